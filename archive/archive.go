@@ -0,0 +1,326 @@
+// Package archive 将邮箱内容导出到长期存储
+//
+// Exporter 可以把邮箱快照（JSON）和每封邮件的 EML 文件写入本地目录、任意
+// io.Writer 或 S3 兼容的对象存储，既可以定时导出，也可以在删除邮箱前导出，
+// 满足对验证类邮件有留存要求的团队。Replay 可以把归档中的邮件重新交给
+// 当前的解析器处理，用于回归验证。
+//
+// 使用示例:
+//   exporter := archive.NewExporter(client, archive.DirSink("/data/mail-archive"), archive.Options{})
+//
+//   // 删除前先归档
+//   err := exporter.DeleteMailbox(ctx, mailbox.Address)
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// 导出格式
+const (
+	FormatJSON = "json" // 每次导出一个邮箱快照（包含全部邮件详情，编码见 Options.Codec）
+	FormatEML  = "eml"  // 每封邮件一个 RFC 5322 格式的 .eml 文件
+)
+
+// Sink 归档写入目标
+type Sink interface {
+	// Put 将 r 的内容写入 key 对应的位置（key 使用 "/" 分隔）
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+}
+
+// WriterFactory 按 key 创建写入器
+type WriterFactory func(ctx context.Context, key string) (io.WriteCloser, error)
+
+// writerSink 基于 WriterFactory 的 Sink
+type writerSink struct {
+	factory WriterFactory
+}
+
+// WriterSink 将 WriterFactory 适配为 Sink
+func WriterSink(factory WriterFactory) Sink {
+	return writerSink{factory: factory}
+}
+
+// Put 实现 Sink 接口
+func (s writerSink) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	w, err := s.factory(ctx, key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// DirSink 写入本地目录（key 中的 "/" 对应子目录）
+//
+// 指向目录之外的 key（如包含 ".."）会被拒绝。
+func DirSink(dir string) Sink {
+	return WriterSink(func(ctx context.Context, key string) (io.WriteCloser, error) {
+		if !filepath.IsLocal(filepath.FromSlash(key)) {
+			return nil, fmt.Errorf("invalid archive key: %q", key)
+		}
+		full := filepath.Join(dir, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+			return nil, err
+		}
+		return os.OpenFile(full, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	})
+}
+
+// ObjectStore S3 兼容对象存储需要具备的方法
+//
+// 以 minio-go 为例：
+//
+//   type minioStore struct {
+//       c      *minio.Client
+//       bucket string
+//   }
+//
+//   func (m minioStore) PutObject(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+//       _, err := m.c.PutObject(ctx, m.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+//       return err
+//   }
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+}
+
+// ObjectSink 将对象存储适配为 Sink
+func ObjectSink(store ObjectStore) Sink {
+	return objectSink{store: store}
+}
+
+type objectSink struct {
+	store ObjectStore
+}
+
+// Put 实现 Sink 接口
+func (s objectSink) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return s.store.PutObject(ctx, key, r, size, contentType)
+}
+
+// Options 导出配置
+type Options struct {
+	Formats     []string // 导出格式（为空表示 JSON + EML）
+	Prefix      string   // key 前缀（如 "campaign-2025/"）
+	Concurrency int      // 同时获取邮件详情的并发数（<= 0 表示 4）
+
+	// Codec 快照的编码（nil 表示带缩进的 JSON），快照文件以编码名称为扩展名。
+	// 大批量归档 HTML 邮件时可以用 mail2sdk.GzipCodec(mail2sdk.JSONCodec) 压缩，
+	// Replay 会按扩展名自动选择解码方式。
+	Codec mail2sdk.Codec
+
+	// OnProgress ExportMailboxes 的进度回调（可选），每导出完一个邮箱调用一次
+	OnProgress mail2sdk.ProgressFunc
+}
+
+// Snapshot 邮箱快照
+type Snapshot struct {
+	Address    string                `json:"email"`       // 邮箱地址
+	ExportedAt time.Time             `json:"exported_at"` // 导出时间
+	Mails      []mail2sdk.MailDetail `json:"mails"`       // 全部邮件详情
+}
+
+// Result 单个邮箱的导出结果
+type Result struct {
+	Address string   // 邮箱地址
+	Mails   int      // 导出的邮件数量
+	Keys    []string // 写入的全部 key
+}
+
+// Exporter 邮箱归档导出器
+type Exporter struct {
+	client *mail2sdk.Client
+	sink   Sink
+	opts   Options
+}
+
+// NewExporter 创建导出器
+func NewExporter(client *mail2sdk.Client, sink Sink, opts Options) *Exporter {
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{FormatJSON, FormatEML}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	return &Exporter{client: client, sink: sink, opts: opts}
+}
+
+// ExportMailbox 导出一个邮箱的全部邮件
+//
+// EML 文件以邮件 ID 命名，重复导出会覆盖同名文件；JSON 快照以导出时间命名，
+// 每次导出都会生成新的快照。
+func (e *Exporter) ExportMailbox(ctx context.Context, address string) (*Result, error) {
+	mails, err := e.client.GetMails(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("list mails failed: %w", err)
+	}
+
+	details, err := e.fetchDetails(ctx, address, mails)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := &Result{Address: address, Mails: len(details)}
+	base := e.opts.Prefix + keySegment(address)
+
+	for _, format := range e.opts.Formats {
+		switch format {
+		case FormatJSON:
+			data, ext, contentType, err := e.encodeSnapshot(&Snapshot{Address: address, ExportedAt: now, Mails: details})
+			if err != nil {
+				return nil, fmt.Errorf("encode snapshot failed: %w", err)
+			}
+			key := path.Join(base, "snapshots", now.Format("20060102T150405.000000000Z")+"."+ext)
+			if err := e.sink.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+				return nil, fmt.Errorf("write %s failed: %w", key, err)
+			}
+			result.Keys = append(result.Keys, key)
+		case FormatEML:
+			for i := range details {
+				data := BuildEML(&details[i])
+				key := path.Join(base, "eml", keySegment(details[i].ID)+".eml")
+				if err := e.sink.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "message/rfc822"); err != nil {
+					return nil, fmt.Errorf("write %s failed: %w", key, err)
+				}
+				result.Keys = append(result.Keys, key)
+			}
+		default:
+			return nil, fmt.Errorf("unknown archive format: %s", format)
+		}
+	}
+
+	return result, nil
+}
+
+// encodeSnapshot 按 Options.Codec 编码快照，返回数据、扩展名和 Content-Type
+func (e *Exporter) encodeSnapshot(snapshot *Snapshot) ([]byte, string, string, error) {
+	if e.opts.Codec == nil {
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		return data, "json", "application/json", err
+	}
+
+	data, err := e.opts.Codec.Marshal(snapshot)
+	name := e.opts.Codec.Name()
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		return data, name, "application/gzip", err
+	case name == "json":
+		return data, name, "application/json", err
+	default:
+		return data, name, "application/octet-stream", err
+	}
+}
+
+// ExportMailboxes 依次导出多个邮箱
+//
+// 单个邮箱导出失败不影响其他邮箱，进度通过 Options.OnProgress 上报。
+// 结果中的条目按 addresses 顺序排列，Key 为邮箱地址。
+func (e *Exporter) ExportMailboxes(ctx context.Context, addresses []string) *mail2sdk.BatchResult[*Result] {
+	// 每个邮箱内部已经并发获取邮件详情，邮箱之间依次导出
+	return mail2sdk.RunBatch(ctx, addresses, 1, e.opts.OnProgress,
+		func(ctx context.Context, item *mail2sdk.BatchItem[*Result]) error {
+			result, err := e.ExportMailbox(ctx, item.Key)
+			item.Value = result
+			return err
+		})
+}
+
+// DeleteMailbox 先导出邮箱再删除，导出失败时不会删除
+func (e *Exporter) DeleteMailbox(ctx context.Context, address string) error {
+	if _, err := e.ExportMailbox(ctx, address); err != nil {
+		return fmt.Errorf("archive before delete failed: %w", err)
+	}
+	return e.client.DeleteMailbox(ctx, address)
+}
+
+// Schedule 按固定间隔导出 addresses 返回的所有邮箱，直到 ctx 被取消
+//
+// 单个邮箱导出失败会通过 onError 上报（可以为 nil），不影响其他邮箱。
+func (e *Exporter) Schedule(ctx context.Context, interval time.Duration, addresses func() []string, onError func(address string, err error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, address := range addresses() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, err := e.ExportMailbox(ctx, address); err != nil && onError != nil {
+				onError(address, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// keySegment 将邮箱地址或邮件 ID 转义为 key 中的一段
+//
+// 这些值来自服务端，PathEscape 不会转义 "."，需要单独处理 "." 和 ".."。
+func keySegment(s string) string {
+	s = url.PathEscape(s)
+	if s == "." || s == ".." {
+		s = strings.Repeat("%2E", len(s))
+	}
+	return s
+}
+
+// fetchDetails 并发获取邮件详情（保持原始顺序）
+func (e *Exporter) fetchDetails(ctx context.Context, address string, mails []mail2sdk.Mail) ([]mail2sdk.MailDetail, error) {
+	details := make([]mail2sdk.MailDetail, len(mails))
+	errs := make([]error, len(mails))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, e.opts.Concurrency)
+	for i, mail := range mails {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, mailID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			detail, err := e.client.GetMailDetail(ctx, address, mailID)
+			if err == nil && detail.Truncated {
+				// 归档需要完整正文
+				detail, err = e.client.GetMailDetailFull(ctx, address, mailID)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("get mail %s failed: %w", mailID, err)
+				return
+			}
+			details[i] = *detail
+		}(i, mail.ID)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
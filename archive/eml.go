@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// BuildEML 将邮件详情转换为 RFC 5322 格式的 EML 内容
+//
+// 同时包含纯文本和 HTML 时生成 multipart/alternative 结构，正文使用
+// quoted-printable 编码，非 ASCII 主题使用 RFC 2047 编码。
+func BuildEML(detail *mail2sdk.MailDetail) []byte {
+	var b bytes.Buffer
+
+	date := detail.ReceivedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	writeHeader(&b, "From", detail.From)
+	writeHeader(&b, "To", strings.Join(detail.To, ", "))
+	writeHeader(&b, "Subject", mime.QEncoding.Encode("utf-8", detail.Subject))
+	writeHeader(&b, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&b, "Message-ID", "<"+detail.ID+"@mail2sdk.archive>")
+	writeHeader(&b, "MIME-Version", "1.0")
+
+	hasText := detail.TextBody != ""
+	hasHTML := detail.HTMLBody != ""
+
+	switch {
+	case hasText && hasHTML:
+		boundary := newBoundary()
+		writeHeader(&b, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		b.WriteString("\r\n")
+		writePart(&b, boundary, "text/plain; charset=utf-8", detail.TextBody)
+		writePart(&b, boundary, "text/html; charset=utf-8", detail.HTMLBody)
+		b.WriteString("--" + boundary + "--\r\n")
+	case hasHTML:
+		writeBody(&b, "text/html; charset=utf-8", detail.HTMLBody)
+	default:
+		writeBody(&b, "text/plain; charset=utf-8", detail.TextBody)
+	}
+
+	return b.Bytes()
+}
+
+// writeHeader 写入一个邮件头（去除值中的换行，防止头注入）
+func writeHeader(b *bytes.Buffer, key, value string) {
+	value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+	b.WriteString(key + ": " + value + "\r\n")
+}
+
+// writeBody 写入单段正文
+func writeBody(b *bytes.Buffer, contentType, body string) {
+	writeHeader(b, "Content-Type", contentType)
+	writeHeader(b, "Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+	writeQP(b, body)
+}
+
+// writePart 写入 multipart 中的一段
+func writePart(b *bytes.Buffer, boundary, contentType, body string) {
+	b.WriteString("--" + boundary + "\r\n")
+	writeBody(b, contentType, body)
+	b.WriteString("\r\n")
+}
+
+// writeQP 以 quoted-printable 编码写入正文
+func writeQP(b *bytes.Buffer, body string) {
+	w := quotedprintable.NewWriter(b)
+	w.Write([]byte(body))
+	w.Close()
+}
+
+// newBoundary 生成随机的 multipart 分隔符
+func newBoundary() string {
+	var buf [12]byte
+	rand.Read(buf[:])
+	return "mail2sdk-" + hex.EncodeToString(buf[:])
+}
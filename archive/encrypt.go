@@ -0,0 +1,103 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// EncryptedSuffix 加密后的归档文件追加的后缀
+const EncryptedSuffix = ".enc"
+
+// encryptedSink 加密内容后写入下层 Sink
+type encryptedSink struct {
+	sink   Sink
+	cipher *mail2sdk.Cipher
+}
+
+// EncryptedSink 使用 AES-GCM 加密归档内容后写入 sink
+//
+// 写入的 key 会追加 EncryptedSuffix，读取时使用相同密钥的 DecryptedSource。
+// 归档的 key（包含邮箱地址）不会被加密。密钥的长度必须为 16、24 或 32 字节。
+//
+// 示例:
+//   sink, err := archive.EncryptedSink(archive.DirSink("/data/mail-archive"), key)
+//   exporter := archive.NewExporter(client, sink, archive.Options{})
+//
+//   // 回放时使用相同的密钥
+//   source, err := archive.DecryptedSource(archive.DirSource("/data/mail-archive"), key)
+func EncryptedSink(sink Sink, key []byte) (Sink, error) {
+	c, err := mail2sdk.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return encryptedSink{sink: sink, cipher: c}, nil
+}
+
+// Put 实现 Sink 接口
+func (s encryptedSink) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	data, err := s.cipher.Seal(plaintext, []byte(key))
+	if err != nil {
+		return err
+	}
+	return s.sink.Put(ctx, key+EncryptedSuffix, bytes.NewReader(data), int64(len(data)), "application/octet-stream")
+}
+
+// decryptedSource 读取 EncryptedSink 写入的归档
+type decryptedSource struct {
+	source Source
+	cipher *mail2sdk.Cipher
+}
+
+// DecryptedSource 读取 EncryptedSink 写入的归档并解密
+//
+// List 只返回加密的归档（去掉 EncryptedSuffix 后的原始 key），可以直接交给 Replay。
+func DecryptedSource(source Source, key []byte) (Source, error) {
+	c, err := mail2sdk.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return decryptedSource{source: source, cipher: c}, nil
+}
+
+// List 实现 Source 接口
+func (s decryptedSource) List(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := s.source.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	plain := keys[:0]
+	for _, key := range keys {
+		if strings.HasSuffix(key, EncryptedSuffix) {
+			plain = append(plain, strings.TrimSuffix(key, EncryptedSuffix))
+		}
+	}
+	return plain, nil
+}
+
+// Open 实现 Source 接口
+func (s decryptedSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := s.source.Open(ctx, key+EncryptedSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.cipher.Open(data, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("open %s failed: %w", key, err)
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
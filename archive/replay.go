@@ -0,0 +1,219 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// Source 归档读取来源
+type Source interface {
+	// List 返回以 prefix 开头的全部 key（使用 "/" 分隔）
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Open 打开 key 对应的内容
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// dirSource 读取 DirSink 写入的本地目录
+type dirSource struct {
+	dir string
+}
+
+// DirSource 读取 DirSink 写入的本地目录
+func DirSource(dir string) Source {
+	return dirSource{dir: dir}
+}
+
+// List 实现 Source 接口
+func (s dirSource) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Open 实现 Source 接口
+func (s dirSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+}
+
+// Filter 回放过滤条件（零值表示回放全部邮件）
+type Filter struct {
+	Prefix    string               // 归档时使用的 key 前缀（与 Options.Prefix 一致）
+	Addresses []string             // 只回放这些邮箱（为空表示全部）
+	Since     time.Time            // 只回放此时间之后接收的邮件
+	Until     time.Time            // 只回放此时间之前接收的邮件
+	Matcher   mail2sdk.MailMatcher // 额外的匹配条件（可以为 nil）
+}
+
+// match 判断邮件是否满足过滤条件
+func (f *Filter) match(detail *mail2sdk.MailDetail) bool {
+	if !f.Since.IsZero() && detail.ReceivedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !detail.ReceivedAt.Before(f.Until) {
+		return false
+	}
+	return f.Matcher == nil || f.Matcher.Match(detail)
+}
+
+// ReplayHandler 处理回放的一封邮件，返回错误时回放停止
+type ReplayHandler func(ctx context.Context, address string, detail *mail2sdk.MailDetail) error
+
+// ReplayStats 回放统计
+type ReplayStats struct {
+	Snapshots int // 读取的快照数量
+	Mails     int // 交给 handler 处理的邮件数量
+	Skipped   int // 被过滤或重复的邮件数量
+}
+
+// Replay 将归档中的邮件重新交给 handler 处理
+//
+// 读取快照（FormatJSON，按扩展名选择解码方式，见 mail2sdk.LookupCodec）中的邮件详情，按 key 顺序
+// （同一邮箱按导出时间）逐个快照回放，同一快照内按接收时间从早到晚。同一封邮件出现在多个
+// 快照中时只回放一次。每次只有一个快照的内容在内存中，归档较大时也不会占用过多内存。
+// 可用于改进解析器后，用历史真实邮件重新验证。
+//
+// 参数:
+//   ctx: 上下文
+//   src: 归档来源（如 DirSource）
+//   filter: 过滤条件
+//   handler: 邮件处理函数
+//
+// 返回:
+//   *ReplayStats: 回放统计
+//   error: 读取归档失败或 handler 返回的错误
+//
+// 示例:
+//   pipeline := mail2sdk.Pipeline{mail2sdk.CodeExtractor(), extractor}
+//   stats, err := archive.Replay(ctx, archive.DirSource("/data/mail-archive"),
+//       archive.Filter{Since: time.Now().AddDate(0, -1, 0)},
+//       func(ctx context.Context, address string, detail *mail2sdk.MailDetail) error {
+//           fields, err := pipeline.Run(detail)
+//           if err != nil || fields["code"] == "" {
+//               log.Printf("%s %s 提取失败: %v", address, detail.ID, err)
+//           }
+//           return nil
+//       })
+func Replay(ctx context.Context, src Source, filter Filter, handler ReplayHandler) (*ReplayStats, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler is required")
+	}
+
+	keys, err := src.List(ctx, filter.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list archive failed: %w", err)
+	}
+
+	addresses := make(map[string]bool, len(filter.Addresses))
+	for _, address := range filter.Addresses {
+		addresses[address] = true
+	}
+
+	stats := &ReplayStats{}
+	seen := make(map[string]bool)
+	var mails []*mail2sdk.MailDetail
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		codec := snapshotCodec(key)
+		if !strings.Contains(key, "/snapshots/") || codec == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		snapshot, err := readSnapshot(ctx, src, key, codec)
+		if err != nil {
+			return stats, err
+		}
+		if len(addresses) > 0 && !addresses[snapshot.Address] {
+			continue
+		}
+		stats.Snapshots++
+
+		mails = mails[:0]
+		for i := range snapshot.Mails {
+			detail := &snapshot.Mails[i]
+			id := snapshot.Address + "\x00" + detail.ID
+			if seen[id] || !filter.match(detail) {
+				stats.Skipped++
+				continue
+			}
+			seen[id] = true
+			mails = append(mails, detail)
+		}
+
+		sort.SliceStable(mails, func(i, j int) bool {
+			return mails[i].ReceivedAt.Before(mails[j].ReceivedAt)
+		})
+
+		for _, detail := range mails {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+			if err := handler(ctx, snapshot.Address, detail); err != nil {
+				return stats, err
+			}
+			stats.Mails++
+		}
+	}
+
+	return stats, nil
+}
+
+// snapshotCodec 按快照文件的扩展名（如 ".json"、".gob.gz"）选择编码，无法识别时返回 nil
+func snapshotCodec(key string) mail2sdk.Codec {
+	name := strings.TrimPrefix(path.Ext(key), ".")
+	if name == "gz" {
+		name = strings.TrimPrefix(path.Ext(strings.TrimSuffix(key, ".gz")), ".") + ".gz"
+	}
+	return mail2sdk.LookupCodec(name)
+}
+
+// readSnapshot 读取并解码一个快照
+func readSnapshot(ctx context.Context, src Source, key string, codec mail2sdk.Codec) (*Snapshot, error) {
+	r, err := src.Open(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("open %s failed: %w", key, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", key, err)
+	}
+
+	var snapshot Snapshot
+	if err := codec.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parse %s failed: %w", key, err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,303 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrAttachmentRejected 表示附件未通过 AttachmentPolicy 检查
+var ErrAttachmentRejected = errors.New("attachment rejected")
+
+// 附件下载的默认大小上限（AttachmentPolicy.MaxSize 未设置时使用）
+const defaultMaxAttachmentSize = 25 << 20
+
+// 按文件扩展名判断的可执行文件类型
+var executableExtensions = map[string]bool{
+	".exe": true, ".dll": true, ".scr": true, ".msi": true, ".com": true,
+	".bat": true, ".cmd": true, ".ps1": true, ".vbs": true, ".js": true,
+	".jar": true, ".sh": true, ".app": true, ".apk": true, ".lnk": true,
+}
+
+// 按文件头判断的可执行文件类型
+var executableMagic = []struct {
+	prefix      []byte
+	contentType string
+}{
+	{[]byte("MZ"), "application/x-msdownload"},
+	{[]byte("\x7fELF"), "application/x-executable"},
+	{[]byte("\xfe\xed\xfa\xce"), "application/x-mach-binary"},
+	{[]byte("\xfe\xed\xfa\xcf"), "application/x-mach-binary"},
+	{[]byte("\xce\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("\xcf\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("#!"), "text/x-shellscript"},
+}
+
+// 实际为 zip 格式的常见文档类型
+var zipBasedTypes = map[string]bool{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         true,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": true,
+	"application/java-archive":                true,
+	"application/epub+zip":                    true,
+	"application/vnd.android.package-archive": true,
+}
+
+// SniffContentType 根据文件头判断附件的实际类型
+//
+// 在 http.DetectContentType 的基础上增加了 Windows PE、ELF、Mach-O 和脚本文件的识别。
+// 无法识别时返回 "application/octet-stream"。
+func SniffContentType(data []byte) string {
+	for _, m := range executableMagic {
+		if bytes.HasPrefix(data, m.prefix) {
+			return m.contentType
+		}
+	}
+	return http.DetectContentType(data)
+}
+
+// isExecutableType 判断 SniffContentType 返回的类型是否为可执行文件
+func isExecutableType(contentType string) bool {
+	for _, m := range executableMagic {
+		if m.contentType == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType 去掉 MIME 类型中的参数并转为小写
+func mediaType(contentType string) string {
+	if t, _, err := mime.ParseMediaType(contentType); err == nil {
+		return t
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// contentTypeMismatch 判断声明的类型与实际类型是否明显不符
+//
+// 只在双方都是具体的二进制类型时判断为不符：文本类格式（csv、json 等）的
+// 嗅探结果都是 text/plain，未声明或声明为 octet-stream 的附件也不视为不符。
+func contentTypeMismatch(declared, sniffed string) bool {
+	declared, sniffed = mediaType(declared), mediaType(sniffed)
+	switch {
+	case declared == "" || declared == "application/octet-stream":
+		return false
+	case sniffed == "application/octet-stream" || strings.HasPrefix(sniffed, "text/"):
+		// 嗅探为文本的脚本文件也可能伪装成其他类型
+		return sniffed == "text/x-shellscript"
+	case sniffed == "application/zip" && zipBasedTypes[declared]:
+		return false
+	}
+	return declared != sniffed
+}
+
+// AttachmentCheck 附件检查结果
+type AttachmentCheck struct {
+	Attachment Attachment // 附件元数据
+	Declared   string     // 邮件中声明的类型
+	Sniffed    string     // 根据文件头判断的实际类型
+	Mismatch   bool       // 声明类型与实际类型不符
+	Executable bool       // 按文件头或扩展名判断为可执行文件
+}
+
+// AttachmentPolicy 附件安全策略
+//
+// 用于在把附件保存到磁盘前做基本的安全检查。零值策略只检查大小（25 MB）。
+type AttachmentPolicy struct {
+	RejectExecutables bool     // 拒绝可执行文件（按文件头和扩展名判断）
+	RejectMismatch    bool     // 拒绝声明类型与实际类型不符的附件
+	MaxSize           int64    // 附件大小上限（<= 0 表示 25 MB）
+	AllowedTypes      []string // 允许的实际类型（为空表示不限制，支持 "image/*" 写法）
+
+	// Scan 自定义扫描（可选），如接入杀毒引擎；返回错误时拒绝该附件
+	Scan func(ctx context.Context, check *AttachmentCheck, data []byte) error
+}
+
+// maxSize 返回生效的大小上限
+func (p AttachmentPolicy) maxSize() int64 {
+	if p.MaxSize <= 0 {
+		return defaultMaxAttachmentSize
+	}
+	return p.MaxSize
+}
+
+// allows 判断实际类型是否在允许列表中
+func (p AttachmentPolicy) allows(sniffed string) bool {
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	sniffed = mediaType(sniffed)
+	for _, t := range p.AllowedTypes {
+		t = mediaType(t)
+		if t == sniffed || (strings.HasSuffix(t, "/*") && strings.HasPrefix(sniffed, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 按策略检查附件内容
+//
+// 返回检查结果；附件被拒绝时同时返回 ErrAttachmentRejected（可用 errors.Is 判断）。
+func (p AttachmentPolicy) Check(ctx context.Context, att Attachment, data []byte) (*AttachmentCheck, error) {
+	check := &AttachmentCheck{
+		Attachment: att,
+		Declared:   att.ContentType,
+		Sniffed:    SniffContentType(data),
+	}
+	check.Mismatch = contentTypeMismatch(check.Declared, check.Sniffed)
+	check.Executable = isExecutableType(check.Sniffed) ||
+		executableExtensions[strings.ToLower(filepath.Ext(att.Filename))]
+
+	reject := func(reason string) (*AttachmentCheck, error) {
+		return check, fmt.Errorf("%w: %s: %s", ErrAttachmentRejected, att.Filename, reason)
+	}
+
+	switch {
+	case int64(len(data)) > p.maxSize():
+		return reject(fmt.Sprintf("size %d exceeds limit %d", len(data), p.maxSize()))
+	case p.RejectExecutables && check.Executable:
+		return reject("executable content (" + check.Sniffed + ")")
+	case p.RejectMismatch && check.Mismatch:
+		return reject(fmt.Sprintf("declared %s but content is %s", check.Declared, check.Sniffed))
+	case !p.allows(check.Sniffed):
+		return reject("content type " + check.Sniffed + " is not allowed")
+	}
+
+	if p.Scan != nil {
+		if err := p.Scan(ctx, check, data); err != nil {
+			return reject(err.Error())
+		}
+	}
+	return check, nil
+}
+
+// DownloadAttachment 下载附件内容
+//
+// 服务端不支持附件下载时返回 ErrNotSupportedByServer。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   mailID: 邮件 ID
+//   att: 附件（来自 MailDetail.Attachments，需要包含 ID）
+//   maxSize: 大小上限，超过时返回错误（<= 0 表示 25 MB）
+//
+// 返回:
+//   []byte: 附件内容
+//   error: 错误信息
+func (c *Client) DownloadAttachment(ctx context.Context, address, mailID string, att Attachment, maxSize int64) ([]byte, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+	escapedID, err := escapePathSegment("mailID", mailID)
+	if err != nil {
+		return nil, err
+	}
+	escapedAtt, err := escapePathSegment("attachment id", att.ID)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize <= 0 {
+		maxSize = defaultMaxAttachmentSize
+	}
+	if att.Size > maxSize {
+		return nil, fmt.Errorf("attachment %s is too large (%d bytes)", att.Filename, att.Size)
+	}
+
+	if err := c.requireFeature(ctx, FeatureAttachments); err != nil {
+		return nil, err
+	}
+
+	path := "/api/mailbox/" + escaped + "/mails/" + escapedID + "/attachments/" + escapedAtt
+	req, err := c.newRequest(withMailboxRead(ctx), "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, c.redactError(err)
+	}
+	defer resp.Body.Close()
+	c.observeRateLimit(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: c.redact(string(body))}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read attachment failed: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("attachment %s is too large (more than %d bytes)", att.Filename, maxSize)
+	}
+	return data, nil
+}
+
+// SaveAttachment 下载附件，按策略检查后保存到目录中
+//
+// 文件名取自附件元数据，包含路径分隔符等不安全字符的文件名会被拒绝；
+// 同名文件已存在时返回错误而不是覆盖。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   mailID: 邮件 ID
+//   att: 附件（来自 MailDetail.Attachments）
+//   dir: 保存目录
+//   policy: 安全策略
+//
+// 返回:
+//   string: 保存的文件路径
+//   *AttachmentCheck: 检查结果（下载失败时为 nil）
+//   error: 错误信息（策略拒绝时为 ErrAttachmentRejected）
+//
+// 示例:
+//   policy := mail2sdk.AttachmentPolicy{RejectExecutables: true, RejectMismatch: true, MaxSize: 5 << 20}
+//   for _, att := range detail.Attachments {
+//       path, _, err := client.SaveAttachment(ctx, address, detail.ID, att, "./downloads", policy)
+//       if errors.Is(err, mail2sdk.ErrAttachmentRejected) {
+//           log.Printf("跳过可疑附件: %v", err)
+//           continue
+//       }
+//   }
+func (c *Client) SaveAttachment(ctx context.Context, address, mailID string, att Attachment, dir string, policy AttachmentPolicy) (string, *AttachmentCheck, error) {
+	if err := validatePathSegment("filename", att.Filename); err != nil || att.Filename == "" {
+		return "", nil, fmt.Errorf("%w: %q: unsafe filename", ErrAttachmentRejected, att.Filename)
+	}
+
+	data, err := c.DownloadAttachment(ctx, address, mailID, att, policy.maxSize())
+	if err != nil {
+		return "", nil, err
+	}
+
+	check, err := policy.Check(ctx, att, data)
+	if err != nil {
+		return "", check, err
+	}
+
+	path := filepath.Join(dir, att.Filename)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", check, fmt.Errorf("create file failed: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", check, fmt.Errorf("write file failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", check, fmt.Errorf("write file failed: %w", err)
+	}
+	return path, check, nil
+}
@@ -0,0 +1,226 @@
+package mail2sdk
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AuditRecord 一条审计记录（对应一次变更类 API 调用）
+type AuditRecord struct {
+	Time       time.Time `json:"time"`             // 调用开始时间
+	Operation  string    `json:"operation"`        // 操作名称（如 create_mailbox、delete_mailbox）
+	Actor      string    `json:"actor"`            // 调用方标识（默认为 API 密钥指纹）
+	Target     string    `json:"target,omitempty"` // 操作对象（通常为邮箱地址）
+	Method     string    `json:"method"`           // HTTP 方法
+	Path       string    `json:"path"`             // 请求路径
+	Success    bool      `json:"success"`          // 是否成功
+	Error      string    `json:"error,omitempty"`  // 失败原因
+	DurationMs int64     `json:"duration_ms"`      // 耗时（毫秒，包含重试）
+
+	Attrs map[string]string `json:"attrs,omitempty"` // 调用方通过 WithAttributes 附加的属性
+}
+
+// AuditSink 审计记录写入目标
+type AuditSink interface {
+	WriteAudit(ctx context.Context, record AuditRecord) error
+}
+
+// WithAuditLog 启用审计日志
+//
+// 启用后所有变更类调用（创建、删除、设置密码、分享等非 GET 请求）都会生成一条
+// AuditRecord 写入 sink。审计写入失败不会影响 API 调用本身。
+//
+// 示例:
+//   f, _ := os.OpenFile("audit.jsonl", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithAuditLog(mail2sdk.NewAuditWriter(f)))
+func WithAuditLog(sink AuditSink) Option {
+	return func(c *Client) {
+		c.auditSink = sink
+	}
+}
+
+// WithAuditActor 设置审计记录中的调用方标识（默认为 API 密钥指纹）
+//
+// 示例:
+//   mail2sdk.WithAuditActor("ci-signup-tests")
+func WithAuditActor(actor string) Option {
+	return func(c *Client) {
+		c.auditActor = actor
+	}
+}
+
+// auditWriter 以 JSON Lines 格式写入 io.Writer
+type auditWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditWriter 创建以 JSON Lines 格式写入 w 的审计目标（并发安全）
+func NewAuditWriter(w io.Writer) AuditSink {
+	return &auditWriter{w: w}
+}
+
+// WriteAudit 实现 AuditSink 接口
+func (a *auditWriter) WriteAudit(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.w.Write(data)
+	return err
+}
+
+// 审计记录在 Store 中的键前缀
+const auditKeyPrefix = "audit/"
+
+// storeAuditSink 写入 Store 的审计目标
+type storeAuditSink struct {
+	store Store
+	seq   uint64
+}
+
+// NewStoreAuditSink 创建写入 Store 的审计目标
+//
+// 记录按时间顺序保存在 "audit/" 前缀下，可通过 ExportAuditLog 导出。
+func NewStoreAuditSink(store Store) AuditSink {
+	return &storeAuditSink{store: store}
+}
+
+// WriteAudit 实现 AuditSink 接口
+func (s *storeAuditSink) WriteAudit(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	// 时间前缀保证字典序即时间序，序号避免同一纳秒内的键冲突
+	seq := atomic.AddUint64(&s.seq, 1)
+	key := fmt.Sprintf("%s%s-%08d", auditKeyPrefix, record.Time.UTC().Format("20060102T150405.000000000Z"), seq)
+	return s.store.Put(ctx, key, data)
+}
+
+// ExportAuditLog 将 Store 中的审计记录按时间顺序以 JSON Lines 格式写入 w
+//
+// 示例:
+//   err := mail2sdk.ExportAuditLog(ctx, store, os.Stdout)
+func ExportAuditLog(ctx context.Context, store Store, w io.Writer) error {
+	keys, err := store.List(ctx, auditKeyPrefix)
+	if err != nil {
+		return fmt.Errorf("list audit records failed: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, key := range keys {
+		data, err := store.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("read audit record %s failed: %w", key, err)
+		}
+		bw.Write(data)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// isMutating 判断请求是否为变更类请求
+func isMutating(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
+// auditActorName 返回审计记录中的调用方标识
+func (c *Client) auditActorName() string {
+	if c.auditActor != "" {
+		return c.auditActor
+	}
+	s := c.settings()
+	switch {
+	case s.apiKey != "":
+		return "key:" + fingerprint(s.apiKey)
+	case s.mailboxToken != "":
+		return "token:" + fingerprint(s.mailboxToken)
+	default:
+		return "anonymous"
+	}
+}
+
+// fingerprint 返回密钥的短指纹（不可逆，可安全写入日志）
+func fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+// recordAudit 为一次变更类调用写入审计记录
+func (c *Client) recordAudit(ctx context.Context, method, path string, start time.Time, err error) {
+	if c.auditSink == nil || !isMutating(method) {
+		return
+	}
+
+	operation, target := describeOperation(method, path)
+	record := AuditRecord{
+		Time:       start,
+		Operation:  operation,
+		Actor:      c.auditActorName(),
+		Target:     target,
+		Method:     method,
+		Path:       path,
+		Success:    err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+		Attrs:      Attributes(ctx),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	// 审计失败不影响业务调用
+	c.auditSink.WriteAudit(context.WithoutCancel(ctx), record)
+}
+
+// describeOperation 根据请求方法和路径推断操作名称和操作对象
+func describeOperation(method, path string) (operation, target string) {
+	p := path
+	if idx := strings.IndexByte(p, '?'); idx >= 0 {
+		p = p[:idx]
+	}
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+
+	// /api/mailbox/{address}/...
+	if len(segments) >= 3 && segments[0] == "api" && segments[1] == "mailbox" {
+		target, _ = url.PathUnescape(segments[2])
+	}
+	suffix := ""
+	if len(segments) > 3 {
+		suffix = strings.Join(segments[3:], "/")
+	}
+
+	switch {
+	case method == http.MethodPost && p == "/api/mailbox":
+		return "create_mailbox", ""
+	case method == http.MethodDelete && len(segments) == 3 && segments[1] == "mailbox":
+		return "delete_mailbox", target
+	case method == http.MethodPut && suffix == "password":
+		return "set_mailbox_password", target
+	case method == http.MethodPost && suffix == "password/rotate":
+		return "rotate_mailbox_password", target
+	case method == http.MethodPost && suffix == "share":
+		return "share_mailbox", target
+	case method == http.MethodPost && len(segments) == 6 && segments[3] == "mails" && segments[5] == "trash":
+		return "trash_mail", target
+	case method == http.MethodPost && len(segments) == 6 && segments[3] == "trash" && segments[5] == "restore":
+		return "restore_mail", target
+	default:
+		return strings.ToLower(method) + " " + p, target
+	}
+}
@@ -0,0 +1,53 @@
+package mail2sdk
+
+import "strings"
+
+// WithBasePath 设置 API 的路径前缀
+//
+// 服务部署在反向代理的子路径下（如 https://host/mailapi）时使用，也可以直接把前缀写在
+// baseURL 中。前缀会拼接在 baseURL 之后、接口路径之前，多余或缺少的 "/" 会被规范化。
+//
+// 示例:
+//   client := mail2sdk.NewClient("https://host/", apiKey, mail2sdk.WithBasePath("/mailapi/"))
+//   // 请求地址为 https://host/mailapi/api/domains
+func WithBasePath(prefix string) Option {
+	return func(c *Client) {
+		c.basePath = prefix
+	}
+}
+
+// normalizeBaseURL 去掉首尾空白和末尾的 "/"，合并路径中重复的 "/"
+func normalizeBaseURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	scheme := ""
+	if i := strings.Index(raw, "://"); i >= 0 {
+		scheme, raw = raw[:i+3], raw[i+3:]
+	}
+	return scheme + strings.TrimRight(collapseSlashes(raw), "/")
+}
+
+// normalizeBasePath 将路径前缀规范化为 "/prefix" 形式（空前缀返回空字符串）
+func normalizeBasePath(prefix string) string {
+	return strings.TrimRight(collapseSlashes("/"+strings.TrimSpace(prefix)), "/")
+}
+
+// collapseSlashes 合并连续的 "/"
+func collapseSlashes(s string) string {
+	for strings.Contains(s, "//") {
+		s = strings.ReplaceAll(s, "//", "/")
+	}
+	return s
+}
+
+// url 返回接口路径对应的完整地址（保留查询字符串，设置了 WithEndpoints 时选择其中一个地址）
+func (c *Client) url(path string) string {
+	query := ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, query = path[:i], path[i:]
+	}
+	// 常见情况下路径已经规范，只需一次拼接
+	if strings.HasPrefix(path, "/") && !strings.Contains(path, "//") {
+		return c.requestBase() + path + query
+	}
+	return c.requestBase() + collapseSlashes("/"+path) + query
+}
@@ -0,0 +1,117 @@
+package mail2sdk
+
+import (
+	"context"
+)
+
+// 批量操作默认并发数
+const defaultBatchConcurrency = 8
+
+// CreateMailboxesOptions 批量创建邮箱的配置
+type CreateMailboxesOptions struct {
+	Mode        int      // 邮箱生成模式（ModeAuto/ModeRandom/ModeChinese/ModeEnglish）
+	Domains     []string // 候选域名（为空表示使用全部可用域名）
+	Blacklist   []string // 域名黑名单
+	Concurrency int      // 最大并发请求数（<= 0 表示使用默认值 8）
+
+	// OnProgress 进度回调（可选），每创建完一个邮箱调用一次，
+	// Item 为新邮箱的地址（创建失败时为空）
+	OnProgress ProgressFunc
+}
+
+// CreateMailboxes 并发创建 n 个邮箱
+//
+// 单个邮箱创建失败不会影响其他邮箱。结果中的条目按请求顺序排列，
+// 创建成功的条目 Key 为新邮箱的地址。
+//
+// 参数:
+//   ctx: 上下文（取消后未开始的创建会直接失败）
+//   n: 邮箱数量
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   *BatchResult[*Mailbox]: 每个邮箱的创建结果
+//
+// 示例:
+//   result := client.CreateMailboxes(ctx, 100, &mail2sdk.CreateMailboxesOptions{
+//       Mode: mail2sdk.ModeRandom,
+//       OnProgress: func(p mail2sdk.Progress) {
+//           fmt.Printf("\r%d/%d（失败 %d）", p.Done, p.Total, p.Errors)
+//       },
+//   })
+//   mailboxes := result.Successes()
+func (c *Client) CreateMailboxes(ctx context.Context, n int, opts *CreateMailboxesOptions) *BatchResult[*Mailbox] {
+	var o CreateMailboxesOptions
+	if opts != nil {
+		o = *opts
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	return RunBatch(ctx, make([]string, n), o.Concurrency, o.OnProgress,
+		func(ctx context.Context, item *BatchItem[*Mailbox]) error {
+			mailbox, err := c.CreateMailboxWithDomains(ctx, o.Mode, o.Domains, o.Blacklist)
+			if err != nil {
+				return err
+			}
+			item.Key, item.Value = mailbox.Address, mailbox
+			return nil
+		})
+}
+
+// ExtractCodesOptions 批量提取验证码的配置
+type ExtractCodesOptions struct {
+	Concurrency int // 最大并发请求数（<= 0 表示使用默认值 8）
+	MaxMails    int // 每个邮箱最多检查的邮件数量（0 表示使用默认值 5）
+
+	// OnProgress 进度回调（可选），每处理完一个邮箱调用一次
+	OnProgress ProgressFunc
+}
+
+// ExtractCodes 并发提取多个邮箱的验证码
+//
+// 使用有界并发逐个调用 ExtractCode，单个邮箱失败不会影响其他邮箱，
+// 错误记录在对应条目的 Err 中。重复的地址只会请求一次。
+//
+// 参数:
+//   ctx: 上下文（取消后未开始的邮箱会直接返回 ctx.Err()）
+//   addresses: 邮箱地址列表
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   *BatchResult[*CodeResult]: 每个邮箱的提取结果（Key 为邮箱地址）
+//
+// 示例:
+//   result := client.ExtractCodes(ctx, addresses, &mail2sdk.ExtractCodesOptions{Concurrency: 16})
+//   for _, item := range result.Items {
+//       if item.Err == nil && item.Value.Found {
+//           fmt.Println(item.Key, item.Value.Code)
+//       }
+//   }
+func (c *Client) ExtractCodes(ctx context.Context, addresses []string, opts *ExtractCodesOptions) *BatchResult[*CodeResult] {
+	var o ExtractCodesOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return RunBatch(ctx, uniqueAddresses(addresses), o.Concurrency, o.OnProgress,
+		func(ctx context.Context, item *BatchItem[*CodeResult]) error {
+			result, err := c.ExtractCode(ctx, item.Key, o.MaxMails)
+			item.Value = result
+			return err
+		})
+}
+
+// uniqueAddresses 去除重复的地址（保持原始顺序）
+func uniqueAddresses(addresses []string) []string {
+	seen := make(map[string]bool, len(addresses))
+	unique := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+	return unique
+}
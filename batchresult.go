@@ -0,0 +1,152 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchItem 批量操作中单个条目的结果
+type BatchItem[T any] struct {
+	Key      string        // 条目标识（如邮箱地址）
+	Value    T             // 结果（Err 不为 nil 时为零值或部分结果）
+	Err      error         // 该条目的错误
+	Retries  int           // 该条目的请求重试次数
+	Duration time.Duration // 该条目的耗时（未开始时为 0）
+}
+
+// BatchResult 批量操作的结果
+//
+// Items 与输入一一对应，成功和失败的条目都在其中，调用方不再需要按下标
+// 对齐结果和错误两个切片。
+type BatchResult[T any] struct {
+	Items    []BatchItem[T] // 每个条目的结果（按输入顺序）
+	Duration time.Duration  // 整个批量操作的耗时
+}
+
+// Successes 返回全部成功条目的结果（按输入顺序）
+func (r *BatchResult[T]) Successes() []T {
+	values := make([]T, 0, len(r.Items))
+	for _, item := range r.Items {
+		if item.Err == nil {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+// Failed 返回全部失败的条目（按输入顺序）
+func (r *BatchResult[T]) Failed() []BatchItem[T] {
+	var failed []BatchItem[T]
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Err 返回全部失败原因的合并（全部成功时为 nil）
+func (r *BatchResult[T]) Err() error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Err != nil {
+			if item.Key != "" {
+				errs = append(errs, fmt.Errorf("%s: %w", item.Key, item.Err))
+			} else {
+				errs = append(errs, item.Err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Retries 返回全部条目的重试次数之和
+func (r *BatchResult[T]) Retries() int {
+	total := 0
+	for _, item := range r.Items {
+		total += item.Retries
+	}
+	return total
+}
+
+// RunBatch 以有界并发对每个 key 执行 fn，返回结构化的批量结果
+//
+// fn 通过 item 写入结果（可以修改 item.Key，如创建邮箱后填入地址），返回的错误
+// 记录在 item.Err 中；条目的重试次数和耗时由 RunBatch 统计。ctx 取消后未开始的
+// 条目直接以 ctx.Err() 失败。内置的批量操作都基于 RunBatch 实现，也可以用它
+// 在 SDK 之上实现自己的批量操作。
+//
+// 参数:
+//   ctx: 上下文
+//   keys: 条目标识列表
+//   concurrency: 最大并发数（<= 0 表示 8）
+//   onProgress: 进度回调（可以为 nil）
+//   fn: 处理单个条目的函数
+//
+// 示例:
+//   result := mail2sdk.RunBatch(ctx, addresses, 4, nil,
+//       func(ctx context.Context, item *mail2sdk.BatchItem[int]) error {
+//           mails, err := client.GetMails(ctx, item.Key)
+//           item.Value = len(mails)
+//           return err
+//       })
+//   fmt.Println(len(result.Successes()), result.Err())
+func RunBatch[T any](ctx context.Context, keys []string, concurrency int, onProgress ProgressFunc, fn func(ctx context.Context, item *BatchItem[T]) error) *BatchResult[T] {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	start := time.Now()
+	result := &BatchResult[T]{Items: make([]BatchItem[T], len(keys))}
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+		progress = NewProgressTracker(len(keys), onProgress)
+	)
+
+	for i, key := range keys {
+		item := &result.Items[i]
+		item.Key = key
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			item.Err = ctx.Err()
+			progress.Step(item.Key, item.Err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(item *BatchItem[T]) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var retries int64
+			itemStart := time.Now()
+			item.Err = fn(withRetryCounter(ctx, &retries), item)
+			item.Duration = time.Since(itemStart)
+			item.Retries = int(atomic.LoadInt64(&retries))
+			progress.Step(item.Key, item.Err)
+		}(item)
+	}
+
+	wg.Wait()
+	result.Duration = time.Since(start)
+	return result
+}
+
+// withRetryCounter 返回一个上下文，该上下文中的请求每次重试都会计入 counter
+func withRetryCounter(ctx context.Context, counter *int64) context.Context {
+	return context.WithValue(ctx, ctxKeyRetries, counter)
+}
+
+// countRetry 将一次重试计入上下文中的计数器
+func countRetry(ctx context.Context) {
+	if counter, ok := ctx.Value(ctxKeyRetries).(*int64); ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
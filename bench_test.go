@@ -0,0 +1,189 @@
+package mail2sdk_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// 请求热路径的每次调用分配次数（go test -run xxx -bench . -benchmem，内存传输层）
+//
+//   基准                     优化前 allocs/op    当前 allocs/op    其中 net/http 与传输层
+//   BenchmarkCreateMailbox   57                  33                16
+//   BenchmarkGetMails        64                  32                13
+//   BenchmarkGetMailDetail   50                  26                13
+//   BenchmarkExtractCode     49                  26                13
+//
+// "优化前"为引入预构建请求头、缓冲区复用和单次解析之前的实现。net/http 与传输层的
+// 分配（见 transportAllocs）不受 SDK 控制，扣除后 SDK 自身的分配为 41/51/37/36 次，
+// allocBudgets 中的上限为其一半，TestRequestAllocations 会在分配次数回退时失败。
+var allocBudgets = map[string]float64{
+	"CreateMailbox": 20,
+	"GetMails":      25,
+	"GetMailDetail": 18,
+	"ExtractCode":   18,
+}
+
+// benchTransport 在内存中返回固定响应的传输层，避免把 HTTP 服务端的分配计入结果
+type benchTransport struct {
+	create, list, detail, code []byte
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *benchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	var body []byte
+	switch path := req.URL.Path; {
+	case req.Method == http.MethodPost:
+		body = t.create
+	case strings.HasSuffix(path, "/mails"):
+		body = t.list
+	case strings.HasSuffix(path, "/code"):
+		body = t.code
+	default:
+		body = t.detail
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// newBenchClient 创建使用内存传输层的客户端
+func newBenchClient() *mail2sdk.Client {
+	rt := newBenchTransport()
+	return mail2sdk.NewClient("https://mail.example.com", "bench-key", mail2sdk.WithTransport(rt))
+}
+
+// newBenchTransport 创建返回固定响应的内存传输层
+func newBenchTransport() *benchTransport {
+	var list strings.Builder
+	list.WriteString(`{"code":0,"data":{"count":20,"mails":[`)
+	for i := 0; i < 20; i++ {
+		if i > 0 {
+			list.WriteByte(',')
+		}
+		list.WriteString(`{"id":"m` + string(rune('a'+i)) + `","from":"noreply@example.com","subject":"您的验证码","received_at":"2025-11-07T10:00:00Z"}`)
+	}
+	list.WriteString(`]}}`)
+
+	return &benchTransport{
+		create: []byte(`{"code":0,"data":{"email":"user@example.com","username":"user","domain":"example.com",` +
+			`"expires_at":"2025-11-07T11:00:00Z","created_at":"2025-11-07T10:00:00Z"}}`),
+		list: []byte(list.String()),
+		detail: []byte(`{"code":0,"data":{"id":"ma","from":"noreply@example.com","to":["user@example.com"],` +
+			`"subject":"您的验证码","text_content":"您的验证码是 123456，10 分钟内有效。",` +
+			`"html_content":"<p>您的验证码是 <b>123456</b>，10 分钟内有效。</p>","received_at":"2025-11-07T10:00:00Z"}}`),
+		code: []byte(`{"code":0,"data":{"code":"123456","found":true,"all_codes":["123456"],"checked_mails":1,"latest_mail_id":"ma"}}`),
+	}
+}
+
+// benchCall 被测量的 SDK 调用及其发出的请求
+type benchCall struct {
+	method, path string
+	call         func(ctx context.Context, c *mail2sdk.Client) error
+}
+
+// benchCalls 被测量的 SDK 调用
+var benchCalls = map[string]benchCall{
+	"CreateMailbox": {"POST", "/api/mailbox", func(ctx context.Context, c *mail2sdk.Client) error {
+		_, err := c.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+		return err
+	}},
+	"GetMails": {"GET", "/api/mailbox/user@example.com/mails", func(ctx context.Context, c *mail2sdk.Client) error {
+		_, err := c.GetMails(ctx, "user@example.com")
+		return err
+	}},
+	"GetMailDetail": {"GET", "/api/mailbox/user@example.com/mails/ma", func(ctx context.Context, c *mail2sdk.Client) error {
+		_, err := c.GetMailDetail(ctx, "user@example.com", "ma")
+		return err
+	}},
+	"ExtractCode": {"GET", "/api/mailbox/user@example.com/code?max_mails=5", func(ctx context.Context, c *mail2sdk.Client) error {
+		_, err := c.ExtractCode(ctx, "user@example.com", 5)
+		return err
+	}},
+}
+
+func benchmarkCall(b *testing.B, name string) {
+	client := newBenchClient()
+	call := benchCalls[name].call
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := call(ctx, client); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateMailbox(b *testing.B) { benchmarkCall(b, "CreateMailbox") }
+func BenchmarkGetMails(b *testing.B)      { benchmarkCall(b, "GetMails") }
+func BenchmarkGetMailDetail(b *testing.B) { benchmarkCall(b, "GetMailDetail") }
+func BenchmarkExtractCode(b *testing.B)   { benchmarkCall(b, "ExtractCode") }
+
+// transportAllocs 直接用 net/http 发送同样请求的分配次数（即内存传输层和 net/http 自身的开销）
+func transportAllocs(t *testing.T, method, path string) float64 {
+	client := &http.Client{Transport: newBenchTransport()}
+	ctx := context.Background()
+	url := "https://mail.example.com" + path
+	body := []byte(`{"mode":"random"}` + "\n")
+
+	return testing.AllocsPerRun(200, func() {
+		var reqBody io.Reader
+		if method == http.MethodPost {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	})
+}
+
+func TestRequestAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budget in short mode")
+	}
+	if raceEnabled {
+		t.Skip("skipping allocation budget with the race detector")
+	}
+
+	client := newBenchClient()
+	ctx := context.Background()
+	for name, budget := range allocBudgets {
+		bc := benchCalls[name]
+		if err := bc.call(ctx, client); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		allocs := testing.AllocsPerRun(200, func() {
+			if err := bc.call(ctx, client); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		})
+		floor := transportAllocs(t, bc.method, bc.path)
+		t.Logf("%s: %.0f allocs/op, %.0f in net/http and the transport", name, allocs, floor)
+		if allocs-floor > budget {
+			t.Errorf("%s: %.0f allocs/op in the SDK, budget %.0f", name, allocs-floor, budget)
+		}
+	}
+}
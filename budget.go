@@ -0,0 +1,153 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded 表示操作预算已经用完
+//
+// 可以通过 errors.Is 判断：
+//
+//   if errors.Is(err, mail2sdk.ErrBudgetExceeded) {
+//       // 返回降级结果
+//   }
+var ErrBudgetExceeded = errors.New("operation budget exceeded")
+
+// Budget 一组 SDK 调用共享的耗时预算
+//
+// 与 context 的截止时间不同，Budget 只累计 SDK 调用本身（包括重试等待和 WaitForCode、
+// Watch 等方法的轮询间隔）花费的时间，调用之间业务代码的耗时不计入。预算用完后，
+// 携带该预算的调用会立即返回 ErrBudgetExceeded，进行中的请求也会在预算耗尽时取消，
+// 避免一个缓慢的邮箱拖垮交互式请求的 SLA。
+//
+// Budget 可以在多个 goroutine 中共享。计入预算的是至少有一个调用在进行中的墙钟时间：
+// 并发的调用共享同一个截止时间，不会各自按剩余预算计时而超支。
+//
+// 示例:
+//   budget := mail2sdk.NewBudget(3 * time.Second)
+//   ctx = mail2sdk.WithBudget(ctx, budget)
+//
+//   mailbox, err := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+//   // ...
+//   result, err := client.ExtractCode(ctx, mailbox.Address, 5)
+//   if errors.Is(err, mail2sdk.ErrBudgetExceeded) {
+//       log.Printf("已耗时 %s，放弃等待验证码", budget.Spent())
+//   }
+type Budget struct {
+	limit time.Duration
+
+	mu     sync.Mutex
+	spent  time.Duration // 已结束的计时区间累计的时间
+	active int           // 进行中的调用数
+	since  time.Time     // 当前计时区间的开始时间（active > 0 时有效）
+}
+
+// NewBudget 创建耗时预算
+func NewBudget(limit time.Duration) *Budget {
+	return &Budget{limit: limit}
+}
+
+// Limit 返回预算总额
+func (b *Budget) Limit() time.Duration {
+	return b.limit
+}
+
+// Spent 返回已花费的时间（包括进行中的调用已经花费的时间）
+func (b *Budget) Spent() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spentAt(time.Now())
+}
+
+// Remaining 返回剩余预算（已用完时为 0）
+func (b *Budget) Remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if spent := b.spentAt(time.Now()); spent < b.limit {
+		return b.limit - spent
+	}
+	return 0
+}
+
+// spentAt 返回截至 now 花费的时间（调用方需持有锁）
+func (b *Budget) spentAt(now time.Time) time.Duration {
+	if b.active > 0 {
+		return b.spent + now.Sub(b.since)
+	}
+	return b.spent
+}
+
+// exceeded 返回预算用完的错误
+func (b *Budget) exceeded() error {
+	return fmt.Errorf("%w (spent %s of %s)", ErrBudgetExceeded, b.Spent().Round(time.Millisecond), b.limit)
+}
+
+// begin 开始一次计入预算的调用
+//
+// 返回的上下文在剩余预算耗尽时取消；调用结束后必须调用 finish 结束计时，
+// finish 会把因预算耗尽导致的错误包装为 ErrBudgetExceeded。
+//
+// 第一个调用开始时开始计时并确定截止时间，之后开始的并发调用（包括轮询方法内部
+// 发出的请求）沿用同一个截止时间，最后一个调用结束时才把这段时间计入预算。
+func (b *Budget) begin(ctx context.Context) (context.Context, func(error) error, error) {
+	b.mu.Lock()
+	now := time.Now()
+	if b.spentAt(now) >= b.limit {
+		b.mu.Unlock()
+		return ctx, nil, b.exceeded()
+	}
+	if b.active == 0 {
+		b.since = now
+	}
+	b.active++
+	deadline := b.since.Add(b.limit - b.spent)
+	b.mu.Unlock()
+
+	bctx, cancel := context.WithDeadlineCause(ctx, deadline, ErrBudgetExceeded)
+	finish := func(err error) error {
+		b.end()
+		budgetHit := errors.Is(context.Cause(bctx), ErrBudgetExceeded)
+		cancel()
+		if err != nil && budgetHit && !errors.Is(err, ErrBudgetExceeded) {
+			return fmt.Errorf("%w: %v", b.exceeded(), err)
+		}
+		return err
+	}
+	return bctx, finish, nil
+}
+
+// end 结束一次调用，最后一个进行中的调用结束时累计这段计时区间
+func (b *Budget) end() {
+	b.mu.Lock()
+	b.active--
+	if b.active == 0 {
+		b.spent += time.Since(b.since)
+	}
+	b.mu.Unlock()
+}
+
+// beginBudget 对上下文中的预算调用 begin（没有预算时返回原上下文和不做处理的 finish）
+func beginBudget(ctx context.Context) (context.Context, func(error) error, error) {
+	budget := budgetFrom(ctx)
+	if budget == nil {
+		return ctx, func(err error) error { return err }, nil
+	}
+	return budget.begin(ctx)
+}
+
+// WithBudget 返回携带耗时预算的上下文
+//
+// 使用该上下文的所有 SDK 调用都会计入同一个预算。
+func WithBudget(ctx context.Context, budget *Budget) context.Context {
+	return context.WithValue(ctx, ctxKeyBudget, budget)
+}
+
+// budgetFrom 返回上下文中的 *Budget（没有时返回 nil）
+func budgetFrom(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(ctxKeyBudget).(*Budget)
+	return budget
+}
@@ -0,0 +1,119 @@
+package mail2sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// newEmptyInboxServer 返回邮件列表始终为空的服务端，每个请求耗时 delay
+func newEmptyInboxServer(t *testing.T, delay time.Duration) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"data":{"count":0,"mails":[]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBudgetChargesPolling(t *testing.T) {
+	srv := newEmptyInboxServer(t, 0)
+	client := mail2sdk.NewClient(srv.URL, "key")
+
+	// 默认轮询间隔为 3 秒，预算必须在第一次轮询等待中耗尽
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	budget := mail2sdk.NewBudget(200 * time.Millisecond)
+	ctx = mail2sdk.WithBudget(ctx, budget)
+
+	start := time.Now()
+	_, err := client.WaitForCode(ctx, "user@example.com", nil)
+	if !errors.Is(err, mail2sdk.ErrBudgetExceeded) {
+		t.Fatalf("WaitForCode error = %v, want ErrBudgetExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WaitForCode returned after %s, budget is %s", elapsed, budget.Limit())
+	}
+	if spent := budget.Spent(); spent < budget.Limit() {
+		t.Errorf("Spent() = %s after polling, want at least %s", spent, budget.Limit())
+	}
+
+	// 预算用完后 Watch 直接返回错误
+	if _, err := client.Watch(ctx, "user@example.com", nil); !errors.Is(err, mail2sdk.ErrBudgetExceeded) {
+		t.Errorf("Watch error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestBudgetEndsWatch(t *testing.T) {
+	srv := newEmptyInboxServer(t, 0)
+	client := mail2sdk.NewClient(srv.URL, "key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ctx = mail2sdk.WithBudget(ctx, mail2sdk.NewBudget(200*time.Millisecond))
+
+	events, err := client.Watch(ctx, "user@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last error
+	for ev := range events {
+		last = ev.Err
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Watch kept polling after the budget ran out")
+	}
+	if !errors.Is(last, mail2sdk.ErrBudgetExceeded) {
+		t.Errorf("last event error = %v, want ErrBudgetExceeded", last)
+	}
+}
+
+func TestBudgetSharedByConcurrentCalls(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	srv := newEmptyInboxServer(t, delay)
+	client := mail2sdk.NewClient(srv.URL, "key")
+
+	budget := mail2sdk.NewBudget(300 * time.Millisecond)
+	ctx := mail2sdk.WithBudget(context.Background(), budget)
+
+	getMails := func(n int) []error {
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = client.GetMails(ctx, "user@example.com")
+			}(i)
+		}
+		wg.Wait()
+		return errs
+	}
+
+	// 并发的调用共享同一段时间，不会按调用次数重复计费
+	for i, err := range getMails(5) {
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if spent := budget.Spent(); spent >= budget.Limit() {
+		t.Fatalf("Spent() = %s after one round of concurrent calls, want less than %s", spent, budget.Limit())
+	}
+
+	// 第二轮调用共享剩余的预算，全部在截止时间到达时失败，总耗时不超出预算
+	for i, err := range getMails(5) {
+		if !errors.Is(err, mail2sdk.ErrBudgetExceeded) {
+			t.Errorf("call %d error = %v, want ErrBudgetExceeded", i, err)
+		}
+	}
+	if spent := budget.Spent(); spent > budget.Limit()+100*time.Millisecond {
+		t.Errorf("Spent() = %s, budget %s was overspent", spent, budget.Limit())
+	}
+}
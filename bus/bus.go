@@ -0,0 +1,223 @@
+// Package bus 将邮件事件转发到消息总线
+//
+// Forwarder 把 Watch 监听到的新邮件和 Webhook 推送的事件统一编码为 JSON 消息，
+// 交给用户提供的 Publisher 发布，使邮件到达事件能够接入已有的事件驱动流水线。
+//
+// 本包不依赖任何消息队列客户端库：NATSPublisher 和 KafkaPublisher 只要求传入的
+// 对象具有对应的方法，用户可以直接传入 nats.Conn，或为 Kafka 客户端写几行适配代码。
+//
+// 使用示例:
+//   nc, _ := nats.Connect(nats.DefaultURL)
+//   fwd := bus.NewForwarder(bus.NATSPublisher(nc), bus.Options{Topic: "mail2.events"})
+//
+//   events, _ := client.Watch(ctx, mailbox.Address, nil)
+//   go fwd.ForwardWatch(ctx, events)
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+	"github.com/chuyu5762/mail2sdk/webhook"
+)
+
+// 消息来源
+const (
+	SourceWatch   = "watch"
+	SourceWebhook = "webhook"
+)
+
+// Publisher 消息发布接口
+//
+// key 通常为邮箱地址，支持分区的消息队列（如 Kafka）可以用它保证同一邮箱的消息有序。
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, payload []byte) error
+}
+
+// PublisherFunc 将普通函数适配为 Publisher
+type PublisherFunc func(ctx context.Context, topic string, key, payload []byte) error
+
+// Publish 实现 Publisher 接口
+func (f PublisherFunc) Publish(ctx context.Context, topic string, key, payload []byte) error {
+	return f(ctx, topic, key, payload)
+}
+
+// Message 发布到消息总线的统一消息格式
+type Message struct {
+	Source     string      `json:"source"`                // 消息来源（watch / webhook）
+	Type       string      `json:"type"`                  // 事件类型（如 mail.received）
+	EventID    string      `json:"event_id,omitempty"`    // Webhook 事件 ID
+	Address    string      `json:"email,omitempty"`       // 邮箱地址
+	MailID     string      `json:"mail_id,omitempty"`     // 邮件 ID
+	From       string      `json:"from,omitempty"`        // 发件人
+	Subject    string      `json:"subject,omitempty"`     // 主题
+	ReceivedAt *time.Time  `json:"received_at,omitempty"` // 接收时间（未知时为 nil）
+	Data       interface{} `json:"data,omitempty"`        // 其他事件的原始数据
+}
+
+// Options 转发配置
+type Options struct {
+	Topic string // 发布主题（为空时使用 TopicFunc）
+
+	// TopicFunc 按消息决定主题（可选，优先级高于 Topic）
+	TopicFunc func(msg *Message) string
+
+	// OnError 发布失败或监听出错时的回调（可选）
+	OnError func(msg *Message, err error)
+}
+
+// Forwarder 事件转发器
+type Forwarder struct {
+	pub  Publisher
+	opts Options
+}
+
+// NewForwarder 创建事件转发器
+func NewForwarder(pub Publisher, opts Options) *Forwarder {
+	return &Forwarder{pub: pub, opts: opts}
+}
+
+// Publish 编码并发布一条消息
+func (f *Forwarder) Publish(ctx context.Context, msg *Message) error {
+	topic := f.opts.Topic
+	if f.opts.TopicFunc != nil {
+		topic = f.opts.TopicFunc(msg)
+	}
+	if topic == "" {
+		return fmt.Errorf("bus: topic is required")
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("bus: encode message failed: %w", err)
+	}
+	return f.pub.Publish(ctx, topic, []byte(msg.Address), payload)
+}
+
+// ForwardWatch 转发 Watch 通道中的新邮件事件，直到通道关闭或 ctx 被取消
+//
+// 监听错误和发布失败都会通过 OnError 上报，不会中断转发。
+func (f *Forwarder) ForwardWatch(ctx context.Context, events <-chan mail2sdk.MailEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if ev.Err != nil {
+				f.reportError(nil, ev.Err)
+				continue
+			}
+
+			msg := &Message{
+				Source:     SourceWatch,
+				Type:       webhook.TypeMailReceived,
+				Address:    ev.Address,
+				MailID:     ev.Mail.ID,
+				From:       ev.Mail.From,
+				Subject:    ev.Mail.Subject,
+				ReceivedAt: optionalTime(ev.Mail.ReceivedAt),
+			}
+			if err := f.Publish(ctx, msg); err != nil {
+				f.reportError(msg, err)
+			}
+		}
+	}
+}
+
+// PublishEvent 转发一个 Webhook 事件
+func (f *Forwarder) PublishEvent(ctx context.Context, event webhook.Event) error {
+	msg := &Message{
+		Source:  SourceWebhook,
+		Type:    event.EventType(),
+		EventID: event.EventID(),
+	}
+
+	switch e := event.(type) {
+	case *webhook.MailReceived:
+		msg.Address = e.Address
+		msg.MailID = e.MailID
+		msg.From = e.From
+		msg.Subject = e.Subject
+		msg.ReceivedAt = optionalTime(e.ReceivedAt)
+	case *webhook.MailboxCreated:
+		msg.Address = e.Address
+		msg.Data = e
+	case *webhook.MailboxExpired:
+		msg.Address = e.Address
+		msg.Data = e
+	case *webhook.UnknownEvent:
+		msg.Data = e.Data
+	default:
+		msg.Data = e
+	}
+
+	return f.Publish(ctx, msg)
+}
+
+// WebhookHandler 返回可直接用于 webhook.NewConsumer 的处理函数
+//
+// 发布失败时返回错误，由 Consumer 负责持久化和重试。
+//
+// 示例:
+//   consumer := webhook.NewConsumer(store, fwd.WebhookHandler(), webhook.ConsumerOptions{Secret: secret})
+func (f *Forwarder) WebhookHandler() webhook.HandlerFunc {
+	return f.PublishEvent
+}
+
+// optionalTime 零值时间返回 nil，使 JSON 中省略该字段
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// reportError 上报错误
+func (f *Forwarder) reportError(msg *Message, err error) {
+	if f.opts.OnError != nil {
+		f.opts.OnError(msg, err)
+	}
+}
+
+// NATSConn NATS 连接需要具备的方法（*nats.Conn 满足该接口）
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher 将 NATS 连接适配为 Publisher
+//
+// 示例:
+//   nc, _ := nats.Connect(nats.DefaultURL)
+//   pub := bus.NATSPublisher(nc)
+func NATSPublisher(conn NATSConn) Publisher {
+	return PublisherFunc(func(ctx context.Context, topic string, key, payload []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return conn.Publish(topic, payload)
+	})
+}
+
+// KafkaProducer Kafka 生产者需要具备的方法
+//
+// 以 segmentio/kafka-go 为例，几行代码即可适配：
+//
+//   type kafkaWriter struct{ w *kafka.Writer }
+//
+//   func (k kafkaWriter) Produce(ctx context.Context, topic string, key, value []byte) error {
+//       return k.w.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: value})
+//   }
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+// KafkaPublisher 将 Kafka 生产者适配为 Publisher（邮箱地址作为消息键）
+func KafkaPublisher(producer KafkaProducer) Publisher {
+	return PublisherFunc(producer.Produce)
+}
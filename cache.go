@@ -0,0 +1,192 @@
+package mail2sdk
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 邮件详情缓存（全局，默认关闭）
+//
+// 邮件一旦接收，其详情内容就不会再变化，因此可以安全地缓存。
+// 同一封邮件被多次读取（提取验证码、链接、附件等）时只会请求一次网络。
+var (
+	detailCacheMu sync.RWMutex
+	detailCache   *mailDetailCache
+)
+
+// mailDetailCache 基于 LRU + TTL 的邮件详情缓存
+type mailDetailCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	ll         *list.List               // 最近使用顺序，队首为最近使用
+	items      map[string]*list.Element // 缓存键 -> 链表节点
+}
+
+// detailCacheEntry 缓存条目
+type detailCacheEntry struct {
+	key       string
+	detail    MailDetail
+	expiresAt time.Time
+}
+
+// newMailDetailCache 创建邮件详情缓存
+func newMailDetailCache(maxEntries int, ttl time.Duration) *mailDetailCache {
+	return &mailDetailCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// detailCacheKey 生成缓存键
+//
+// 包含 baseURL，避免不同服务端的邮件 ID 冲突；包含凭据指纹（见 credentialFingerprint），
+// 轮换密钥、Reload 或使用不同邮箱令牌的会话不会读到其他凭据获取的详情。
+func detailCacheKey(baseURL, address, credential, mailID string) string {
+	return baseURL + "\x00" + address + "\x00" + credential + "\x00" + mailID
+}
+
+// credentialFingerprint 返回读取邮件时使用的凭据（API 密钥和邮箱级令牌）的指纹
+func (c *Client) credentialFingerprint(ctx context.Context) string {
+	sum := sha256.Sum256([]byte(c.settings().apiKey + "\x00" + c.mailboxTokenFor(withMailboxRead(ctx))))
+	return hex.EncodeToString(sum[:8])
+}
+
+// get 读取缓存，过期条目会被直接移除
+func (c *mailDetailCache) get(key string) (*MailDetail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*detailCacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+
+	// 返回副本，避免调用方修改缓存内容
+	detail := entry.detail
+	detail.To = append([]string(nil), entry.detail.To...)
+	detail.Attachments = append([]Attachment(nil), entry.detail.Attachments...)
+	return &detail, true
+}
+
+// put 写入缓存，超过容量时淘汰最久未使用的条目
+func (c *mailDetailCache) put(key string, detail *MailDetail) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := *detail
+	stored.To = append([]string(nil), detail.To...)
+	stored.Attachments = append([]Attachment(nil), detail.Attachments...)
+	expiresAt := time.Now().Add(c.ttl)
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*detailCacheEntry)
+		entry.detail = stored
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(&detailCacheEntry{key: key, detail: stored, expiresAt: expiresAt})
+	c.items[key] = elem
+
+	if c.maxEntries > 0 {
+		for c.ll.Len() > c.maxEntries {
+			c.removeElement(c.ll.Back())
+		}
+	}
+}
+
+// removeMail 移除一封邮件在所有凭据下的缓存条目
+func (c *mailDetailCache) removeMail(baseURL, address, mailID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := baseURL + "\x00" + address + "\x00"
+	suffix := "\x00" + mailID
+	for key, elem := range c.items {
+		if strings.HasPrefix(key, prefix) && strings.HasSuffix(key, suffix) && len(key) > len(prefix)+len(suffix) {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// removeAddress 移除某个邮箱的全部缓存条目（邮箱被删除时调用）
+func (c *mailDetailCache) removeAddress(baseURL, address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := baseURL + "\x00" + address + "\x00"
+	for key, elem := range c.items {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// len 返回当前缓存条目数量
+func (c *mailDetailCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// removeElement 移除链表节点（调用方需持有锁）
+func (c *mailDetailCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*detailCacheEntry).key)
+}
+
+// getDetailCache 获取当前启用的缓存（未启用时返回 nil）
+func getDetailCache() *mailDetailCache {
+	detailCacheMu.RLock()
+	defer detailCacheMu.RUnlock()
+	return detailCache
+}
+
+// EnableMailDetailCache 启用邮件详情缓存（导出函数）
+//
+// 启用后 GetMailDetail 会按 (邮箱地址, 邮件 ID) 缓存结果，适用于同一封邮件
+// 会被多次读取的提取流程（验证码 + 链接 + 附件等）。
+//
+// 参数:
+//   maxEntries: 最大缓存条目数（<= 0 表示不限制）
+//   ttl: 缓存有效期（<= 0 表示永不过期）
+//
+// 示例:
+//   mail2sdk.EnableMailDetailCache(1000, 10*time.Minute)
+func EnableMailDetailCache(maxEntries int, ttl time.Duration) {
+	detailCacheMu.Lock()
+	defer detailCacheMu.Unlock()
+	detailCache = newMailDetailCache(maxEntries, ttl)
+}
+
+// DisableMailDetailCache 关闭并清空邮件详情缓存（导出函数）
+func DisableMailDetailCache() {
+	detailCacheMu.Lock()
+	defer detailCacheMu.Unlock()
+	detailCache = nil
+}
+
+// MailDetailCacheLen 返回当前缓存的邮件详情数量（未启用时返回 0）
+func MailDetailCacheLen() int {
+	if c := getDetailCache(); c != nil {
+		return c.len()
+	}
+	return 0
+}
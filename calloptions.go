@@ -0,0 +1,101 @@
+package mail2sdk
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CallOption 单次调用的可选参数
+//
+// 以可变参数的形式传给 GetMails、GetFolderMails、ListTrash 等方法，新增参数时不必
+// 再修改方法签名。服务端会收到对应的查询参数；旧版本服务端忽略这些参数时，SDK 会在
+// 本地按相同的规则过滤，结果保持一致。
+//
+// 示例:
+//   mails, err := client.GetMails(ctx, address,
+//       mail2sdk.WithSince(startedAt),
+//       mail2sdk.WithLimit(20),
+//   )
+type CallOption func(*callOptions)
+
+// callOptions 单次调用的参数
+type callOptions struct {
+	limit  int       // 最多返回的数量（0 表示不限制）
+	since  time.Time // 只返回该时间及之后收到的邮件（零值表示不限制）
+	folder string    // 文件夹（为空表示收件箱）
+}
+
+// WithLimit 最多返回 n 条结果（按服务端返回的顺序截取，<= 0 表示不限制）
+func WithLimit(n int) CallOption {
+	return func(o *callOptions) {
+		o.limit = n
+	}
+}
+
+// WithSince 只返回 t 及之后收到的邮件
+//
+// 适合在触发发送验证码前记下时间，只查看之后到达的邮件。
+func WithSince(t time.Time) CallOption {
+	return func(o *callOptions) {
+		o.since = t
+	}
+}
+
+// WithFolder 读取指定文件夹中的邮件（见 FolderInbox、FolderSpam）
+func WithFolder(folder string) CallOption {
+	return func(o *callOptions) {
+		o.folder = folder
+	}
+}
+
+// newCallOptions 应用全部调用参数
+func newCallOptions(opts []CallOption) callOptions {
+	if len(opts) == 0 {
+		return callOptions{}
+	}
+	var o callOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// query 返回附加到请求路径上的查询字符串（没有参数时为空）
+func (o callOptions) query() string {
+	values := url.Values{}
+	if o.limit > 0 {
+		values.Set("limit", strconv.Itoa(o.limit))
+	}
+	if !o.since.IsZero() {
+		values.Set("since", o.since.UTC().Format(time.RFC3339))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// keep 判断收到时间为 receivedAt 的邮件是否满足 since 条件
+func (o callOptions) keep(receivedAt time.Time) bool {
+	return o.since.IsZero() || !receivedAt.Before(o.since)
+}
+
+// filterMails 在本地应用 since 和 limit（服务端已经处理过时不会改变结果）
+func (o callOptions) filterMails(mails []Mail) []Mail {
+	if o.since.IsZero() && o.limit <= 0 {
+		return mails
+	}
+	kept := mails[:0]
+	for _, mail := range mails {
+		if o.keep(mail.ReceivedAt) {
+			kept = append(kept, mail)
+		}
+	}
+	if o.limit > 0 && len(kept) > o.limit {
+		kept = kept[:o.limit]
+	}
+	return kept
+}
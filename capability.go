@@ -0,0 +1,283 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotSupportedByServer 表示当前服务端版本不支持该功能
+//
+// 可以通过 errors.Is 判断：
+//
+//   if errors.Is(err, mail2sdk.ErrNotSupportedByServer) {
+//       // 降级处理
+//   }
+var ErrNotSupportedByServer = errors.New("feature not supported by server")
+
+// Feature 服务端可选功能
+type Feature string
+
+// 服务端可选功能常量
+const (
+	FeatureMailboxPassword Feature = "mailbox_password" // SetMailboxPassword / RotateMailboxPassword
+	FeatureShareLink       Feature = "share_link"       // ShareMailbox
+	FeatureListMailboxes   Feature = "list_mailboxes"   // ListMailboxes / PurgeAll
+	FeatureDomainStats     Feature = "domain_stats"     // DomainUsage
+	FeatureTrash           Feature = "trash"            // TrashMail / RestoreMail / ListTrash
+	FeatureQuota           Feature = "quota"            // Quota
+	FeatureAttachments     Feature = "attachments"      // DownloadAttachment / SaveAttachment
+	FeatureTokenRefresh    Feature = "token_refresh"    // RefreshMailboxToken / Session.EnableTokenRefresh
+	FeatureCustomUsername  Feature = "custom_username"  // WithNameTemplate
+	FeatureFolders         Feature = "folders"          // ListFolders / GetFolderMails / RescueFromSpam / IncludeSpam
+	FeatureExcludeDomains  Feature = "exclude_domains"  // 创建邮箱时由服务端避开黑名单域名（WithFastCreate）
+	FeatureBatchMails      Feature = "batch_mails"      // 一次请求获取多个邮箱的邮件列表（WatchScheduler）
+)
+
+// capabilityMatrix 各功能要求的最低服务端版本
+//
+// 服务端在 /api/version 中返回 features 列表时以该列表为准；
+// 无法获取服务端版本时按支持处理，由首次请求的结果决定。
+var capabilityMatrix = map[Feature]string{
+	FeatureMailboxPassword: "1.2.0",
+	FeatureShareLink:       "1.2.0",
+	FeatureListMailboxes:   "1.3.0",
+	FeatureDomainStats:     "1.3.0",
+	FeatureTrash:           "1.4.0",
+	FeatureQuota:           "1.4.0",
+	FeatureAttachments:     "1.4.0",
+	FeatureTokenRefresh:    "1.4.0",
+	FeatureCustomUsername:  "1.4.0",
+	FeatureFolders:         "1.5.0",
+	FeatureExcludeDomains:  "1.6.0",
+	FeatureBatchMails:      "1.7.0",
+}
+
+// ServerInfo 服务端版本信息
+type ServerInfo struct {
+	Version      string   `json:"version"`                 // 服务端版本（旧版本服务端为空）
+	Features     []string `json:"features,omitempty"`      // 服务端声明支持的功能（可选）
+	PollInterval float64  `json:"poll_interval,omitempty"` // 服务端建议的轮询间隔（秒，可选）
+}
+
+// serverInfoRetryTTL 获取服务端版本失败后缓存该错误的时长
+const serverInfoRetryTTL = 5 * time.Minute
+
+// capabilities 每个客户端缓存的服务端能力信息
+type capabilities struct {
+	mu          sync.Mutex
+	info        *ServerInfo      // 已获取的服务端信息（nil 表示尚未获取）
+	infoErr     error            // 最近一次获取服务端信息失败的错误
+	infoErrAt   time.Time        // infoErr 的发生时间
+	unsupported map[Feature]bool // 运行中发现不支持的功能
+}
+
+// ServerInfo 获取服务端版本信息（结果会被缓存）
+//
+// 旧版本服务端没有 /api/version 接口，此时返回 Version 为空的 ServerInfo。
+// 服务端给出的其他失败（如 401、405 或返回的不是 JSON）会缓存 5 分钟，期间直接返回
+// 同一个错误，避免每次调用 Supports 都重复请求；网络错误、超时和 ctx 取消不会被缓存。
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	c.caps.mu.Lock()
+	info, infoErr, infoErrAt := c.caps.info, c.caps.infoErr, c.caps.infoErrAt
+	c.caps.mu.Unlock()
+	if info != nil {
+		return info, nil
+	}
+	if infoErr != nil && time.Since(infoErrAt) < serverInfoRetryTTL {
+		return nil, infoErr
+	}
+
+	info = &ServerInfo{}
+	if err := c.do(ctx, "GET", "/api/version", nil, info); err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			if code := ErrorCodeOf(err); ctx.Err() == nil && code != CodeNetwork && code != CodeTimeout {
+				c.caps.mu.Lock()
+				c.caps.infoErr, c.caps.infoErrAt = err, time.Now()
+				c.caps.mu.Unlock()
+			}
+			return nil, err
+		}
+		info = &ServerInfo{}
+	}
+
+	c.caps.mu.Lock()
+	c.caps.info, c.caps.infoErr = info, nil
+	c.caps.mu.Unlock()
+	c.setPollHint(time.Duration(info.PollInterval * float64(time.Second)))
+	return info, nil
+}
+
+// Supports 判断服务端是否支持某个功能
+//
+// 判断顺序：运行中已发现不支持 > 服务端声明的 features 列表 > 版本矩阵。
+// 无法确定服务端版本时返回 true。
+func (c *Client) Supports(ctx context.Context, feature Feature) (bool, error) {
+	c.caps.mu.Lock()
+	unsupported := c.caps.unsupported[feature]
+	c.caps.mu.Unlock()
+	if unsupported {
+		return false, nil
+	}
+
+	info, err := c.ServerInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	return info.supports(feature), nil
+}
+
+// supports 按 features 列表或版本矩阵判断服务端是否支持某个功能
+func (info *ServerInfo) supports(feature Feature) bool {
+	if len(info.Features) > 0 {
+		for _, f := range info.Features {
+			if Feature(f) == feature {
+				return true
+			}
+		}
+		return false
+	}
+
+	minVersion, ok := capabilityMatrix[feature]
+	if !ok || info.Version == "" {
+		return true
+	}
+	return compareVersions(info.Version, minVersion) >= 0
+}
+
+// Capabilities 服务端能力概览
+type Capabilities struct {
+	Version  string           // 服务端版本（旧版本服务端为空）
+	Features map[Feature]bool // SDK 已知的每个功能是否可用
+}
+
+// Has 判断功能是否可用（SDK 未知的功能返回 false）
+func (caps *Capabilities) Has(feature Feature) bool {
+	return caps.Features[feature]
+}
+
+// Supported 返回可用的功能列表（按名称排序）
+func (caps *Capabilities) Supported() []Feature {
+	features := make([]Feature, 0, len(caps.Features))
+	for feature, ok := range caps.Features {
+		if ok {
+			features = append(features, feature)
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// Capabilities 查询服务端支持哪些功能
+//
+// 判断规则与 Supports 相同：服务端声明了 features 列表时以列表为准，否则按版本矩阵
+// 判断，无法确定版本时视为支持。结果基于缓存的 ServerInfo，运行中发现不支持的功能
+// 也会反映在结果中。不支持的功能调用时返回 ErrNotSupportedByServer。
+//
+// 返回:
+//   *Capabilities: 服务端能力概览
+//   error: 获取服务端信息失败时的错误
+//
+// 示例:
+//   caps, err := client.Capabilities(ctx)
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+//   fmt.Println("服务端版本:", caps.Version, "支持:", caps.Supported())
+//   if caps.Has(mail2sdk.FeatureFolders) {
+//       spam, _ := client.GetFolderMails(ctx, address, mail2sdk.FolderSpam)
+//   }
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	info, err := c.ServerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &Capabilities{Version: info.Version, Features: make(map[Feature]bool, len(capabilityMatrix))}
+	for feature := range capabilityMatrix {
+		if caps.Features[feature], err = c.Supports(ctx, feature); err != nil {
+			return nil, err
+		}
+	}
+	return caps, nil
+}
+
+// requireFeature 服务端不支持该功能时返回 ErrNotSupportedByServer
+//
+// 获取服务端版本失败时不阻止调用，由接口本身的结果决定。
+func (c *Client) requireFeature(ctx context.Context, feature Feature) error {
+	ok, err := c.Supports(ctx, feature)
+	if err == nil && !ok {
+		return fmt.Errorf("%w: %s", ErrNotSupportedByServer, feature)
+	}
+	return nil
+}
+
+// markUnsupportedOn404 接口返回 404 时记录服务端不支持该功能
+//
+// 只用于路径中不含邮箱地址的接口：邮箱相关接口的 404 可能只是邮箱不存在。
+func (c *Client) markUnsupportedOn404(feature Feature, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	c.markUnsupported(feature)
+	return fmt.Errorf("%w: %s", ErrNotSupportedByServer, feature)
+}
+
+// markUnsupported 记录运行中发现服务端不支持该功能
+func (c *Client) markUnsupported(feature Feature) {
+	c.caps.mu.Lock()
+	defer c.caps.mu.Unlock()
+	if c.caps.unsupported == nil {
+		c.caps.unsupported = make(map[Feature]bool)
+	}
+	c.caps.unsupported[feature] = true
+}
+
+// knownUnsupported 判断是否已知服务端不支持该功能（不发送请求，未获取服务端信息时返回 false）
+func (c *Client) knownUnsupported(feature Feature) bool {
+	c.caps.mu.Lock()
+	unsupported, info := c.caps.unsupported[feature], c.caps.info
+	c.caps.mu.Unlock()
+	return unsupported || (info != nil && !info.supports(feature))
+}
+
+// compareVersions 比较两个形如 "v1.2.3" 的版本号
+//
+// 返回 -1、0、1；无法解析的部分按 0 处理，预发布后缀（如 "-beta"）被忽略。
+func compareVersions(a, b string) int {
+	pa, pb := parseVersion(a), parseVersion(b)
+	for i := 0; i < 3; i++ {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion 解析版本号的主、次、修订号
+func parseVersion(v string) [3]int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+
+	var parts [3]int
+	for i, s := range strings.SplitN(v, ".", 3) {
+		n, _ := strconv.Atoi(s)
+		parts[i] = n
+	}
+	return parts
+}
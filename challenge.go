@@ -0,0 +1,131 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrChallengeDetected 表示请求被 WAF/CDN 的人机验证页面拦截
+//
+// 常见于 Cloudflare 等防护开启"5 秒盾"或验证码时，服务端返回的是 HTML 页面而不是
+// API 响应。可以通过 errors.Is 判断，用 errors.As 获取 *ChallengeError 查看详情。
+var ErrChallengeDetected = errors.New("waf challenge detected")
+
+// ChallengeError WAF/CDN 人机验证的详细信息
+type ChallengeError struct {
+	Provider   string      // 防护提供方（如 "cloudflare"、"aws-waf"，无法识别时为 "unknown"）
+	StatusCode int         // HTTP 状态码
+	URL        string      // 被拦截的请求地址
+	Header     http.Header // 响应头（如 Cloudflare 的 Cf-Ray，便于联系服务端排查）
+	Body       string      // 响应体的前 1KB（已脱敏）
+}
+
+// Error 实现 error 接口
+func (e *ChallengeError) Error() string {
+	return fmt.Sprintf("%v: %s (status=%d)", ErrChallengeDetected, e.Provider, e.StatusCode)
+}
+
+// Unwrap 使 errors.Is(err, ErrChallengeDetected) 成立
+func (e *ChallengeError) Unwrap() error {
+	return ErrChallengeDetected
+}
+
+// ChallengeHandler 遇到 WAF/CDN 人机验证时的处理函数
+//
+// 返回 nil 表示已经处理（如已切换代理、通过外部服务完成验证并写入 Cookie），
+// SDK 会重新发送该请求一次；返回错误则放弃，调用方收到的错误同时包含两者。
+type ChallengeHandler func(ctx context.Context, challenge *ChallengeError) error
+
+// WithChallengeHandler 设置遇到 WAF/CDN 人机验证时的处理函数
+//
+// 每次调用最多处理一次，重新发送后仍被拦截时返回 ErrChallengeDetected。验证通过后
+// 服务端通常下发 Cookie（如 cf_clearance），需要配合带 Cookie Jar 的 HTTP 客户端
+// （WithHTTPClient）才能在之后的请求中携带。
+//
+// 示例:
+//   jar, _ := cookiejar.New(nil)
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithHTTPClient(&http.Client{Jar: jar, Transport: proxies}),
+//       mail2sdk.WithChallengeHandler(func(ctx context.Context, ch *mail2sdk.ChallengeError) error {
+//           log.Printf("被 %s 拦截，切换代理: %s", ch.Provider, ch.URL)
+//           return proxies.Next()
+//       }),
+//   )
+func WithChallengeHandler(handler ChallengeHandler) Option {
+	return func(c *Client) {
+		c.challengeHandler = handler
+	}
+}
+
+// handleChallenge 请求被人机验证拦截时调用 ChallengeHandler
+//
+// 返回 true 表示应重新发送请求；返回 false 时 err 为最终的错误。
+func (c *Client) handleChallenge(ctx context.Context, err error, handled *bool) (bool, error) {
+	var challenge *ChallengeError
+	if c.challengeHandler == nil || *handled || !errors.As(err, &challenge) {
+		return false, err
+	}
+	*handled = true
+
+	if handlerErr := c.challengeHandler(ctx, challenge); handlerErr != nil {
+		return false, fmt.Errorf("%w (handler: %w)", err, handlerErr)
+	}
+	return true, nil
+}
+
+// challengeSignature 某个 WAF/CDN 的人机验证页面特征
+type challengeSignature struct {
+	provider string
+	server   string   // Server 响应头包含的内容（为空表示不检查）
+	markers  []string // 页面中出现任意一个即视为验证页面（小写）
+}
+
+// challengeSignatures 常见 WAF/CDN 的验证页面特征
+var challengeSignatures = []challengeSignature{
+	{provider: "cloudflare", markers: []string{"cf-chl", "challenge-platform", "cf_chl_opt", "just a moment...", "attention required! | cloudflare"}},
+	{provider: "ddos-guard", markers: []string{"ddos-guard"}},
+	{provider: "sucuri", markers: []string{"sucuri website firewall"}},
+	{provider: "akamai", server: "akamaighost", markers: []string{"access denied"}},
+	{provider: "unknown", markers: []string{"captcha", "are you a robot", "verify you are human"}},
+}
+
+// maxChallengeScan 检查验证页面特征时扫描的响应体长度
+const maxChallengeScan = 16 << 10
+
+// detectChallenge 判断响应是否为 WAF/CDN 的人机验证页面，返回防护提供方（不是时为空）
+//
+// 先检查明确的响应头；响应体以 JSON 开头时直接跳过，正常响应几乎没有额外开销。
+func detectChallenge(header http.Header, body []byte) string {
+	if strings.EqualFold(header.Get("Cf-Mitigated"), "challenge") {
+		return "cloudflare"
+	}
+	if header.Get("X-Amzn-Waf-Action") != "" {
+		return "aws-waf"
+	}
+
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' {
+		return ""
+	}
+	if len(trimmed) > maxChallengeScan {
+		trimmed = trimmed[:maxChallengeScan]
+	}
+	page := strings.ToLower(string(trimmed))
+	server := strings.ToLower(header.Get("Server"))
+
+	for _, sig := range challengeSignatures {
+		if sig.server != "" && !strings.Contains(server, sig.server) {
+			continue
+		}
+		for _, marker := range sig.markers {
+			if strings.Contains(page, marker) {
+				return sig.provider
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ChaosProfile 故障注入配置
+//
+// 所有概率取值范围为 0-1，为 0 表示不注入该类故障。
+type ChaosProfile struct {
+	Seed int64 // 随机种子（0 表示使用当前时间，固定种子可复现同样的故障序列）
+
+	MinLatency time.Duration // 每个请求附加的最小延迟
+	MaxLatency time.Duration // 每个请求附加的最大延迟
+
+	ErrorRate   float64 // 开始一段 5xx 错误的概率
+	BurstLength int     // 每段 5xx 错误连续返回的次数（<= 0 表示 1）
+	ErrorStatus int     // 注入的状态码（0 表示 503）
+
+	MalformedJSONRate float64 // 将正常响应体替换为残缺 JSON 的概率
+	ResetRate         float64 // 模拟连接被重置（ECONNRESET）的概率
+}
+
+// ChaosStats 故障注入统计
+type ChaosStats struct {
+	Requests      int // 总请求数
+	ServerErrors  int // 注入的 5xx 响应数
+	MalformedJSON int // 注入的残缺 JSON 响应数
+	Resets        int // 注入的连接重置数
+	Passed        int // 未被注入故障的请求数
+}
+
+// ChaosTransport 故障注入传输层（用于弹性测试）
+//
+// 按照 ChaosProfile 在请求中注入延迟、连续 5xx、残缺 JSON 和连接重置，
+// 用于验证重试/退避配置能否扛住不稳定的服务端。不要在生产环境中使用。
+//
+// 示例:
+//   chaos := mail2sdk.NewChaosTransport(nil, mail2sdk.ChaosProfile{
+//       Seed:        42,
+//       MaxLatency:  300 * time.Millisecond,
+//       ErrorRate:   0.2,
+//       BurstLength: 3,
+//       ResetRate:   0.05,
+//   })
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithTransport(chaos),
+//       mail2sdk.WithRetry(mail2sdk.RetryPolicy{MaxRetries: 5}),
+//   )
+type ChaosTransport struct {
+	base    http.RoundTripper
+	profile ChaosProfile
+
+	mu        sync.Mutex
+	rnd       *rand.Rand
+	burstLeft int
+	stats     ChaosStats
+}
+
+// NewChaosTransport 创建故障注入传输层
+//
+// 参数:
+//   base: 实际发送请求的传输层（nil 表示 http.DefaultTransport）
+//   profile: 故障注入配置
+func NewChaosTransport(base http.RoundTripper, profile ChaosProfile) *ChaosTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	seed := profile.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &ChaosTransport{
+		base:    base,
+		profile: profile,
+		rnd:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// chaosFault 一次请求要注入的故障类型
+type chaosFault int
+
+const (
+	faultNone chaosFault = iota
+	faultServerError
+	faultMalformedJSON
+	faultReset
+)
+
+// plan 决定本次请求的延迟和故障类型
+func (t *ChaosTransport) plan() (time.Duration, chaosFault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats.Requests++
+
+	latency := t.profile.MinLatency
+	if span := t.profile.MaxLatency - t.profile.MinLatency; span > 0 {
+		latency += time.Duration(t.rnd.Int63n(int64(span)))
+	}
+
+	fault := faultNone
+	switch {
+	case t.burstLeft > 0:
+		t.burstLeft--
+		fault = faultServerError
+	case t.rnd.Float64() < t.profile.ResetRate:
+		fault = faultReset
+	case t.rnd.Float64() < t.profile.ErrorRate:
+		burst := t.profile.BurstLength
+		if burst <= 0 {
+			burst = 1
+		}
+		t.burstLeft = burst - 1
+		fault = faultServerError
+	case t.rnd.Float64() < t.profile.MalformedJSONRate:
+		fault = faultMalformedJSON
+	}
+
+	switch fault {
+	case faultServerError:
+		t.stats.ServerErrors++
+	case faultMalformedJSON:
+		t.stats.MalformedJSON++
+	case faultReset:
+		t.stats.Resets++
+	default:
+		t.stats.Passed++
+	}
+
+	return latency, fault
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency, fault := t.plan()
+
+	if latency > 0 {
+		if err := sleepContext(req.Context(), latency); err != nil {
+			return nil, err
+		}
+	}
+
+	switch fault {
+	case faultReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	case faultServerError:
+		status := t.profile.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		body := fmt.Sprintf(`{"code":%d,"msg":"chaos: injected server error"}`, status)
+		return syntheticResponse(req, status, body), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || fault != faultMalformedJSON {
+		return resp, err
+	}
+
+	// 丢弃真实响应体，替换为被截断的 JSON
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader([]byte(`{"code":0,"msg":"ok","data":{"`)))
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// Stats 返回故障注入统计
+func (t *ChaosTransport) Stats() ChaosStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// syntheticResponse 构造一个不经过网络的 HTTP 响应
+func syntheticResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sleepContext 等待指定时间，ctx 被取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
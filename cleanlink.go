@@ -0,0 +1,222 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultLinkTrackers 默认的邮件点击追踪域名（包括子域名）
+//
+// 邮件服务商会把正文中的链接改写为经过这些域名的跳转地址。主机名第一段为 click、
+// links、trk 等的地址（如 click.mail.example.com）同样视为追踪地址。
+var DefaultLinkTrackers = []string{
+	"sendgrid.net",
+	"list-manage.com",
+	"mandrillapp.com",
+	"mailgun.org",
+	"sparkpostmail.com",
+	"awstrack.me",
+	"hubspotlinks.com",
+	"mjt.lu",
+	"rs6.net",
+	"safelinks.protection.outlook.com",
+}
+
+// 追踪地址主机名的第一段
+var trackerHostLabels = map[string]bool{
+	"click": true, "clicks": true, "links": true, "trk": true, "track": true, "tracking": true,
+}
+
+// 跳转地址中携带目标链接的查询参数
+var redirectParams = []string{"url", "u", "target", "dest", "destination", "redirect", "redirect_url", "q", "link"}
+
+// 跳转地址路径的最后一段（非追踪域名只在这些路径上解包，避免误解包登录页的 redirect 参数）
+var redirectPaths = map[string]bool{
+	"url": true, "redirect": true, "r": true, "click": true, "track": true, "out": true, "link": true,
+}
+
+// 追踪参数（utm_ 开头的参数全部去除）
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true, "igshid": true,
+	"mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true, "mkt_tok": true, "vero_id": true,
+	"oly_anon_id": true, "oly_enc_id": true, "_ga": true, "_gl": true, "ref_src": true,
+}
+
+// 等待确认链接时每封邮件最多解析的追踪地址数
+const maxResolvedLinks = 5
+
+// CleanLink 去除链接中的追踪参数，并解开一层把目标地址放在参数或路径中的跳转地址
+//
+// 去除 utm_*、fbclid、gclid、mc_eid 等追踪参数；跳转地址（追踪域名，或路径为
+// /url、/redirect、/click 等）的 url、u、target、redirect 等参数或路径中携带
+// 完整的目标地址时，返回目标地址。不发送任何请求，无法识别时原样返回。
+//
+// 示例:
+//   mail2sdk.CleanLink("https://example.com/verify?token=abc&utm_source=mail")
+//   // https://example.com/verify?token=abc
+//   mail2sdk.CleanLink("https://www.google.com/url?q=https%3A%2F%2Fexample.com%2Fverify%3Ftoken%3Dabc")
+//   // https://example.com/verify?token=abc
+func CleanLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+	if target := unwrapLink(u); target != nil {
+		u = target
+	}
+	stripTrackingParams(u)
+	return u.String()
+}
+
+// unwrapLink 返回跳转地址中携带的目标地址（不是跳转地址时返回 nil）
+func unwrapLink(u *url.URL) *url.URL {
+	tracker := isTrackerHost(u.Hostname(), DefaultLinkTrackers)
+	segments := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	if !tracker && !redirectPaths[strings.ToLower(segments[len(segments)-1])] {
+		return nil
+	}
+
+	query := u.Query()
+	for _, param := range redirectParams {
+		if target := absoluteLink(query.Get(param)); target != nil {
+			return target
+		}
+	}
+	// 部分服务商把目标地址编码在路径中（如 /L0/https:%2F%2Fexample.com%2Fverify/1/...）
+	if tracker {
+		for _, segment := range segments {
+			if s, err := url.PathUnescape(segment); err == nil {
+				if target := absoluteLink(s); target != nil {
+					return target
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// absoluteLink 解析完整的 http(s) 地址（不是时返回 nil）
+func absoluteLink(s string) *url.URL {
+	lower := strings.ToLower(s)
+	if !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "http://") {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return u
+}
+
+// stripTrackingParams 去除追踪参数（其余参数保持原有顺序和编码）
+func stripTrackingParams(u *url.URL) {
+	if u.RawQuery == "" {
+		return
+	}
+	parts := strings.Split(u.RawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		key := part
+		if i := strings.Index(part, "="); i >= 0 {
+			key = part[:i]
+		}
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, "utm_") || trackingParams[key] {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	u.RawQuery = strings.Join(kept, "&")
+}
+
+// isTrackerHost 判断主机名是否为点击追踪地址
+func isTrackerHost(host string, trackers []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if i := strings.Index(host, "."); i > 0 && trackerHostLabels[host[:i]] {
+		return true
+	}
+	return domainMatches(host, trackers)
+}
+
+// trackerLink 判断链接是否为需要请求才能解开的追踪地址
+//
+// 路径本身像确认链接时不算，避免提前访问并消耗一次性令牌。
+func trackerLink(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && isTrackerHost(u.Hostname(), DefaultLinkTrackers) && !confirmLinkPattern.MatchString(u.Path)
+}
+
+// WithLinkCleaning 清理确认链接中的追踪参数和跳转地址
+//
+// 设置后 WaitForCodeOrLink 返回的链接、ConfirmLink 访问的链接和最终地址都会经过
+// CleanLink 处理，下游断言可以直接比较规范的确认地址。resolve 为 true 时，无法离线
+// 解开的追踪地址（见 DefaultLinkTrackers）会被请求一次（不跟随重定向、不携带 API
+// 密钥），取其重定向目标作为链接；请求失败时使用离线清理的结果。
+//
+// 只对追踪域名发送请求，确认链接本身不会被提前访问。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithLinkCleaning(true))
+//   result, _ := client.WaitForCodeOrLink(ctx, address)
+//   // result.Link 为 https://example.com/verify?token=abc，而不是 https://click.example.com/ls/click?upn=...
+func WithLinkCleaning(resolve bool) Option {
+	return func(c *Client) {
+		c.cleanLinks = true
+		c.resolveLinks = resolve
+	}
+}
+
+// cleanLink 按客户端配置清理链接（未启用时原样返回）
+func (c *Client) cleanLink(ctx context.Context, link string) string {
+	if !c.cleanLinks {
+		return link
+	}
+	cleaned := CleanLink(link)
+	if !c.resolveLinks {
+		return cleaned
+	}
+	// 已经离线解开的跳转地址不再请求
+	if !trackerLink(cleaned) {
+		return cleaned
+	}
+	target, err := c.resolveLink(ctx, cleaned)
+	if err != nil {
+		c.log(ctx, LogEntry{Level: LogDebug, Message: "resolve tracking link failed", URL: cleaned, Err: err})
+		return cleaned
+	}
+	return CleanLink(target)
+}
+
+// resolveLink 请求一次跳转地址，返回重定向目标
+func (c *Client) resolveLink(ctx context.Context, link string) (string, error) {
+	httpClient := *c.settings().httpClient
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header["User-Agent"] = c.settings().userAgentHeader
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", c.redactError(fmt.Errorf("resolve link failed: %w", err))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("resolve link failed: status %d without location", resp.StatusCode)
+	}
+	return location.String(), nil
+}
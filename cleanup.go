@@ -0,0 +1,147 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 自动清理默认的检查间隔
+const defaultCleanupInterval = 10 * time.Minute
+
+// CleanupPolicy 自动清理策略
+type CleanupPolicy struct {
+	MaxAge       time.Duration // 删除创建时间超过该时长的邮箱（<= 0 表示不按时间清理）
+	MaxMailboxes int           // 邮箱数量上限，超出时从最早创建的开始删除（<= 0 表示不限制）
+	Interval     time.Duration // 检查间隔（<= 0 表示 10 分钟）
+
+	// OnReport 每轮清理完成后的回调（可选），err 为获取邮箱列表失败的错误
+	OnReport func(report *PurgeReport, err error)
+}
+
+// enabled 判断策略是否需要执行
+func (p CleanupPolicy) enabled() bool {
+	return p.MaxAge > 0 || p.MaxMailboxes > 0
+}
+
+// SetCleanupPolicy 设置自动清理策略并启动后台清理
+//
+// 后台 goroutine 会立即执行一轮清理，之后按 Interval 定期删除符合策略的邮箱，
+// 无需单独的定时任务即可让账号保持在配额以内。再次调用会替换之前的策略，
+// 传入零值策略会停止后台清理。
+//
+// 示例:
+//   client.SetCleanupPolicy(mail2sdk.CleanupPolicy{
+//       MaxAge:       24 * time.Hour,
+//       MaxMailboxes: 500,
+//       OnReport: func(r *mail2sdk.PurgeReport, err error) {
+//           if err != nil || len(r.Failed) > 0 {
+//               log.Printf("自动清理失败: %v %v", err, r.Failed)
+//           }
+//       },
+//   })
+//   defer client.SetCleanupPolicy(mail2sdk.CleanupPolicy{}) // 停止后台清理
+func (c *Client) SetCleanupPolicy(policy CleanupPolicy) {
+	c.cleanupMu.Lock()
+	defer c.cleanupMu.Unlock()
+
+	if c.cleanupStop != nil {
+		c.cleanupStop()
+		c.cleanupStop = nil
+	}
+	if !policy.enabled() {
+		return
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = defaultCleanupInterval
+	}
+
+	ctx, cancel := context.WithCancel(c.lifetime)
+	c.cleanupStop = cancel
+	go c.cleanupLoop(ctx, policy)
+}
+
+// cleanupLoop 按间隔执行清理，直到 ctx 被取消
+func (c *Client) cleanupLoop(ctx context.Context, policy CleanupPolicy) {
+	ticker := time.NewTicker(policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := c.RunCleanup(ctx, policy)
+		if ctx.Err() != nil {
+			return
+		}
+		if policy.OnReport != nil {
+			policy.OnReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunCleanup 按策略执行一轮清理
+//
+// 先删除超过 MaxAge 的邮箱，剩余数量仍超过 MaxMailboxes 时再从最早创建的开始删除。
+// 逐页遍历全部邮箱，不受 ListMailboxes 的数量上限限制。正在使用的邮箱（未关闭的
+// 会话、邮箱池中的空闲邮箱和已取出的邮箱）和服务端没有返回创建时间的邮箱不会被
+// 删除，记录在 PurgeReport.Skipped 中，但仍计入 MaxMailboxes 的数量。
+func (c *Client) RunCleanup(ctx context.Context, policy CleanupPolicy) (*PurgeReport, error) {
+	inUse := make(map[string]bool)
+	for _, local := range c.localMailboxes() {
+		inUse[strings.ToLower(local.mailbox.Address)] = true
+	}
+
+	var candidates []Mailbox
+	total := 0
+	report := &PurgeReport{Failed: make(map[string]error)}
+	err := c.eachMailbox(ctx, func(mailbox Mailbox) {
+		total++
+		if inUse[strings.ToLower(mailbox.Address)] || mailbox.CreatedAt.IsZero() {
+			report.Skipped = append(report.Skipped, mailbox.Address)
+			return
+		}
+		candidates = append(candidates, mailbox)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list mailboxes failed: %w", err)
+	}
+
+	report.Matched = selectForCleanup(candidates, total, policy, time.Now())
+
+	c.deleteMatched(ctx, report, PurgeOptions{})
+	return report, nil
+}
+
+// selectForCleanup 从可以删除的邮箱中选出需要按策略删除的邮箱，total 为账号下的邮箱总数
+func selectForCleanup(mailboxes []Mailbox, total int, policy CleanupPolicy, now time.Time) []string {
+	sorted := make([]Mailbox, len(mailboxes))
+	copy(sorted, mailboxes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var selected []string
+	kept := sorted[:0]
+	cutoff := now.Add(-policy.MaxAge)
+	for _, mailbox := range sorted {
+		if policy.MaxAge > 0 && mailbox.CreatedAt.Before(cutoff) {
+			selected = append(selected, mailbox.Address)
+		} else {
+			kept = append(kept, mailbox)
+		}
+	}
+
+	// kept 按创建时间升序排列，超出上限的部分从最早的开始删除（不能删除的邮箱同样占用名额）
+	if excess := total - len(selected) - policy.MaxMailboxes; policy.MaxMailboxes > 0 && excess > 0 {
+		for _, mailbox := range kept[:min(excess, len(kept))] {
+			selected = append(selected, mailbox.Address)
+		}
+	}
+	return selected
+}
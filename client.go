@@ -0,0 +1,1045 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client 表示一个 Mail2 API 客户端
+//
+// Client 持有服务地址和认证信息，所有包级函数（CreateMailbox、GetMails 等）
+// 内部都会通过 Client 发送请求。Client 可以安全地在多个 goroutine 中共享。
+//
+// 示例:
+//   client := mail2sdk.NewClient("https://mail.cwn.cc", "your-api-key")
+//   mailbox, err := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+type Client struct {
+	baseURL   string            // API 基础地址（已规范化，包含 WithBasePath 设置的前缀）
+	basePath  string            // API 路径前缀（见 WithBasePath）
+	mirrors   []string          // 额外的 API 地址（见 WithEndpoints）
+	endpoints *endpointSet      // 全部 API 地址及其统计（nil 表示只有 baseURL）
+	apiKey    string            // API 密钥
+	transport http.RoundTripper // 自定义传输层（nil 表示使用默认传输层）
+	dial      DialFunc          // 自定义连接函数（见 WithDialer）
+	resolver  *net.Resolver     // 自定义 DNS 解析器（见 WithResolver）
+	retry     RetryPolicy       // 重试策略
+
+	mailboxToken  string          // 邮箱级访问令牌（用于读取邮件的接口）
+	headers       http.Header     // 每个请求附加的自定义请求头（见 WithHeader）
+	domainHeaders []domainHeaders // 按邮箱域名或 API 地址附加的请求头（见 WithDomainHeaders）
+	blacklist     []string        // 客户端级别的域名黑名单（见 WithBlacklist）
+	defaultMode   int             // ModeDefault 对应的生成模式（见 WithDefaultMode）
+
+	auditSink  AuditSink // 审计日志目标（nil 表示不记录）
+	auditActor string    // 审计记录中的调用方标识
+
+	eventHandler     func(ClientEvent) // 客户端事件回调
+	challengeHandler ChallengeHandler  // WAF/CDN 人机验证处理（nil 表示直接返回错误）
+	noiseFilter      *noiseFilter      // 噪音发件人过滤（nil 表示不过滤）
+	ocr              OCRProvider       // 图片验证码识别（nil 表示不识别）
+	latency          *LatencyTracker   // 邮件到达延迟统计（nil 表示不统计）
+
+	selector       *DomainSelector // 域名选择器（见 WithDomainSelector，默认每个客户端独立）
+	readOnly       bool            // 只读模式（拒绝修改数据的请求）
+	fastCreate     bool            // 设置黑名单时跳过预先获取域名列表（见 WithFastCreate）
+	cleanLinks     bool            // 清理确认链接中的追踪参数（见 WithLinkCleaning）
+	resolveLinks   bool            // 请求一次无法离线解开的追踪地址
+	names          *nameTemplate   // 邮箱用户名模板（nil 表示由服务端命名）
+	watchers       *watcherLimit   // 同时进行的监听、等待操作上限（nil 表示不限制）
+	redirectPolicy RedirectPolicy  // API 请求的重定向策略（见 WithRedirectPolicy）
+
+	cooldownWindow time.Duration // 等待验证码超时后域名的冷却时间（0 表示不冷却）
+	cooldownRamp   time.Duration // 冷却结束后逐步恢复的时间
+
+	redactors []Redactor     // 自定义脱敏规则
+	issued    issuedSecrets  // 服务端签发的邮箱令牌（同样会被脱敏）
+	logHook   func(LogEntry) // 日志回调
+
+	schemaDrift    *sync.Map // 已报告的 Schema 差异（nil 表示不校验）
+	strictDecoding bool      // 响应出现未知字段时报错（见 WithStrictDecoding）
+	envelope       *Envelope // 自定义响应结构（nil 表示标准结构，见 WithEnvelope）
+
+	caps      capabilities     // 服务端能力信息
+	rateLimit rateLimitTracker // 最新的限流状态
+	pollHint  int64            // 服务端建议的轮询间隔（纳秒，0 表示未给出）
+	quota     quotaTracker     // 最新的剩余配额
+	senders   senderTracker    // 监听到的邮件的发件人统计（见 SenderStats）
+	patterns  patternRegistry  // 按名称注册的正则（见 RegisterPattern）
+
+	poolsMu sync.Mutex         // 保护 pools
+	pools   map[*Pool]struct{} // 该客户端创建的邮箱池
+
+	sessionsMu sync.Mutex            // 保护 sessions
+	sessions   map[*Session]struct{} // 尚未关闭的会话（见 Inventory）
+
+	cleanupMu   sync.Mutex         // 保护 cleanupStop
+	cleanupStop context.CancelFunc // 停止后台自动清理（nil 表示未启用）
+
+	closeOnce sync.Once          // 保证 Close 只执行一次
+	lifetime  context.Context    // 客户端关闭时取消，后台任务都从它派生（见 Close）
+	shutdown  context.CancelFunc // 取消 lifetime
+
+	timeout         time.Duration // 默认请求超时时间（0 表示 30 秒，见 WithTimeout）
+	httpClient      *http.Client  // 由 NewClient 构建或由 WithHTTPClient 指定（创建时的超时，见 settings）
+	userAgentSuffix string        // 附加在 User-Agent 之后的应用标识（见 WithUserAgentSuffix）
+	locale          Locale        // ErrorMessage 使用的语言（见 WithLocale）
+
+	// live 运行中使用的 API 密钥、超时、黑名单等配置（见 Reload）。上面由配置项设置的
+	// 同名字段只是创建时的初始值，发送请求时一律通过 settings() 读取
+	live atomic.Pointer[reloadable]
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithTransport 设置自定义 HTTP 传输层
+//
+// 可用于接入代理、请求埋点，或配合 ChaosTransport 进行故障注入测试。
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// DefaultHTTPClient 未使用 WithHTTPClient 时所有客户端共用的 HTTP 客户端
+//
+// 为 nil 时每个客户端各自构建一个 HTTP 客户端（共享同一个连接池）。包级函数（CreateMailbox、
+// GetMails 等）每次调用都会创建新的客户端，需要让它们走代理或接入埋点时可以设置该变量。
+// 应在发起任何请求之前设置。
+var DefaultHTTPClient *http.Client
+
+// WithHTTPClient 使用调用方提供的 HTTP 客户端发送请求
+//
+// 适用于需要自定义代理、TLS 配置或请求埋点的场景，客户端的超时设置同样生效。
+// 同时使用 WithTransport 或 WithDryRun 时，SDK 会复制该 HTTP 客户端并把传输层
+// 替换为它们指定的传输层。
+//
+// 示例:
+//   proxyURL, _ := url.Parse("http://proxy.corp.example:3128")
+//   hc := &http.Client{
+//       Timeout:   15 * time.Second,
+//       Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+//   }
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithHTTPClient(hc))
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetry 设置请求重试策略
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithRetry(mail2sdk.RetryPolicy{
+//       MaxRetries: 3,
+//       BaseDelay:  200 * time.Millisecond,
+//       MaxDelay:   5 * time.Second,
+//   }))
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithMailboxToken 使用邮箱级访问令牌读取邮件
+//
+// 部分服务端版本在创建邮箱时会返回 Mailbox.AccessToken。设置后 GetMails、
+// GetMailDetail、ExtractCode 等读取邮件的接口会携带 X-Mailbox-Token 请求头，
+// 可以在不共享主 API 密钥的情况下把某个邮箱的读取权限委托给他人。
+//
+// 示例:
+//   // 只持有邮箱令牌的一方（apiKey 传空字符串）
+//   reader := mail2sdk.NewClient(baseURL, "", mail2sdk.WithMailboxToken(mailbox.AccessToken))
+//   mails, err := reader.GetMails(ctx, mailbox.Address)
+func WithMailboxToken(token string) Option {
+	return func(c *Client) {
+		c.mailboxToken = token
+	}
+}
+
+// WithBlacklist 设置客户端级别的域名黑名单
+//
+// 自动选择域名时（CreateMailbox 未指定域名、CreateMailboxWithDomains 等），黑名单会与
+// 每次调用传入的 blacklist 合并生效。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithBlacklist("eu.org", "edu.kg"))
+//   mailbox, _ := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil) // 不会使用 eu.org 和 edu.kg
+func WithBlacklist(domains ...string) Option {
+	return func(c *Client) {
+		c.blacklist = append(c.blacklist, domains...)
+	}
+}
+
+// WithDefaultMode 设置客户端的默认生成模式
+//
+// 调用创建邮箱的接口时传入 ModeDefault 即使用该模式，便于由配置文件（见 LoadConfig）
+// 统一决定邮箱的命名风格。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithDefaultMode(mail2sdk.ModeEnglish))
+//   mailbox, _ := client.CreateMailbox(ctx, mail2sdk.ModeDefault, "", nil) // 英文名邮箱
+func WithDefaultMode(mode int) Option {
+	return func(c *Client) {
+		c.defaultMode = mode
+	}
+}
+
+// resolveMode 将 ModeDefault 替换为客户端的默认模式
+func (c *Client) resolveMode(mode int) int {
+	if mode == ModeDefault {
+		return c.settings().defaultMode
+	}
+	return mode
+}
+
+// mergeBlacklist 合并客户端级别和调用传入的黑名单
+func (c *Client) mergeBlacklist(blacklist []string) []string {
+	own := c.settings().blacklist
+	if len(own) == 0 {
+		return blacklist
+	}
+	merged := make([]string, 0, len(own)+len(blacklist))
+	merged = append(merged, own...)
+	return append(merged, blacklist...)
+}
+
+// NewClient 创建 API 客户端
+//
+// 参数:
+//   baseURL: API 基础地址（如: "https://mail.cwn.cc"，可以包含路径前缀，末尾的 "/" 会被去掉）
+//   apiKey: API 密钥
+//   opts: 可选配置项
+//
+// 返回:
+//   *Client: 客户端实例
+//
+// NewClient 不校验 baseURL，地址有误时第一次请求才会失败。需要在创建时发现配置问题
+// 时使用 NewClientChecked（校验地址）或 Connect（校验地址并检查服务端是否可用）。
+func NewClient(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: normalizeBaseURL(baseURL),
+		apiKey:  apiKey,
+	}
+	c.lifetime, c.shutdown = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.baseURL += normalizeBasePath(c.basePath)
+	c.endpoints = newEndpointSet(c.baseURL, c.mirrors, c.basePath)
+	if c.selector == nil {
+		c.selector = NewDomainSelector()
+	}
+
+	// 预先构建 HTTP 客户端和固定请求头，避免每次请求重复分配
+	if c.httpClient == nil {
+		c.httpClient = DefaultHTTPClient
+	}
+	if c.httpClient == nil {
+		// 未自定义传输层时使用共享传输层，所有客户端复用同一个连接池
+		transport := c.transport
+		if transport == nil {
+			transport = sharedTransport
+		}
+		timeout := c.timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		c.httpClient = &http.Client{Timeout: timeout, Transport: transport}
+	} else {
+		// 复制调用方的 HTTP 客户端，下面设置重定向检查时不影响原对象
+		hc := *c.httpClient
+		if c.transport != nil {
+			hc.Transport = c.transport
+		}
+		if c.timeout > 0 {
+			hc.Timeout = c.timeout
+		}
+		c.httpClient = &hc
+	}
+	c.httpClient.Transport = c.dialTransport(c.httpClient.Transport)
+	c.httpClient.CheckRedirect = c.checkRedirect(c.httpClient.CheckRedirect)
+	c.live.Store(c.snapshot(c.httpClient))
+	return c
+}
+
+// BaseURL 返回客户端使用的 API 基础地址（规范化后，包含路径前缀；设置了 WithEndpoints 时为主地址）
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// 预先构建的请求头值（只读，所有请求共享）
+var (
+	userAgentHeader   = []string{"Mail2SDK-Go/" + Version}
+	contentTypeHeader = []string{"application/json"}
+)
+
+// bufferPool 复用请求体和响应体的缓冲区
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// 超过该大小的缓冲区不放回池中，避免偶发的大响应长期占用内存
+const maxPooledBufferSize = 64 << 10
+
+// getBuffer 从池中获取一个空缓冲区
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer 将缓冲区放回池中
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// newRequest 构建带认证信息的 HTTP 请求
+//
+// body 为已编码的 JSON 请求体（nil 表示无请求体）。
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+
+	// 直接写入规范化后的键，省去 Header.Set 的规范化开销
+	req.Header["Content-Type"] = contentTypeHeader
+	c.applyHeaders(req, true)
+	c.setAuthHeaders(req)
+	return req, nil
+}
+
+// setAuthHeaders 设置认证和标识请求头
+//
+// 未配置 API 密钥时（仅持有邮箱令牌）不发送 X-API-Key。
+func (c *Client) setAuthHeaders(req *http.Request) {
+	s := c.settings()
+	if s.apiKey != "" {
+		req.Header["X-Api-Key"] = s.apiKeyHeader
+	}
+	if token := c.mailboxTokenFor(req.Context()); token != "" {
+		req.Header["X-Mailbox-Token"] = []string{token}
+	}
+	req.Header["User-Agent"] = s.userAgentHeader
+}
+
+// send 发送 HTTP 请求
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	return c.sendWith(c.httpClientFor(req), req)
+}
+
+// sendWith 使用指定的 HTTP 客户端发送请求
+func (c *Client) sendWith(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	c.observeEndpoint(req, time.Since(start), resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// do 执行 API 请求并解析标准响应
+//
+// result 为 nil 时只检查 HTTP 状态码，不解析响应体。失败时按重试策略重试。
+// 返回的错误已经过脱敏处理。
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, result interface{}) (err error) {
+	if c.Closed() {
+		return ErrClientClosed
+	}
+	if c.auditSink != nil {
+		start := time.Now()
+		defer func() { c.recordAudit(ctx, method, path, start, err) }()
+	}
+	// 在审计记录之前脱敏（defer 按后进先出执行）
+	defer func() { err = c.redactError(err) }()
+
+	// 只读模式下拒绝修改数据的请求（同样会被审计记录）
+	if err := c.checkReadOnly(method, path); err != nil {
+		return err
+	}
+
+	// 计入耗时预算（预算耗尽的错误同样会被审计记录）
+	if budget := budgetFrom(ctx); budget != nil {
+		var finish func(error) error
+		ctx, finish, err = budget.begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { err = finish(err) }()
+	}
+
+	// 请求体只编码一次，重试时复用
+	var encoded []byte
+	if body != nil {
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
+			return fmt.Errorf("marshal request body failed: %w", err)
+		}
+		encoded = buf.Bytes()
+	}
+
+	// 整个调用使用同一份重试策略，调用过程中 Reload 不影响已经开始的重试
+	retry := c.settings().retry
+	decodeRetried, challengeHandled := false, false
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.doOnce(ctx, method, path, encoded, result, attempt)
+		if err == nil {
+			return nil
+		}
+		if !retryable {
+			// 被人机验证拦截时交给 ChallengeHandler，处理成功后立即重新发送
+			resend, err := c.handleChallenge(ctx, err, &challengeHandled)
+			if !resend {
+				return err
+			}
+			countRetry(ctx)
+			continue
+		}
+		if attempt >= retry.MaxRetries {
+			// 响应解析失败时即使没有配置重试（或重试次数已用完）也再试一次
+			var decodeErr *DecodeError
+			if decodeRetried || !errors.As(err, &decodeErr) {
+				return err
+			}
+			decodeRetried = true
+		}
+
+		if sleepContext(ctx, retry.delay(attempt)) != nil {
+			return err
+		}
+		countRetry(ctx)
+	}
+}
+
+// doOnce 执行一次 API 请求
+//
+// 返回的 retryable 表示该错误是否值得重试。attempt 为第几次尝试，仅用于日志。
+func (c *Client) doOnce(ctx context.Context, method, path string, body []byte, result interface{}, attempt int) (retryable bool, err error) {
+	s := c.settings()
+	reqCtx, cancel := c.attemptContext(ctx, s)
+	defer cancel()
+
+	req, err := c.newRequest(reqCtx, method, path, body)
+	if err != nil {
+		return false, err
+	}
+
+	status := 0
+	if c.logHook != nil {
+		start := time.Now()
+		defer func() {
+			entry := LogEntry{
+				Level:    LogDebug,
+				Message:  "request completed",
+				Method:   method,
+				URL:      req.URL.String(),
+				Status:   status,
+				Attempt:  attempt,
+				Duration: time.Since(start),
+				Header:   req.Header,
+				Err:      err,
+			}
+			if err != nil {
+				entry.Level, entry.Message = LogError, "request failed"
+				if retryable && attempt < c.settings().retry.MaxRetries {
+					entry.Level, entry.Message = LogWarn, "request failed, will retry"
+				}
+			}
+			c.log(ctx, entry)
+		}()
+	}
+
+	resp, err := c.sendWith(s.sendClient, req)
+	if err != nil {
+		// 网络错误：幂等请求可以安全重试（被重定向到登录页时重试没有意义）
+		return ctx.Err() == nil && isIdempotent(method) && !errors.Is(err, ErrRedirectedToLogin), err
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+	c.observeRateLimit(resp.Header)
+	c.observePollInterval(resp.Header)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return isIdempotent(method), fmt.Errorf("read response failed: %w", err)
+	}
+	respBody := buf.Bytes()
+
+	// WAF/CDN 的验证页面可能以任意状态码返回，先于状态码检查识别
+	if provider := detectChallenge(resp.Header, respBody); provider != "" {
+		return false, &ChallengeError{
+			Provider:   provider,
+			StatusCode: resp.StatusCode,
+			URL:        c.redact(req.URL.String()),
+			Header:     resp.Header.Clone(),
+			Body:       c.redact(truncateBody(respBody, maxDecodeErrorBody)),
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := c.redirectError(resp, respBody); err != nil {
+			return false, err
+		}
+		err := &APIError{StatusCode: resp.StatusCode, Message: c.redact(string(respBody))}
+		return isRetryableStatus(method, resp.StatusCode), err
+	}
+
+	if c.schemaDrift != nil {
+		c.checkSchema(ctx, method, path, respBody)
+	}
+
+	if capture := responseCaptureFrom(ctx); capture != nil {
+		capture.capture(resp, respBody)
+	}
+
+	if result == nil {
+		return false, nil
+	}
+
+	switch {
+	case c.envelope != nil:
+		err = c.envelope.decode(respBody, result, c.strictDecoding)
+	case c.strictDecoding:
+		err = decodeEnvelopeStrict(respBody, result)
+	default:
+		err = decodeEnvelope(respBody, result)
+	}
+	if err == nil {
+		return false, nil
+	}
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.Message = c.redact(apiErr.Message)
+		return false, err
+	}
+
+	// 响应无法解析（多为 CDN 返回的 HTML 页面），附上原始响应便于排查；
+	// 响应体根本不是 JSON 时往往是暂时的，幂等请求可以重试
+	return isIdempotent(method) && !json.Valid(respBody), &DecodeError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        c.redact(truncateBody(respBody, maxDecodeErrorBody)),
+		Err:         err,
+	}
+}
+
+// responseEnvelope 标准响应的外层结构，Data 指向调用方的结果
+type responseEnvelope struct {
+	Code int         `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data"`
+}
+
+// envelopePool 复用 decodeEnvelope 的外层结构
+var envelopePool = sync.Pool{
+	New: func() interface{} { return new(responseEnvelope) },
+}
+
+// decodeEnvelope 解析标准响应并将 data 字段写入 result
+//
+// 正常情况下只解析一次：data 直接解码到 result 中。只有解析失败时才会
+// 退回到逐层解析，以便优先返回 API 错误而不是数据格式错误。
+func decodeEnvelope(respBody []byte, result interface{}) error {
+	envelope := envelopePool.Get().(*responseEnvelope)
+	*envelope = responseEnvelope{Data: result}
+	err := json.Unmarshal(respBody, envelope)
+	code, msg := envelope.Code, envelope.Msg
+	*envelope = responseEnvelope{}
+	envelopePool.Put(envelope)
+
+	if err == nil {
+		if code != 0 && code != 200 {
+			return &APIError{Code: code, Message: msg}
+		}
+		return nil
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("parse response failed: %w", err)
+	}
+
+	if apiResp.Code != 0 && apiResp.Code != 200 {
+		return &APIError{Code: apiResp.Code, Message: apiResp.Msg}
+	}
+
+	if len(apiResp.Data) > 0 {
+		if err := json.Unmarshal(apiResp.Data, result); err != nil {
+			return fmt.Errorf("parse data failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Do 调用任意 API 接口并将响应的 data 字段解析为 T
+//
+// 用于调用 SDK 尚未封装的新接口或未公开接口，复用 SDK 的认证、响应解析（包括
+// WithEnvelope、WithStrictDecoding）、重试、限流和审计逻辑。Go 的方法不能带类型参数，
+// 因此以客户端作为第一个普通参数。
+//
+// 参数:
+//   ctx: 上下文
+//   c: 客户端
+//   method: HTTP 方法（如: "GET"）
+//   path: 接口路径（如: "/api/domains"）
+//   body: 请求体（会被编码为 JSON，传 nil 表示无请求体）
+//
+// 返回:
+//   T: 解析后的 data 字段
+//   error: 错误信息
+//
+// 示例:
+//   type quota struct {
+//       Remaining int `json:"remaining"`
+//   }
+//   q, err := mail2sdk.Do[quota](ctx, client, "GET", "/api/quota", nil)
+func Do[T any](ctx context.Context, c *Client, method, path string, body interface{}) (T, error) {
+	var result T
+	err := c.do(ctx, method, path, body, &result)
+	return result, err
+}
+
+// DoRaw 发送原始 HTTP 请求（自动补全基础地址和认证头）
+//
+// req.URL 为相对路径时会拼接到客户端的基础地址之后；为绝对地址时必须指向基础地址
+// 或 WithEndpoints 设置的地址所在的主机，否则返回错误，避免把 API 密钥和自定义
+// 请求头发往其他主机。返回的响应不做任何解析，调用方需要自行关闭 resp.Body。
+//
+// 示例:
+//   req, _ := http.NewRequest("GET", "/api/mailbox/test@example.com/raw", nil)
+//   resp, err := client.DoRaw(ctx, req)
+//   if err == nil {
+//       defer resp.Body.Close()
+//   }
+func (c *Client) DoRaw(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is required")
+	}
+	if c.Closed() {
+		return nil, ErrClientClosed
+	}
+
+	// 空的 Method 由 net/http 按 GET 发送
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if err := c.checkReadOnly(method, req.URL.Path); err != nil {
+		return nil, err
+	}
+	if req.URL.IsAbs() && !c.ownsHost(req.URL) {
+		return nil, fmt.Errorf("request host %q is not the client's base URL or endpoints", req.URL.Host)
+	}
+
+	req = req.Clone(ctx)
+	req.Method = method
+	if !req.URL.IsAbs() {
+		fullURL, err := url.Parse(c.url(req.URL.RequestURI()))
+		if err != nil {
+			return nil, fmt.Errorf("parse request URL failed: %w", err)
+		}
+		req.URL = fullURL
+		req.Host = fullURL.Host
+	}
+
+	c.applyHeaders(req, false)
+	if req.Header.Get("X-API-Key") == "" {
+		c.setAuthHeaders(req)
+	}
+
+	resp, err := c.send(req)
+	return resp, c.redactError(err)
+}
+
+// ownsHost 判断地址是否指向基础地址或 WithEndpoints 设置的地址所在的主机（协议也须一致）
+func (c *Client) ownsHost(u *url.URL) bool {
+	bases := []string{c.baseURL}
+	if c.endpoints != nil {
+		bases = bases[:0]
+		for _, e := range c.endpoints.list {
+			bases = append(bases, e.base)
+		}
+	}
+	for _, base := range bases {
+		b, err := url.Parse(base)
+		if err == nil && strings.EqualFold(b.Scheme, u.Scheme) && strings.EqualFold(b.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDomains 获取所有可用域名列表
+func (c *Client) GetDomains(ctx context.Context) ([]string, error) {
+	var result struct {
+		Records []struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		} `json:"records"`
+	}
+
+	if err := c.do(ctx, "GET", "/api/domains", nil, &result); err != nil {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(result.Records))
+	for _, d := range result.Records {
+		if d.Enabled {
+			domains = append(domains, d.Name)
+		}
+	}
+
+	return domains, nil
+}
+
+// CreateMailbox 创建临时邮箱
+//
+// 参数含义与包级函数 CreateMailbox 相同。自动选择域名时，如果服务端返回
+// "域名已禁用"，该域名会被移出自动选择并触发 EventDomainDisabled 事件，
+// 然后换一个域名重试。
+func (c *Client) CreateMailbox(ctx context.Context, mode int, domain string, blacklist []string) (*Mailbox, error) {
+	apiMode := apiModeName(c.resolveMode(mode))
+	blacklist = c.mergeBlacklist(blacklist)
+
+	// 如果没有指定域名但有黑名单或域名选择 key，需要从可用域名中选择
+	_, hasKey := domainKeyFrom(ctx)
+	if domain == "" && len(blacklist) > 0 && !hasKey && c.fastCreate {
+		mailbox, ok, err := c.createFast(ctx, apiMode, blacklist)
+		if ok {
+			return mailbox, err
+		}
+	}
+	if domain == "" && (len(blacklist) > 0 || hasKey) {
+		allDomains, err := c.GetDomains(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get domains failed: %w", err)
+		}
+
+		filtered := filterDomains(allDomains, blacklist)
+		if len(filtered) == 0 {
+			return nil, fmt.Errorf("%w after blacklist filtering", ErrNoDomainsAvailable)
+		}
+
+		return c.createWithSelection(ctx, apiMode, filtered)
+	}
+
+	mailbox, err := c.createMailbox(ctx, apiMode, domain, nil)
+	if err != nil && domain != "" && isDomainDisabledError(err) {
+		c.disableDomain(ctx, domain, err)
+	}
+	return mailbox, err
+}
+
+// apiModeName 将模式常量转换为 API 使用的模式名称
+func apiModeName(mode int) string {
+	switch mode {
+	case ModeRandom:
+		return "random"
+	case ModeChinese:
+		return "chinese"
+	case ModeEnglish:
+		return "english"
+	case ModeAuto: // 自动混用
+		modes := []string{"random", "chinese", "english"}
+		return modes[randIntn(3)]
+	default:
+		return "random"
+	}
+}
+
+// createWithSelection 从候选域名中选择一个创建邮箱
+//
+// 使用轮询策略选择域名（确保所有域名均匀使用），上下文中有域名选择 key 时
+// 按 key 确定性地选择（见 WithDomainKey）。所选域名已被禁用时，
+// 将其移出自动选择并换下一个域名重试，直到成功或没有可用域名。
+func (c *Client) createWithSelection(ctx context.Context, apiMode string, domains []string) (*Mailbox, error) {
+	selector := c.selector
+
+	key, hasKey := domainKeyFrom(ctx)
+
+	var lastErr error
+	for {
+		var domain string
+		if hasKey {
+			domain = selector.selectDomainByKey(domains, key)
+		} else {
+			domain = selector.selectDomain(domains)
+		}
+		if domain == "" {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last error: %w)", ErrNoDomainsAvailable, lastErr)
+			}
+			return nil, ErrNoDomainsAvailable
+		}
+
+		mailbox, err := c.createMailbox(ctx, apiMode, domain, nil)
+		if err == nil || !isDomainDisabledError(err) {
+			return mailbox, err
+		}
+
+		c.disableDomain(ctx, domain, err)
+		lastErr = err
+	}
+}
+
+// disableDomain 将域名移出自动选择并触发事件
+func (c *Client) disableDomain(ctx context.Context, domain string, err error) {
+	if c.selector.disable(domain) {
+		c.emit(ctx, ClientEvent{Type: EventDomainDisabled, Domain: domain, Err: err})
+	}
+}
+
+// createMailboxRequest 创建邮箱的请求体
+type createMailboxRequest struct {
+	Mode           string   `json:"mode"`
+	Domain         string   `json:"domain,omitempty"`
+	ExcludeDomains []string `json:"exclude_domains,omitempty"`
+	Username       string   `json:"username,omitempty"`
+}
+
+// createMailbox 发送创建邮箱请求（domain 为空时由服务端随机选择，并避开 exclude 中的域名）
+func (c *Client) createMailbox(ctx context.Context, apiMode, domain string, exclude []string) (*Mailbox, error) {
+	// 构建请求体
+	reqBody := createMailboxRequest{Mode: apiMode}
+
+	// 如果指定了域名
+	if domain != "" {
+		reqBody.Domain = domain
+	} else {
+		reqBody.ExcludeDomains = exclude
+	}
+
+	// 按模板生成用户名（见 WithNameTemplate）
+	username, err := c.templateUsername(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reqBody.Username = username
+
+	// 只记录创建请求本身的响应，不包括选择域名时的 GetDomains 等请求
+	capture := &responseCapture{dataKey: c.envelopeOrDefault().DataKey}
+	reqCtx := withResponseCapture(ctx, capture)
+
+	if domain != "" {
+		reqCtx = withTargetDomain(reqCtx, domain)
+	}
+
+	var mailbox Mailbox
+	if err := c.do(reqCtx, "POST", "/api/mailbox", &reqBody, &mailbox); err != nil {
+		return nil, err
+	}
+
+	c.issued.add(mailbox.AccessToken)
+	c.recordQuota(capture.receipt.QuotaRemaining)
+	if dst := receiptFrom(ctx); dst != nil {
+		*dst = capture.receipt
+	}
+
+	return &mailbox, nil
+}
+
+// CreateMailboxWithDomains 从指定域名组中选择一个创建邮箱
+//
+// 参数含义与包级函数 CreateMailboxWithDomains 相同。
+func (c *Client) CreateMailboxWithDomains(ctx context.Context, mode int, domains []string, blacklist []string) (*Mailbox, error) {
+	if len(domains) == 0 {
+		return c.CreateMailbox(ctx, mode, "", blacklist)
+	}
+
+	// 过滤黑名单域名
+	filtered := filterDomains(domains, c.mergeBlacklist(blacklist))
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("%w after blacklist filtering", ErrNoDomainsAvailable)
+	}
+
+	return c.createWithSelection(ctx, apiModeName(c.resolveMode(mode)), filtered)
+}
+
+// GetMails 获取邮箱的邮件列表
+//
+// 可以通过 WithSince、WithLimit、WithFolder 等 CallOption 缩小结果范围。
+func (c *Client) GetMails(ctx context.Context, address string, opts ...CallOption) ([]Mail, error) {
+	o := newCallOptions(opts)
+	return c.listMails(ctx, address, o.folder, o)
+}
+
+// listMails 获取邮箱某个文件夹的邮件列表（folder 为空表示收件箱，且不标注 Folder 字段）
+func (c *Client) listMails(ctx context.Context, address, folder string, o callOptions) ([]Mail, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/api/mailbox/" + escaped + "/mails"
+	if folder != "" && folder != FolderInbox {
+		escapedFolder, err := escapePathSegment("folder", folder)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.requireFeature(ctx, FeatureFolders); err != nil {
+			return nil, err
+		}
+		path = "/api/mailbox/" + escaped + "/folders/" + escapedFolder + "/mails"
+	}
+
+	list := mailJSONPool.Get().(*[]mailJSON)
+	result := struct {
+		Count int        `json:"count"`
+		Mails []mailJSON `json:"mails"`
+	}{Mails: *list}
+
+	ctx = withMailboxRead(ctx)
+	err = c.do(ctx, "GET", path+o.query(), nil, &result)
+	*list = result.Mails
+	if err != nil {
+		decodeMailList(list)
+		return nil, err
+	}
+
+	mails := o.filterMails(decodeMailList(list))
+	if folder != "" {
+		mails = withFolder(mails, folder)
+	}
+	return mails, nil
+}
+
+// GetMailDetail 获取邮件的完整详情
+//
+// 启用了邮件详情缓存（EnableMailDetailCache）时会优先读取缓存。服务端可能截断
+// 特别大的正文，此时 Truncated 为 true，需要完整内容时使用 GetMailDetailFull。
+func (c *Client) GetMailDetail(ctx context.Context, address, mailID string) (*MailDetail, error) {
+	return c.getMailDetail(ctx, address, mailID, false)
+}
+
+// GetMailDetailFull 获取邮件的完整详情，要求服务端不截断正文
+//
+// 缓存中的详情已被截断时会重新获取。旧版本服务端不支持该参数时返回的结果与
+// GetMailDetail 相同，可以通过 Truncated 判断。
+//
+// 示例:
+//   detail, err := client.GetMailDetail(ctx, address, mailID)
+//   if err == nil && detail.Truncated {
+//       detail, err = client.GetMailDetailFull(ctx, address, mailID)
+//   }
+func (c *Client) GetMailDetailFull(ctx context.Context, address, mailID string) (*MailDetail, error) {
+	return c.getMailDetail(ctx, address, mailID, true)
+}
+
+// getMailDetail 获取邮件详情，full 为 true 时要求服务端返回未截断的正文
+func (c *Client) getMailDetail(ctx context.Context, address, mailID string, full bool) (*MailDetail, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+	escapedID, err := escapePathSegment("mailID", mailID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 邮件详情不可变，命中缓存时直接返回（要求完整内容时跳过已截断的缓存）
+	cache := getDetailCache()
+	var cacheKey string
+	if cache != nil {
+		cacheKey = detailCacheKey(c.baseURL, address, c.credentialFingerprint(ctx), mailID)
+		if detail, ok := cache.get(cacheKey); ok && (!full || !detail.Truncated) {
+			return detail, nil
+		}
+	}
+
+	path := "/api/mailbox/" + escaped + "/mails/" + escapedID
+	if full {
+		path += "?full=true"
+	}
+
+	var decoded mailDetailJSON
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "GET", path, nil, &decoded); err != nil {
+		return nil, err
+	}
+
+	detail := decoded.detail()
+	if cache != nil {
+		cache.put(cacheKey, detail)
+	}
+
+	return detail, nil
+}
+
+// detailForExtraction 获取用于提取内容的邮件详情，正文被截断时自动获取完整内容
+func (c *Client) detailForExtraction(ctx context.Context, address, mailID string) (*MailDetail, error) {
+	detail, err := c.GetMailDetail(ctx, address, mailID)
+	if err != nil || !detail.Truncated {
+		return detail, err
+	}
+	return c.GetMailDetailFull(ctx, address, mailID)
+}
+
+// ExtractCode 提取验证码（使用 API 内置算法）
+func (c *Client) ExtractCode(ctx context.Context, address string, maxMails int) (*CodeResult, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 一次拼接出完整路径
+	var path string
+	if maxMails > 0 {
+		path = "/api/mailbox/" + escaped + "/code?max_mails=" + strconv.Itoa(maxMails)
+	} else {
+		path = "/api/mailbox/" + escaped + "/code"
+	}
+
+	var result CodeResult
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteMailbox 删除邮箱及其所有邮件
+//
+// 注意: 此操作不可逆！
+func (c *Client) DeleteMailbox(ctx context.Context, address string) error {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return err
+	}
+
+	path := "/api/mailbox/" + escaped
+
+	if err := c.do(ctx, "DELETE", path, nil, nil); err != nil {
+		return err
+	}
+
+	// 邮箱已删除，清理其缓存的邮件详情
+	if cache := getDetailCache(); cache != nil {
+		cache.removeAddress(c.baseURL, address)
+	}
+
+	return nil
+}
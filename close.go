@@ -0,0 +1,71 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrClientClosed 表示客户端已经调用过 Close
+var ErrClientClosed = errors.New("client closed")
+
+// Close 关闭客户端并释放它持有的资源
+//
+// 关闭后：
+//   - 正在进行的 Watch 会关闭事件通道，WaitForCode 等等待调用返回 ErrClientClosed；
+//   - 后台自动清理（SetCleanupPolicy）和会话令牌刷新（EnableTokenRefresh）停止；
+//   - 之后发起的请求直接返回 ErrClientClosed；
+//   - 审计目标实现了 Flush() error 或 io.Closer 时会被调用，写出缓冲中的记录；
+//   - 客户端独占的传输层中的空闲连接被关闭（共享传输层和 http.DefaultTransport
+//     会被其他客户端使用，不受影响）。
+//
+// 邮箱池中的空闲邮箱不会被删除：需要保留时先调用 Pool.Save，不再需要时先调用
+// Pool.Close。重复调用 Close 是安全的，只有第一次调用会返回错误。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey)
+//   defer client.Close()
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.shutdown()
+		c.SetCleanupPolicy(CleanupPolicy{})
+
+		if flusher, ok := c.auditSink.(interface{ Flush() error }); ok {
+			err = flusher.Flush()
+		}
+		if closer, ok := c.auditSink.(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		if transport := c.httpClient.Transport; transport != nil && transport != sharedTransport && transport != http.DefaultTransport {
+			c.httpClient.CloseIdleConnections()
+		}
+	})
+	return err
+}
+
+// Closed 判断客户端是否已经关闭
+func (c *Client) Closed() bool {
+	return c.lifetime.Err() != nil
+}
+
+// bindLifetime 返回在 ctx 结束或客户端关闭时取消的上下文
+//
+// 用于 Watch、等待验证码等长时间运行的调用，使 Close 能让它们及时退出。
+func (c *Client) bindLifetime(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.lifetime, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// stopPolling 判断轮询遇到的错误是否意味着继续轮询也只会立即失败
+func stopPolling(err error) bool {
+	return errors.Is(err, ErrBudgetExceeded) || errors.Is(err, ErrClientClosed)
+}
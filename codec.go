@@ -0,0 +1,159 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Codec 持久化数据的序列化方式
+//
+// SDK 写入 Store 或归档的结构化数据（邮箱池状态、Webhook 重试队列、归档快照）默认使用
+// JSON，可以在对应组件的配置中换成更紧凑的编码，或用 GzipCodec 压缩。
+type Codec interface {
+	// Name 编码名称，用作归档文件的扩展名（如 "json"、"gob"、"json.gz"）
+	Name() string
+	// Marshal 编码 v
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal 将 data 解码到 v（v 为指针）
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// 内置编码
+var (
+	JSONCodec Codec = jsonCodec{} // JSON（默认，便于人工查看）
+	GobCodec  Codec = gobCodec{}  // encoding/gob 二进制编码（体积更小，只适合 Go 程序读取）
+)
+
+// jsonCodec JSON 编码
+type jsonCodec struct{}
+
+// Name 实现 Codec 接口
+func (jsonCodec) Name() string { return "json" }
+
+// Marshal 实现 Codec 接口
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal 实现 Codec 接口
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// gobCodec encoding/gob 编码
+type gobCodec struct{}
+
+// Name 实现 Codec 接口
+func (gobCodec) Name() string { return "gob" }
+
+// Marshal 实现 Codec 接口
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal 实现 Codec 接口
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// gzipMagic gzip 数据的前两个字节
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// GzipCodec 返回先用 inner 编码、再用 gzip 压缩的编码
+//
+// 邮件的 HTML 正文重复内容多，压缩后通常只有原来的几分之一。解码时遇到未压缩的
+// 数据会直接交给 inner 解码，已有的未压缩数据可以继续读取。
+//
+// 示例:
+//   exporter := archive.NewExporter(client, sink, archive.Options{
+//       Codec: mail2sdk.GzipCodec(mail2sdk.GobCodec), // 快照保存为 .gob.gz
+//   })
+func GzipCodec(inner Codec) Codec {
+	return gzipCodec{inner: inner}
+}
+
+// gzipCodec gzip 压缩编码
+type gzipCodec struct {
+	inner Codec
+}
+
+// Name 实现 Codec 接口
+func (c gzipCodec) Name() string { return c.inner.Name() + ".gz" }
+
+// Marshal 实现 Codec 接口
+func (c gzipCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("gzip failed: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gzip failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal 实现 Codec 接口
+func (c gzipCodec) Unmarshal(data []byte, v interface{}) error {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return c.inner.Unmarshal(data, v)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return c.inner.Unmarshal(data, v)
+	}
+	defer zr.Close()
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("gunzip failed: %w", err)
+	}
+	return c.inner.Unmarshal(plain, v)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{"json": JSONCodec, "gob": GobCodec}
+)
+
+// RegisterCodec 注册自定义编码，使 LookupCodec（以及归档回放）可以按名称找到它
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.Name()] = codec
+}
+
+// LookupCodec 按名称查找编码（不存在时返回 nil）
+//
+// 名称以 ".gz" 结尾时返回对应编码的 GzipCodec，如 "json.gz"。
+func LookupCodec(name string) Codec {
+	if inner := strings.TrimSuffix(name, ".gz"); inner != name {
+		if codec := LookupCodec(inner); codec != nil {
+			return GzipCodec(codec)
+		}
+		return nil
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return codecs[name]
+}
+
+// codecOrJSON 未指定编码时使用 JSON
+func codecOrJSON(codec Codec) Codec {
+	if codec == nil {
+		return JSONCodec
+	}
+	return codec
+}
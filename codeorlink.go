@@ -0,0 +1,126 @@
+package mail2sdk
+
+import (
+	"context"
+	"regexp"
+)
+
+// 确认类链接的常见关键词（用于区分确认链接和页脚、图片等普通链接）
+var confirmLinkPattern = regexp.MustCompile(`(?i)verif|confirm|activat|magic|token|validate|login|signin|sign-in|auth`)
+
+// 邮件同时包含确认链接时，验证码候选要求的最低置信度
+const minCodeScoreWithLink = 0.7
+
+// CodeOrLinkKind WaitForCodeOrLink 的结果类型
+type CodeOrLinkKind int
+
+// 结果类型常量
+const (
+	KindCode CodeOrLinkKind = iota + 1 // 收到验证码
+	KindLink                           // 收到确认链接
+)
+
+// String 返回结果类型名称
+func (k CodeOrLinkKind) String() string {
+	switch k {
+	case KindCode:
+		return "code"
+	case KindLink:
+		return "link"
+	default:
+		return "unknown"
+	}
+}
+
+// CodeOrLink WaitForCodeOrLink 的结果
+//
+// Kind 为 KindCode 时 Code 有效，为 KindLink 时 Link 有效。
+type CodeOrLink struct {
+	Kind       CodeOrLinkKind  // 结果类型
+	Code       string          // 验证码
+	Candidates []CodeCandidate // 验证码候选（按置信度降序）
+	Link       string          // 确认链接
+	Mail       *MailDetail     // 包含验证码或链接的邮件
+}
+
+// WaitForCodeOrLink 等待验证码或确认链接，先到先得
+//
+// 部分服务有时发送验证码、有时发送确认链接。该方法在同一次轮询中对每封新邮件
+// 同时尝试两种提取方式，第一封包含验证码或确认链接的邮件决定结果，流程中
+// 无需先后等待两次。同一封邮件中两者都有时，验证码置信度不低于 0.7 才返回验证码，
+// 否则返回链接。
+//
+// 确认链接指地址中包含 verify、confirm、activate、token、login 等关键词的链接，
+// 退订链接不参与匹配。设置了 WithLinkCleaning 时，点击追踪地址会先被解开再匹配，
+// 返回的 Link 为清理后的地址。
+//
+// 示例:
+//   result, err := client.WaitForCodeOrLink(ctx, address)
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+//   switch result.Kind {
+//   case mail2sdk.KindCode:
+//       submitCode(result.Code)
+//   case mail2sdk.KindLink:
+//       client.ConfirmLink(ctx, result.Mail, &mail2sdk.ConfirmOptions{Link: result.Link, Policy: policy})
+//   }
+func (c *Client) WaitForCodeOrLink(ctx context.Context, address string) (*CodeOrLink, error) {
+	var result *CodeOrLink
+	matcher := MatcherFunc(func(detail *MailDetail) bool {
+		link := c.confirmLink(ctx, detail)
+
+		if codes, text := c.mailCodes(ctx, address, detail, nil); len(codes) > 0 {
+			candidates := RankCodes(codes, text)
+			// 含确认链接的邮件中，页脚的年份、门牌号等数字也会成为候选，
+			// 只有置信度足够高时才视为验证码
+			if link == "" || candidates[0].Score >= minCodeScoreWithLink {
+				result = &CodeOrLink{Kind: KindCode, Code: candidates[0].Code, Candidates: candidates, Mail: detail}
+				return true
+			}
+		}
+		if link != "" {
+			result = &CodeOrLink{Kind: KindLink, Link: link, Mail: detail}
+			return true
+		}
+		return false
+	})
+
+	if _, err := c.WaitForMailMatching(ctx, address, matcher); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// confirmLink 返回邮件中的第一个确认类链接（没有时返回空字符串）
+func confirmLink(detail *MailDetail) string {
+	for _, link := range mailLinks(detail) {
+		if confirmLinkPattern.MatchString(link) && !unsubscribeLink(link) {
+			return link
+		}
+	}
+	return ""
+}
+
+// confirmLink 返回邮件中的第一个确认类链接，按客户端配置清理追踪地址
+func (c *Client) confirmLink(ctx context.Context, detail *MailDetail) string {
+	if !c.cleanLinks {
+		return confirmLink(detail)
+	}
+	resolved := 0
+	for _, link := range mailLinks(detail) {
+		if unsubscribeLink(link) {
+			continue
+		}
+		cleaned := CleanLink(link)
+		// 营销邮件中的追踪地址可能很多，只请求前几个
+		if c.resolveLinks && resolved < maxResolvedLinks && !confirmLinkPattern.MatchString(cleaned) && trackerLink(cleaned) {
+			resolved++
+			cleaned = c.cleanLink(ctx, link)
+		}
+		if confirmLinkPattern.MatchString(cleaned) && !unsubscribeLink(cleaned) {
+			return cleaned
+		}
+	}
+	return ""
+}
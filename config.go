@@ -0,0 +1,723 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig 表示配置文件或配置项不合法
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Config 客户端配置
+//
+// 可以由 LoadConfig 从 YAML/JSON 文件读取，也可以直接构造后调用 NewClient 方法。
+// 配置文件中的字段名为括号内的名称。
+type Config struct {
+	BaseURL      string        // API 基础地址（base_url，必填）
+	Endpoints    []string      // 额外的 API 地址（endpoints，见 WithEndpoints）
+	APIKey       string        // API 密钥（api_key）
+	MailboxToken string        // 邮箱级访问令牌（mailbox_token）
+	Timeout      time.Duration // 请求超时（timeout，如 "10s"，纯数字按秒计算；0 表示 30 秒）
+	Mode         int           // 默认生成模式（mode，auto/random/chinese/english 或 0-3，见 WithDefaultMode）
+	Blacklist    []string      // 域名黑名单（blacklist，见 WithBlacklist）
+	Retry        RetryPolicy   // 重试策略（retry.max_retries、retry.base_delay、retry.max_delay）
+	ReadOnly     bool          // 只读模式（read_only，见 WithReadOnly）
+	DryRun       bool          // 试运行模式（dry_run，见 WithDryRun）
+	MaxWatchers  int           // 同时进行的监听、等待操作上限（max_watchers，0 表示不限制，见 WithMaxWatchers）
+	WatcherQueue int           // 达到上限后允许排队的操作数（watcher_queue）
+
+	Headers         map[string]string // 每个请求附加的请求头（headers，见 WithHeader）
+	UserAgentSuffix string            // 附加在 User-Agent 之后的应用标识（user_agent_suffix，见 WithUserAgentSuffix）
+	Locale          Locale            // 错误提示的语言（locale，en 或 zh，见 WithLocale）
+}
+
+// configFields 配置文件支持的顶层字段
+var configFields = []string{
+	"base_url", "endpoints", "api_key", "mailbox_token", "timeout", "mode",
+	"blacklist", "retry", "read_only", "dry_run", "headers",
+	"user_agent_suffix", "locale", "max_watchers", "watcher_queue",
+}
+
+// retryConfigFields retry 下支持的字段
+var retryConfigFields = []string{"max_retries", "base_delay", "max_delay"}
+
+// modeNames 配置中可以使用的模式名称
+var modeNames = map[string]int{
+	"auto":    ModeAuto,
+	"random":  ModeRandom,
+	"chinese": ModeChinese,
+	"english": ModeEnglish,
+}
+
+// Validate 校验配置
+//
+// 返回的错误可以用 errors.Is(err, ErrInvalidConfig) 判断，错误信息列出全部问题。
+func (cfg *Config) Validate() error {
+	var problems []string
+	if cfg.BaseURL == "" {
+		problems = append(problems, "base_url: required")
+	} else if problem := baseURLProblem(cfg.BaseURL); problem != "" {
+		problems = append(problems, "base_url: "+problem)
+	}
+	for i, endpoint := range cfg.Endpoints {
+		if problem := baseURLProblem(endpoint); problem != "" {
+			problems = append(problems, fmt.Sprintf("endpoints[%d]: %s", i, problem))
+		}
+	}
+	if cfg.Timeout < 0 {
+		problems = append(problems, "timeout: must not be negative")
+	}
+	if cfg.Mode < ModeAuto || cfg.Mode > ModeEnglish {
+		problems = append(problems, fmt.Sprintf("mode: expected auto, random, chinese, english or 0-3, got %d", cfg.Mode))
+	}
+	if cfg.MaxWatchers < 0 {
+		problems = append(problems, "max_watchers: must not be negative")
+	}
+	if cfg.WatcherQueue < 0 {
+		problems = append(problems, "watcher_queue: must not be negative")
+	}
+	if cfg.Retry.MaxRetries < 0 {
+		problems = append(problems, "retry.max_retries: must not be negative")
+	}
+	if cfg.Retry.BaseDelay < 0 {
+		problems = append(problems, "retry.base_delay: must not be negative")
+	}
+	if cfg.Retry.MaxDelay < 0 {
+		problems = append(problems, "retry.max_delay: must not be negative")
+	}
+	if cfg.Retry.BaseDelay > 0 && cfg.Retry.MaxDelay > 0 && cfg.Retry.BaseDelay > cfg.Retry.MaxDelay {
+		problems = append(problems, fmt.Sprintf("retry.base_delay: %s is larger than retry.max_delay %s", cfg.Retry.BaseDelay, cfg.Retry.MaxDelay))
+	}
+	if _, ok := errorMessages[cfg.Locale]; cfg.Locale != "" && !ok {
+		problems = append(problems, fmt.Sprintf("locale: expected en or zh, got %q", cfg.Locale))
+	}
+	return configError(problems)
+}
+
+// Options 将配置转换为客户端配置项
+func (cfg *Config) Options() []Option {
+	var opts []Option
+	if cfg.MailboxToken != "" {
+		opts = append(opts, WithMailboxToken(cfg.MailboxToken))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if cfg.Mode != ModeAuto {
+		opts = append(opts, WithDefaultMode(cfg.Mode))
+	}
+	if len(cfg.Blacklist) > 0 {
+		opts = append(opts, WithBlacklist(cfg.Blacklist...))
+	}
+	if len(cfg.Endpoints) > 0 {
+		opts = append(opts, WithEndpoints(cfg.Endpoints...))
+	}
+	if cfg.Retry != (RetryPolicy{}) {
+		opts = append(opts, WithRetry(cfg.Retry))
+	}
+	if cfg.ReadOnly {
+		opts = append(opts, WithReadOnly())
+	}
+	if cfg.DryRun {
+		opts = append(opts, WithDryRun())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, WithHeaders(cfg.Headers))
+	}
+	if cfg.UserAgentSuffix != "" {
+		opts = append(opts, WithUserAgentSuffix(cfg.UserAgentSuffix))
+	}
+	if cfg.Locale != "" {
+		opts = append(opts, WithLocale(cfg.Locale))
+	}
+	if cfg.MaxWatchers > 0 {
+		opts = append(opts, WithMaxWatchers(cfg.MaxWatchers, cfg.WatcherQueue))
+	}
+	return opts
+}
+
+// NewClient 校验配置并创建客户端
+//
+// opts 在配置之后应用，可以覆盖配置中的设置。
+func (cfg *Config) NewClient(opts ...Option) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return NewClient(cfg.BaseURL, cfg.APIKey, append(cfg.Options(), opts...)...), nil
+}
+
+// LoadConfig 读取配置文件并创建客户端
+//
+// 支持 JSON 和 YAML（常用子集：键值对、嵌套对象、列表、注释）两种格式，按扩展名
+// 判断，其他扩展名按内容判断。字符串中的 ${NAME} 会被替换为环境变量的值，密钥
+// 不必写在文件中。未知字段、类型错误、取值不合法时返回 ErrInvalidConfig，错误信息
+// 列出全部问题。
+//
+// 参数:
+//   path: 配置文件路径
+//   opts: 可选配置项（在配置文件之后应用）
+//
+// 返回:
+//   *Client: 客户端实例
+//   error: 读取失败或配置不合法
+//
+// 示例:
+//   // mail2.yaml:
+//   //   base_url: https://mail.cwn.cc
+//   //   api_key: ${MAIL2_API_KEY}
+//   //   mode: random
+//   //   blacklist: [eu.org, edu.kg]
+//   //   retry:
+//   //     max_retries: 3
+//   //     base_delay: 500ms
+//   client, err := mail2sdk.LoadConfig("mail2.yaml")
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+func LoadConfig(path string, opts ...Option) (*Client, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config failed: %w", err)
+	}
+
+	cfg, err := parseConfig(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	client, err := cfg.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return client, nil
+}
+
+// ParseConfig 解析 YAML 或 JSON 格式的配置（以 { 开头的按 JSON 解析）
+//
+// 只解析和校验字段类型，不做 Validate 中的取值检查。
+func ParseConfig(data []byte) (*Config, error) {
+	return parseConfig(data, "")
+}
+
+// parseConfig 按扩展名（为空时按内容）选择格式解析配置
+func parseConfig(data []byte, ext string) (*Config, error) {
+	var raw map[string]interface{}
+	var err error
+
+	isJSON := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	switch strings.ToLower(ext) {
+	case ".json":
+		isJSON = true
+	case ".yaml", ".yml":
+		isJSON = false
+	}
+
+	if isJSON {
+		err = json.Unmarshal(data, &raw)
+		if err != nil {
+			err = fmt.Errorf("%w: parse json failed: %v", ErrInvalidConfig, err)
+		}
+	} else {
+		raw, err = parseYAML(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConfig(raw)
+}
+
+// decodeConfig 将解析出的通用结构转换为 Config
+func decodeConfig(raw map[string]interface{}) (*Config, error) {
+	cfg := &Config{}
+	var problems []string
+	report := func(key string, err error) {
+		if err != nil {
+			problems = append(problems, key+": "+err.Error())
+		}
+	}
+
+	for _, key := range sortedConfigKeys(raw) {
+		value := raw[key]
+		var err error
+		switch key {
+		case "base_url":
+			cfg.BaseURL, err = configString(value)
+		case "api_key":
+			cfg.APIKey, err = configString(value)
+		case "mailbox_token":
+			cfg.MailboxToken, err = configString(value)
+		case "timeout":
+			cfg.Timeout, err = configDuration(value)
+		case "mode":
+			cfg.Mode, err = configMode(value)
+		case "blacklist":
+			cfg.Blacklist, err = configStrings(value)
+		case "endpoints":
+			cfg.Endpoints, err = configStrings(value)
+		case "max_watchers":
+			cfg.MaxWatchers, err = configInt(value)
+		case "watcher_queue":
+			cfg.WatcherQueue, err = configInt(value)
+		case "read_only":
+			cfg.ReadOnly, err = configBool(value)
+		case "dry_run":
+			cfg.DryRun, err = configBool(value)
+		case "user_agent_suffix":
+			cfg.UserAgentSuffix, err = configString(value)
+		case "locale":
+			var locale string
+			locale, err = configString(value)
+			cfg.Locale = Locale(locale)
+		case "headers":
+			headers, ok := value.(map[string]interface{})
+			if !ok && value != nil {
+				err = fmt.Errorf("expected an object of header names and values")
+				break
+			}
+			cfg.Headers = make(map[string]string, len(headers))
+			for _, name := range sortedConfigKeys(headers) {
+				var fieldErr error
+				cfg.Headers[name], fieldErr = configString(headers[name])
+				report("headers."+name, fieldErr)
+			}
+		case "retry":
+			retry, ok := value.(map[string]interface{})
+			if !ok && value != nil {
+				err = fmt.Errorf("expected an object with %s", strings.Join(retryConfigFields, ", "))
+				break
+			}
+			for _, name := range sortedConfigKeys(retry) {
+				var fieldErr error
+				switch name {
+				case "max_retries":
+					cfg.Retry.MaxRetries, fieldErr = configInt(retry[name])
+				case "base_delay":
+					cfg.Retry.BaseDelay, fieldErr = configDuration(retry[name])
+				case "max_delay":
+					cfg.Retry.MaxDelay, fieldErr = configDuration(retry[name])
+				default:
+					fieldErr = unknownConfigField(name, retryConfigFields)
+				}
+				report("retry."+name, fieldErr)
+			}
+		default:
+			err = unknownConfigField(key, configFields)
+		}
+		report(key, err)
+	}
+
+	if err := configError(problems); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// configError 将问题列表合并为一个错误（没有问题时返回 nil）
+func configError(problems []string) error {
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, problems[0])
+	default:
+		return fmt.Errorf("%w:\n  %s", ErrInvalidConfig, strings.Join(problems, "\n  "))
+	}
+}
+
+// sortedConfigKeys 按字母顺序返回对象的字段名，保证错误信息的顺序稳定
+func sortedConfigKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// envRefPattern 匹配 ${NAME} 形式的环境变量引用
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// configString 读取字符串字段（展开 ${NAME} 环境变量引用）
+func configString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return envRefPattern.ReplaceAllStringFunc(v, func(ref string) string {
+			return os.Getenv(ref[2 : len(ref)-1])
+		}), nil
+	default:
+		return "", fmt.Errorf("expected a string, got %s", jsonTypeOf(value))
+	}
+}
+
+// configStrings 读取字符串列表字段（也接受逗号分隔的字符串）
+func configStrings(value interface{}) ([]string, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		items = v
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			items = append(items, s)
+		}
+	default:
+		return nil, fmt.Errorf("expected a list of strings, got %s", jsonTypeOf(value))
+	}
+
+	var result []string
+	for i, item := range items {
+		s, err := configString(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// configInt 读取非负整数字段
+func configInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		if v >= 0 && v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("expected a non-negative integer, got %s", describeConfigValue(value))
+}
+
+// configBool 读取布尔字段（也接受 YAML 常用的 yes/no、on/off）
+func configBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "yes", "on":
+			return true, nil
+		case "no", "off":
+			return false, nil
+		}
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("expected true or false, got %s", describeConfigValue(value))
+}
+
+// configDuration 读取时长字段（如 "10s"、"500ms"，纯数字按秒计算）
+func configDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case float64:
+		if v >= 0 {
+			return time.Duration(v * float64(time.Second)), nil
+		}
+	case string:
+		if d, err := parseConfigDuration(v); err == nil {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("expected a duration like \"10s\" or \"500ms\", got %s", describeConfigValue(value))
+}
+
+// parseConfigDuration 解析非负时长（纯数字按秒计算）
+func parseConfigDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 {
+			return 0, errors.New("negative duration")
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d < 0 {
+		return 0, errors.New("negative duration")
+	}
+	return d, err
+}
+
+// configMode 读取生成模式字段（模式名称或 0-3）
+func configMode(value interface{}) (int, error) {
+	if s, ok := value.(string); ok {
+		if mode, ok := modeNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+			return mode, nil
+		}
+	}
+	if n, err := configInt(value); err == nil && n <= ModeEnglish {
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected auto, random, chinese, english or 0-3, got %s", describeConfigValue(value))
+}
+
+// describeConfigValue 在错误信息中描述配置值
+func describeConfigValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return jsonTypeOf(value)
+	}
+}
+
+// unknownConfigField 返回未知字段的错误，字段名接近某个已知字段时给出提示
+func unknownConfigField(name string, known []string) error {
+	best, bestDist := "", 3
+	for _, k := range known {
+		if d := editDistance(name, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if best != "" {
+		return fmt.Errorf("unknown field (did you mean %q?)", best)
+	}
+	return fmt.Errorf("unknown field (supported: %s)", strings.Join(known, ", "))
+}
+
+// editDistance 计算两个字符串的编辑距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// yamlLine 去掉注释和空行后的一行 YAML
+type yamlLine struct {
+	num    int    // 行号（从 1 开始）
+	indent int    // 缩进空格数
+	text   string // 去掉缩进和注释后的内容
+}
+
+// yamlParser 只支持配置文件需要的 YAML 子集：
+// 键值对、按缩进嵌套的对象、"- " 列表、[a, b] 行内列表、引号字符串和 # 注释
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML 解析 YAML 子集，标量一律返回字符串（null 和 ~ 返回 nil）
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(string(data), "\n") {
+		text := strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("%w: line %d: tabs are not allowed for indentation", ErrInvalidConfig, i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+
+	if len(p.lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	root, err := p.parseMap(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return root, nil
+}
+
+// errorf 返回带当前行号的错误
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidConfig, p.lines[p.pos].num, fmt.Sprintf(format, args...))
+}
+
+// parseMap 解析缩进为 indent 的连续键值对
+func (p *yamlParser) parseMap(indent int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if line.text == "-" || strings.HasPrefix(line.text, "- ") {
+			return nil, p.errorf("unexpected list item, expected \"key: value\"")
+		}
+
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorf("expected \"key: value\", got %q", line.text)
+		}
+		if _, dup := result[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseYAMLScalar(rest)
+			if err != nil {
+				p.pos--
+				return nil, p.errorf("%s: %v", key, err)
+			}
+			result[key] = value
+			continue
+		}
+
+		// 值在后续行：更深缩进的对象或列表，也允许与键同级缩进的列表
+		result[key] = nil
+		if p.pos >= len(p.lines) {
+			continue
+		}
+		next := p.lines[p.pos]
+		isList := next.text == "-" || strings.HasPrefix(next.text, "- ")
+		var err error
+		switch {
+		case isList && next.indent >= indent:
+			result[key], err = p.parseList(next.indent)
+		case next.indent > indent:
+			result[key], err = p.parseMap(next.indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// parseList 解析缩进为 indent 的连续列表项
+func (p *yamlParser) parseList(indent int) ([]interface{}, error) {
+	var result []interface{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || (line.text != "-" && !strings.HasPrefix(line.text, "- ")) {
+			break
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if item == "" {
+			return nil, p.errorf("empty or nested list items are not supported")
+		}
+		value, err := parseYAMLScalar(item)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		result = append(result, value)
+		p.pos++
+	}
+	return result, nil
+}
+
+// splitYAMLKey 拆分 "key: value"（键可以加引号）
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, text = text[1:end+1], text[end+2:]
+		if !strings.HasPrefix(text, ":") {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(text[1:]), true
+	}
+
+	if strings.HasSuffix(text, ":") {
+		return strings.TrimSpace(text[:len(text)-1]), "", true
+	}
+	i := strings.Index(text, ": ")
+	if i <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+2:]), true
+}
+
+// parseYAMLScalar 解析标量或 [a, b] 行内列表
+func parseYAMLScalar(text string) (interface{}, error) {
+	switch {
+	case text == "null" || text == "~":
+		return nil, nil
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unterminated inline list %q", text)
+		}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		items := []interface{}{}
+		if inner == "" {
+			return items, nil
+		}
+		for _, part := range strings.Split(inner, ",") {
+			item, err := parseYAMLScalar(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case strings.HasPrefix(text, "{"):
+		return nil, fmt.Errorf("inline objects are not supported, use indented keys")
+	case strings.HasPrefix(text, `"`):
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string %s", text)
+		}
+		return s, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("invalid quoted string %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	default:
+		return text, nil
+	}
+}
+
+// stripYAMLComment 去掉行尾的 # 注释（忽略引号内的 #）
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else if ch == '\\' && quote == '"' {
+				i++
+			}
+		case (ch == '"' || ch == '\'') && (i == 0 || strings.IndexByte(" \t:[,-", line[i-1]) >= 0):
+			quote = ch
+		case ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
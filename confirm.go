@@ -0,0 +1,230 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsafeLink 表示链接未通过 LinkPolicy 安全检查
+var ErrUnsafeLink = errors.New("unsafe link")
+
+// 确认链接默认允许的最大重定向次数
+const defaultMaxRedirects = 5
+
+// 常见的二级公共后缀前缀（如 co.uk、com.cn），用于粗略判断主域名
+var secondLevelLabels = map[string]bool{
+	"co": true, "com": true, "net": true, "org": true, "gov": true, "edu": true, "ac": true,
+}
+
+// LinkPolicy 确认链接的安全检查策略
+//
+// 临时邮箱域名是公开的，任何人都可以向其发送钓鱼邮件。自动点击确认链接前，
+// 应检查链接是否指向预期发件方的域名。
+type LinkPolicy struct {
+	// ExpectedDomain 预期的发件方域名（如 "github.com"）。设置后邮件发件人和链接
+	// 都必须属于该域名；为空时使用邮件发件人的域名
+	ExpectedDomain string
+
+	Allowlist    []string // 额外允许的链接域名（包括其子域名），如 CDN 或短链域名
+	MaxRedirects int      // 最大重定向次数（<= 0 表示 5）
+	AllowHTTP    bool     // 是否允许非 HTTPS 链接
+}
+
+// maxRedirects 返回生效的最大重定向次数
+func (p *LinkPolicy) maxRedirects() int {
+	if p == nil || p.MaxRedirects <= 0 {
+		return defaultMaxRedirects
+	}
+	return p.MaxRedirects
+}
+
+// allowedDomains 返回链接允许的域名列表
+func (p *LinkPolicy) allowedDomains(detail *MailDetail) ([]string, error) {
+	sender := senderAddress(detail.From)
+	senderDomain := ""
+	if i := strings.LastIndex(sender, "@"); i >= 0 {
+		senderDomain = sender[i+1:]
+	}
+
+	expected := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(p.ExpectedDomain), "@"))
+	if expected == "" {
+		if senderDomain == "" {
+			return nil, fmt.Errorf("%w: sender domain is unknown", ErrUnsafeLink)
+		}
+		expected = baseDomain(senderDomain)
+	} else if !domainMatches(senderDomain, []string{expected}) {
+		return nil, fmt.Errorf("%w: sender %q is not from %s", ErrUnsafeLink, sender, expected)
+	}
+
+	domains := []string{expected}
+	for _, d := range p.Allowlist {
+		domains = append(domains, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")))
+	}
+	return domains, nil
+}
+
+// checkURL 检查单个地址是否允许访问
+func (p *LinkPolicy) checkURL(u *url.URL, domains []string) error {
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && p.AllowHTTP:
+	default:
+		return fmt.Errorf("%w: scheme %q is not allowed: %s", ErrUnsafeLink, u.Scheme, u.Redacted())
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: link contains credentials", ErrUnsafeLink)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if !domainMatches(host, domains) {
+		return fmt.Errorf("%w: host %s is not in %s", ErrUnsafeLink, host, strings.Join(domains, ", "))
+	}
+	return nil
+}
+
+// baseDomain 粗略计算主域名（mail.example.com -> example.com，a.example.co.uk -> example.co.uk）
+func baseDomain(host string) string {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 && secondLevelLabels[labels[len(labels)-2]] {
+		n = 3
+	}
+	if len(labels) <= n {
+		return host
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// CheckLink 按策略检查邮件中的链接（不发送请求）
+//
+// 重定向链只能在访问时检查，见 ConfirmLink。
+//
+// 返回:
+//   error: 未通过检查时返回 ErrUnsafeLink（可用 errors.Is 判断）
+func CheckLink(link string, detail *MailDetail, policy LinkPolicy) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsafeLink, err)
+	}
+	domains, err := policy.allowedDomains(detail)
+	if err != nil {
+		return err
+	}
+	return policy.checkURL(u, domains)
+}
+
+// ConfirmOptions 确认链接配置
+type ConfirmOptions struct {
+	Link   string      // 要访问的链接（为空时使用邮件中的第一个非退订链接）
+	Policy *LinkPolicy // 安全检查策略（nil 表示不检查）
+}
+
+// ConfirmResult 确认链接的访问结果
+type ConfirmResult struct {
+	URL        string   // 访问的链接
+	FinalURL   string   // 跟随重定向后的最终地址
+	Redirects  []string // 经过的重定向地址
+	StatusCode int      // 最终响应的状态码
+}
+
+// ConfirmLink 访问邮件中的确认链接（如注册激活、邮箱验证）
+//
+// 设置 Policy 时，访问前检查链接的协议和域名，并拒绝离开允许域名的重定向，
+// 避免自动化流程点击投递到公共临时域名的钓鱼链接。请求不携带 API 密钥。
+// 设置了 WithLinkCleaning 时，访问前先清理链接，FinalURL 同样去除追踪参数。
+//
+// 参数:
+//   ctx: 上下文
+//   detail: 邮件详情
+//   opts: 可选配置（传 nil 表示使用第一个链接且不做安全检查）
+//
+// 返回:
+//   *ConfirmResult: 访问结果
+//   error: 错误信息（未通过安全检查时为 ErrUnsafeLink，最终状态码 >= 400 时同样返回错误）
+//
+// 示例:
+//   result, err := client.ConfirmLink(ctx, detail, &mail2sdk.ConfirmOptions{
+//       Policy: &mail2sdk.LinkPolicy{ExpectedDomain: "github.com"},
+//   })
+//   if errors.Is(err, mail2sdk.ErrUnsafeLink) {
+//       log.Printf("拒绝访问可疑链接: %v", err)
+//   }
+func (c *Client) ConfirmLink(ctx context.Context, detail *MailDetail, opts *ConfirmOptions) (*ConfirmResult, error) {
+	if detail == nil {
+		return nil, fmt.Errorf("mail detail is required")
+	}
+	var o ConfirmOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	link := o.Link
+	if link == "" {
+		links := mailLinks(detail)
+		if len(links) == 0 {
+			return nil, fmt.Errorf("no link found in mail %s", detail.ID)
+		}
+		link = links[0]
+	}
+	link = c.cleanLink(ctx, link)
+
+	target, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parse link failed: %w", err)
+	}
+
+	var domains []string
+	if o.Policy != nil {
+		if domains, err = o.Policy.allowedDomains(detail); err != nil {
+			return nil, err
+		}
+		if err := o.Policy.checkURL(target, domains); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ConfirmResult{URL: link}
+	maxRedirects := o.Policy.maxRedirects()
+	// 复用客户端的 HTTP 配置（代理、埋点等），只替换重定向检查
+	httpClient := *c.settings().httpClient
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("%w: too many redirects", ErrUnsafeLink)
+		}
+		result.Redirects = append(result.Redirects, req.URL.String())
+		if o.Policy != nil {
+			return o.Policy.checkURL(req.URL, domains)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header["User-Agent"] = c.settings().userAgentHeader
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrUnsafeLink) {
+			return result, c.redactError(err)
+		}
+		return result, c.redactError(fmt.Errorf("confirm link failed: %w", err))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	result.FinalURL = resp.Request.URL.String()
+	if c.cleanLinks {
+		result.FinalURL = CleanLink(result.FinalURL)
+	}
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 400 {
+		return result, fmt.Errorf("confirm link failed: status %d", resp.StatusCode)
+	}
+	return result, nil
+}
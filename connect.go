@@ -0,0 +1,143 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ValidateBaseURL 检查 API 基础地址是否合法
+//
+// 首尾空白会被忽略（与 NewClient 一致），地址中间有空白、缺少协议、协议不是
+// http/https、缺少主机名或带有查询字符串时返回 ErrInvalidConfig，错误信息说明如何修正。
+//
+// 示例:
+//   err := mail2sdk.ValidateBaseURL("mail.cwn.cc")
+//   // invalid config: base URL "mail.cwn.cc" has no scheme, did you mean "https://mail.cwn.cc"?
+func ValidateBaseURL(raw string) error {
+	if problem := baseURLProblem(raw); problem != "" {
+		return fmt.Errorf("%w: base URL %s", ErrInvalidConfig, problem)
+	}
+	return nil
+}
+
+// baseURLProblem 返回地址的问题描述（合法时返回空字符串）
+func baseURLProblem(raw string) string {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "is empty"
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' || r == '\n' }) >= 0 {
+		return fmt.Sprintf("%q contains whitespace", raw)
+	}
+	if !strings.Contains(s, "://") {
+		return fmt.Sprintf("%q has no scheme, did you mean \"https://%s\"?", raw, strings.TrimLeft(s, "/"))
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Sprintf("%q is not a valid URL: %v", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Sprintf("%q has unsupported scheme %q, expected http or https", raw, u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Sprintf("%q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Sprintf("%q must not contain a query string or fragment", raw)
+	}
+	return ""
+}
+
+// NewClientChecked 校验基础地址后创建客户端
+//
+// 与 NewClient 相同，但 baseURL 或 WithEndpoints 设置的地址不合法时返回错误（见
+// ValidateBaseURL），而不是等到第一次请求才以难以理解的网络错误失败。
+//
+// 返回:
+//   *Client: 客户端实例
+//   error: 地址不合法时返回 ErrInvalidConfig
+func NewClientChecked(baseURL, apiKey string, opts ...Option) (*Client, error) {
+	if err := ValidateBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+	c := NewClient(baseURL, apiKey, opts...)
+	for _, mirror := range c.mirrors {
+		if problem := baseURLProblem(mirror); problem != "" {
+			c.Close()
+			return nil, fmt.Errorf("%w: endpoint %s", ErrInvalidConfig, problem)
+		}
+	}
+	return c, nil
+}
+
+// Connect 校验基础地址、创建客户端并检查服务端是否可用
+//
+// 在 NewClientChecked 的基础上发送一次获取域名列表的请求，确认地址可达、指向的是
+// API 而不是网站页面、API 密钥有效（只配置了邮箱令牌时只检查可达性）。失败时关闭
+// 客户端并返回说明可能原因的错误，原始错误可以用 errors.Is/errors.As 和 ErrorCodeOf 判断。
+// 适合在程序启动时调用，配置错误立即暴露，而不是在第一次 CreateMailbox 时才出现。
+//
+// 参数:
+//   ctx: 上下文（控制检查请求的超时）
+//   baseURL: API 基础地址
+//   apiKey: API 密钥
+//   opts: 可选配置项
+//
+// 返回:
+//   *Client: 检查通过的客户端
+//   error: 地址不合法（ErrInvalidConfig）或检查失败
+//
+// 示例:
+//   ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//   defer cancel()
+//   client, err := mail2sdk.Connect(ctx, os.Getenv("MAIL2_BASE_URL"), os.Getenv("MAIL2_API_KEY"))
+//   if err != nil {
+//       log.Fatal(err) // 如: probe https://mail.cwn.cc failed, the api key was rejected: ...
+//   }
+func Connect(ctx context.Context, baseURL, apiKey string, opts ...Option) (*Client, error) {
+	c, err := NewClientChecked(baseURL, apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.probe(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// probe 检查服务端是否可用，失败时附加可能的原因
+func (c *Client) probe(ctx context.Context) error {
+	var err error
+	if c.settings().apiKey == "" {
+		_, err = c.ServerInfo(ctx)
+	} else {
+		_, err = c.GetDomains(ctx)
+	}
+	if err == nil {
+		return nil
+	}
+
+	hint := ""
+	switch ErrorCodeOf(err) {
+	case CodeUnauthorized:
+		hint = "the api key was rejected"
+	case CodeNotFound:
+		hint = "the api was not found, check the base URL path and WithBasePath"
+	case CodeDecode:
+		hint = "the response is not json, the base URL may point to a website instead of the api"
+	case CodeNetwork, CodeTimeout:
+		hint = "the server is unreachable, check the host, port and proxy settings"
+	case CodeRedirectedToLogin:
+		hint = "requests are redirected to a login page, check the base URL and credentials"
+	case CodeChallenge:
+		hint = "requests are blocked by a WAF/CDN challenge"
+	}
+	if hint == "" {
+		return fmt.Errorf("probe %s failed: %w", c.baseURL, err)
+	}
+	return fmt.Errorf("probe %s failed, %s: %w", c.baseURL, hint, err)
+}
@@ -0,0 +1,116 @@
+package mail2sdk
+
+import "context"
+
+// ctxKey SDK 内部使用的上下文键类型
+type ctxKey int
+
+const (
+	ctxKeyMailboxRead    ctxKey = iota // 标记请求为读取邮件的请求
+	ctxKeyReceipt                      // 调用方传入的 *CreateReceipt
+	ctxKeyCapture                      // 记录原始响应的 *responseCapture
+	ctxKeyBudget                       // 调用方传入的 *Budget
+	ctxKeyTrigger                      // 触发发送邮件的时间
+	ctxKeyDomainKey                    // 确定性选择域名使用的 key
+	ctxKeyMailboxToken                 // 会话持有的 *sessionToken
+	ctxKeyRetries                      // 批量操作统计重试次数的 *int64
+	ctxKeyRequestTimeout               // 单次请求的超时时间
+	ctxKeyRequestHeaders               // 单次请求附加的 *requestHeaders
+	ctxKeyAttributes                   // 调用方附加的属性（map[string]string）
+	ctxKeyTargetDomain                 // 创建邮箱请求的目标域名
+)
+
+// withMailboxRead 标记请求为读取邮件的请求（可使用邮箱级令牌认证）
+func withMailboxRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyMailboxRead, true)
+}
+
+// mailboxTokenFor 返回请求应携带的邮箱级令牌（非读取请求返回空字符串）
+func (c *Client) mailboxTokenFor(ctx context.Context) string {
+	if read, _ := ctx.Value(ctxKeyMailboxRead).(bool); !read {
+		return ""
+	}
+	if st, ok := ctx.Value(ctxKeyMailboxToken).(*sessionToken); ok {
+		if token, _ := st.get(); token != "" {
+			return token
+		}
+	}
+	return c.settings().mailboxToken
+}
+
+// WithCreateReceipt 返回一个上下文，使用该上下文创建邮箱时会把服务端的完整响应写入 receipt
+//
+// 适用于 CreateMailbox、CreateMailboxWithDomains、OpenSession 等所有创建邮箱的方法。
+//
+// 示例:
+//   var receipt mail2sdk.CreateReceipt
+//   mailbox, err := client.CreateMailbox(mail2sdk.WithCreateReceipt(ctx, &receipt), mail2sdk.ModeRandom, "", nil)
+//   fmt.Println(receipt.QuotaRemaining, receipt.RateLimit.Remaining)
+func WithCreateReceipt(ctx context.Context, receipt *CreateReceipt) context.Context {
+	return context.WithValue(ctx, ctxKeyReceipt, receipt)
+}
+
+// receiptFrom 返回上下文中的 *CreateReceipt（没有时返回 nil）
+func receiptFrom(ctx context.Context) *CreateReceipt {
+	receipt, _ := ctx.Value(ctxKeyReceipt).(*CreateReceipt)
+	return receipt
+}
+
+// withResponseCapture 记录该请求的原始响应
+func withResponseCapture(ctx context.Context, capture *responseCapture) context.Context {
+	return context.WithValue(ctx, ctxKeyCapture, capture)
+}
+
+// responseCaptureFrom 返回上下文中的 *responseCapture（没有时返回 nil）
+func responseCaptureFrom(ctx context.Context) *responseCapture {
+	capture, _ := ctx.Value(ctxKeyCapture).(*responseCapture)
+	return capture
+}
+
+// WithDomainKey 返回一个上下文，使用该上下文创建邮箱时按 key 确定性地选择域名
+//
+// 同一个 key（如测试用例 ID）在同一组候选域名中总是得到同一个域名，重跑测试时
+// 可以稳定复现与特定域名相关的投递问题。未指定候选域名时会先获取服务端的域名列表。
+//
+// 示例:
+//   ctx := mail2sdk.WithDomainKey(ctx, t.Name())
+//   mailbox, err := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+func WithDomainKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, ctxKeyDomainKey, key)
+}
+
+// domainKeyFrom 返回上下文中的域名选择 key
+func domainKeyFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(ctxKeyDomainKey).(string)
+	return key, ok
+}
+
+// WithAttributes 返回附加了调用方属性的上下文（如测试用例 ID、租户）
+//
+// 使用该上下文发起的调用产生的日志（LogEntry.Attrs）、客户端事件（ClientEvent.Attrs）
+// 和审计记录（AuditRecord.Attrs）都会带上这些属性，无需借助全局状态就能把 SDK 的
+// 每一行日志归属到发起调用的测试或租户。多次调用会合并属性，同名属性以后设置的为准。
+//
+// 示例:
+//   ctx = mail2sdk.WithAttributes(ctx, map[string]string{"test_case": t.Name(), "tenant": "team-a"})
+//   client.WaitForCode(ctx, address, nil) // 该调用的每条日志都带有 test_case 和 tenant
+func WithAttributes(ctx context.Context, attrs map[string]string) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	parent := Attributes(ctx)
+	merged := make(map[string]string, len(parent)+len(attrs))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, ctxKeyAttributes, merged)
+}
+
+// Attributes 返回上下文中由 WithAttributes 附加的属性（没有时返回 nil，返回值不可修改）
+func Attributes(ctx context.Context) map[string]string {
+	attrs, _ := ctx.Value(ctxKeyAttributes).(map[string]string)
+	return attrs
+}
@@ -0,0 +1,117 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoDefaultClient 表示调用 Default* 包级函数前没有通过 SetDefault 设置默认客户端
+var ErrNoDefaultClient = errors.New("default client not set, call mail2sdk.SetDefault first")
+
+// defaultClient 由 SetDefault 设置的默认客户端
+var defaultClient atomic.Pointer[Client]
+
+// SetDefault 设置 Default* 包级函数使用的默认客户端，返回之前的默认客户端（没有时为 nil）
+//
+// 可以在任意时刻并发地替换，正在进行的调用继续使用替换前的客户端。传 nil 清除默认客户端。
+// 被替换的客户端不会被关闭，不再使用时由调用方关闭。
+//
+// 从 CreateMailbox(baseURL, apiKey, ...) 等包级函数迁移时，可以先在程序启动时设置
+// 默认客户端，把调用逐步改为 DefaultCreateMailbox 等函数，最后再改为直接使用 Client。
+//
+// 示例:
+//   mail2sdk.SetDefault(mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithTimeout(10*time.Second)))
+//
+//   mailbox, err := mail2sdk.DefaultCreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+//
+//   // 替换配置后关闭旧客户端
+//   if old := mail2sdk.SetDefault(newClient); old != nil {
+//       old.Close()
+//   }
+func SetDefault(client *Client) *Client {
+	return defaultClient.Swap(client)
+}
+
+// Default 返回默认客户端（未设置时返回 ErrNoDefaultClient）
+func Default() (*Client, error) {
+	client := defaultClient.Load()
+	if client == nil {
+		return nil, ErrNoDefaultClient
+	}
+	return client, nil
+}
+
+// DefaultGetDomains 使用默认客户端获取可用域名列表（见 Client.GetDomains）
+func DefaultGetDomains(ctx context.Context) ([]string, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDomains(ctx)
+}
+
+// DefaultCreateMailbox 使用默认客户端创建临时邮箱（见 Client.CreateMailbox）
+func DefaultCreateMailbox(ctx context.Context, mode int, domain string, blacklist []string) (*Mailbox, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateMailbox(ctx, mode, domain, blacklist)
+}
+
+// DefaultCreateMailboxWithDomains 使用默认客户端从指定域名组创建邮箱（见 Client.CreateMailboxWithDomains）
+func DefaultCreateMailboxWithDomains(ctx context.Context, mode int, domains []string, blacklist []string) (*Mailbox, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateMailboxWithDomains(ctx, mode, domains, blacklist)
+}
+
+// DefaultGetMails 使用默认客户端获取邮件列表（见 Client.GetMails）
+func DefaultGetMails(ctx context.Context, address string, opts ...CallOption) ([]Mail, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMails(ctx, address, opts...)
+}
+
+// DefaultGetMailDetail 使用默认客户端获取邮件详情（见 Client.GetMailDetail）
+func DefaultGetMailDetail(ctx context.Context, address, mailID string) (*MailDetail, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMailDetail(ctx, address, mailID)
+}
+
+// DefaultExtractCode 使用默认客户端提取验证码（见 Client.ExtractCode）
+func DefaultExtractCode(ctx context.Context, address string, maxMails int) (*CodeResult, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.ExtractCode(ctx, address, maxMails)
+}
+
+// DefaultWaitForCode 使用默认客户端等待验证码（见 Client.WaitForCode）
+func DefaultWaitForCode(ctx context.Context, address string, opts *WaitCodeOptions) (*CodeResult, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return client.WaitForCode(ctx, address, opts)
+}
+
+// DefaultDeleteMailbox 使用默认客户端删除邮箱（见 Client.DeleteMailbox）
+//
+// 注意: 此操作不可逆！
+func DefaultDeleteMailbox(ctx context.Context, address string) error {
+	client, err := Default()
+	if err != nil {
+		return err
+	}
+	return client.DeleteMailbox(ctx, address)
+}
@@ -0,0 +1,241 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DialFunc 建立网络连接的函数（签名同 net.Dialer.DialContext）
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithDialer 使用自定义函数建立到 API 服务端的连接
+//
+// 适用于通过 SOCKS 代理、SSH 隧道或固定出口网卡连接服务端。只作用于 SDK 构建的
+// 传输层和 *http.Transport 类型的自定义传输层（会复制后替换其 DialContext，不影响
+// 原对象）；其他类型的自定义传输层（如 ChaosTransport、WithDryRun）自行负责连接，
+// 此时该配置不生效。同时设置 WithResolver 时以 WithDialer 为准。
+//
+// 示例:
+//   dialer, _ := proxy.SOCKS5("tcp", "127.0.0.1:1080", nil, proxy.Direct)
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithDialer(
+//       dialer.(proxy.ContextDialer).DialContext,
+//   ))
+func WithDialer(dial DialFunc) Option {
+	return func(c *Client) {
+		c.dial = dial
+	}
+}
+
+// WithResolver 使用自定义 DNS 解析器解析 API 服务端的域名
+//
+// 临时邮箱服务的域名在不同网络中可能被解析到不同地址（或被运营商 DNS 污染），
+// 可以用 DNSServerResolver 固定 DNS 服务器，或用 DoHResolver 改用 DNS-over-HTTPS。
+// 生效范围同 WithDialer。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithResolver(mail2sdk.DNSServerResolver("1.1.1.1:53", "8.8.8.8:53")),
+//   )
+func WithResolver(resolver *net.Resolver) Option {
+	return func(c *Client) {
+		c.resolver = resolver
+	}
+}
+
+// dialTransport 返回使用自定义连接函数或解析器的传输层（都未设置时原样返回）
+func (c *Client) dialTransport(rt http.RoundTripper) http.RoundTripper {
+	dial := c.dial
+	if dial == nil && c.resolver != nil {
+		dial = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Resolver:  c.resolver,
+		}).DialContext
+	}
+	if dial == nil {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	// 复制后替换，不影响共享传输层和调用方的对象
+	t = t.Clone()
+	t.DialContext = dial
+	return t
+}
+
+// DNSServerResolver 返回只向指定 DNS 服务器查询的解析器
+//
+// servers 为 "host:port" 形式的地址（省略端口时使用 53），按顺序轮流使用，一个服务器
+// 连接失败时尝试下一个。
+//
+// 示例:
+//   resolver := mail2sdk.DNSServerResolver("223.5.5.5", "119.29.29.29:53")
+func DNSServerResolver(servers ...string) *net.Resolver {
+	addrs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		addrs = append(addrs, server)
+	}
+
+	var next uint32
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			if len(addrs) == 0 {
+				return nil, errors.New("no dns servers configured")
+			}
+			var d net.Dialer
+			start := atomic.AddUint32(&next, 1)
+			var lastErr error
+			for i := range addrs {
+				conn, err := d.DialContext(ctx, network, addrs[(int(start)+i)%len(addrs)])
+				if err == nil {
+					return conn, nil
+				}
+				lastErr = err
+			}
+			return nil, lastErr
+		},
+	}
+}
+
+// DoHResolver 返回通过 DNS-over-HTTPS（RFC 8484）查询的解析器
+//
+// endpoint 为 DoH 服务地址，如 "https://1.1.1.1/dns-query"。使用域名形式的地址时，
+// 该域名本身由系统 DNS 解析，建议使用 IP 形式的地址。hc 为 nil 时使用 10 秒超时的
+// 默认 HTTP 客户端。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithResolver(mail2sdk.DoHResolver("https://1.1.1.1/dns-query", nil)),
+//   )
+func DoHResolver(endpoint string, hc *http.Client) *net.Resolver {
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return &dohConn{ctx: ctx, endpoint: endpoint, hc: hc}, nil
+		},
+	}
+}
+
+// dohConn 把解析器的一次 DNS 查询转换为 DoH 请求
+//
+// 不是 net.PacketConn，解析器会按 TCP 的格式读写（消息前带 2 字节长度）：Write 收集
+// 查询，第一次 Read 时发送请求，之后从响应中读取。
+type dohConn struct {
+	ctx      context.Context
+	endpoint string
+	hc       *http.Client
+	deadline time.Time
+
+	query    bytes.Buffer
+	response *bytes.Reader
+}
+
+// Write 收集查询消息
+func (c *dohConn) Write(b []byte) (int, error) {
+	return c.query.Write(b)
+}
+
+// Read 读取响应（第一次调用时发送查询）
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.response == nil {
+		if err := c.exchange(); err != nil {
+			return 0, err
+		}
+	}
+	return c.response.Read(b)
+}
+
+// exchange 发送 DoH 请求，把响应转换为带长度前缀的格式
+func (c *dohConn) exchange() error {
+	msg := c.query.Bytes()
+	if len(msg) < 2 {
+		return errors.New("doh: empty dns query")
+	}
+	msg = msg[2:]
+
+	ctx := c.ctx
+	if !c.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, c.deadline)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(msg))
+	if err != nil {
+		return fmt.Errorf("doh: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("doh: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("doh: unexpected status %d from %s", resp.StatusCode, c.endpoint)
+	}
+	// DNS 消息最长 65535 字节
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 65535+1))
+	if err != nil {
+		return fmt.Errorf("doh: read response failed: %w", err)
+	}
+	if len(body) > 65535 {
+		return errors.New("doh: response too large")
+	}
+
+	framed := make([]byte, 2+len(body))
+	binary.BigEndian.PutUint16(framed, uint16(len(body)))
+	copy(framed[2:], body)
+	c.response = bytes.NewReader(framed)
+	return nil
+}
+
+// Close 实现 net.Conn 接口
+func (c *dohConn) Close() error { return nil }
+
+// LocalAddr 实现 net.Conn 接口
+func (c *dohConn) LocalAddr() net.Addr { return dohAddr{} }
+
+// RemoteAddr 实现 net.Conn 接口
+func (c *dohConn) RemoteAddr() net.Addr { return dohAddr{} }
+
+// SetDeadline 设置查询的截止时间
+func (c *dohConn) SetDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+// SetReadDeadline 设置查询的截止时间
+func (c *dohConn) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+// SetWriteDeadline 写入只是缓存查询，忽略截止时间
+func (c *dohConn) SetWriteDeadline(time.Time) error { return nil }
+
+// dohAddr dohConn 的地址
+type dohAddr struct{}
+
+func (dohAddr) Network() string { return "doh" }
+func (dohAddr) String() string  { return "doh" }
@@ -0,0 +1,226 @@
+package mail2sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DiffOp 差异行类型
+type DiffOp int
+
+// 差异行类型常量
+const (
+	DiffEqual  DiffOp = iota // 两封邮件相同
+	DiffDelete               // 仅出现在第一封邮件（a）中
+	DiffInsert               // 仅出现在第二封邮件（b）中
+)
+
+// DiffLine 差异中的一行
+type DiffLine struct {
+	Op   DiffOp // 差异类型
+	Text string // 行内容（已规范化）
+}
+
+// MailDiff 两封邮件的结构化差异
+type MailDiff struct {
+	SubjectChanged bool       // 规范化后的主题是否不同
+	Text           []DiffLine // 纯文本正文逐行差异
+	HTMLStructure  []DiffLine // HTML 标签结构差异（每行一个标签）
+	HTMLText       []DiffLine // HTML 可见文本逐行差异
+}
+
+// Changed 判断两封邮件的模板是否存在差异
+func (d *MailDiff) Changed() bool {
+	return d.SubjectChanged || hasChanges(d.Text) || hasChanges(d.HTMLStructure) || hasChanges(d.HTMLText)
+}
+
+// String 以类似 unified diff 的格式输出差异（只包含有变化的部分）
+func (d *MailDiff) String() string {
+	var b strings.Builder
+	if d.SubjectChanged {
+		b.WriteString("subject changed\n")
+	}
+	writeDiffSection(&b, "text", d.Text)
+	writeDiffSection(&b, "html structure", d.HTMLStructure)
+	writeDiffSection(&b, "html text", d.HTMLText)
+	return b.String()
+}
+
+// hasChanges 判断差异中是否包含新增或删除的行
+func hasChanges(lines []DiffLine) bool {
+	for _, l := range lines {
+		if l.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDiffSection 输出一段差异（无变化时不输出）
+func writeDiffSection(b *strings.Builder, name string, lines []DiffLine) {
+	if !hasChanges(lines) {
+		return
+	}
+	b.WriteString("--- " + name + "\n")
+	for _, l := range lines {
+		switch l.Op {
+		case DiffDelete:
+			b.WriteString("- " + l.Text + "\n")
+		case DiffInsert:
+			b.WriteString("+ " + l.Text + "\n")
+		}
+	}
+}
+
+var (
+	urlPattern         = regexp.MustCompile(`(?i)https?://[^\s"'<>]+`)
+	emailPattern       = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+	tokenPattern       = regexp.MustCompile(`[A-Za-z0-9_-]{24,}`)
+	digitsPattern      = regexp.MustCompile(`[0-9]+`)
+	htmlTagNamePattern = regexp.MustCompile(`<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9]*)([^>]*)>`)
+	classPattern       = regexp.MustCompile(`(?i)\bclass\s*=\s*["']([^"']*)["']`)
+	htmlBlockPattern   = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/tr|/li|/h[1-6]|/table)\b[^>]*>`)
+)
+
+// normalizeLine 规范化一行文本：合并空白，链接、邮箱地址、长令牌和数字替换为占位符
+//
+// 验证码、日期、确认链接等每封邮件都不同，替换后才能比较模板本身。
+func normalizeLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = urlPattern.ReplaceAllString(s, "{url}")
+	s = emailPattern.ReplaceAllString(s, "{email}")
+	s = tokenPattern.ReplaceAllString(s, "{token}")
+	return digitsPattern.ReplaceAllString(s, "{n}")
+}
+
+// normalizedLines 将文本拆分为规范化后的非空行
+func normalizedLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = normalizeLine(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// htmlStructure 提取 HTML 的标签结构（每行一个标签，保留 class 属性）
+func htmlStructure(s string) []string {
+	s = htmlIgnorePattern.ReplaceAllString(s, "")
+	var tags []string
+	for _, m := range htmlTagNamePattern.FindAllStringSubmatch(s, -1) {
+		tag := "<" + m[1] + strings.ToLower(m[2])
+		if cls := classPattern.FindStringSubmatch(m[3]); cls != nil {
+			tag += ` class="` + normalizeLine(cls[1]) + `"`
+		}
+		tags = append(tags, tag+">")
+	}
+	return tags
+}
+
+// htmlTextLines 提取 HTML 的可见文本（按块级标签换行）
+func htmlTextLines(s string) []string {
+	s = htmlIgnorePattern.ReplaceAllString(s, " ")
+	s = htmlBlockPattern.ReplaceAllString(s, "\n")
+	return normalizedLines(htmlToText(s))
+}
+
+// DiffMails 比较两封邮件的模板差异
+//
+// 分别比较主题、纯文本正文、HTML 标签结构和 HTML 可见文本。比较前会合并空白，
+// 并将链接、邮箱地址、长令牌和数字替换为占位符（如 {n}），因此验证码、日期
+// 不同的同模板邮件不会被视为有差异。
+// 可用于检测服务商是否修改了验证邮件模板，以便及时更新提取规则。
+//
+// 参数:
+//   a: 基准邮件（如保存的旧模板样本）
+//   b: 新邮件
+//
+// 返回:
+//   *MailDiff: 差异结果
+//
+// 示例:
+//   diff := mail2sdk.DiffMails(baseline, latest)
+//   if diff.Changed() {
+//       log.Printf("验证邮件模板已变化:\n%s", diff)
+//   }
+func DiffMails(a, b *MailDetail) *MailDiff {
+	if a == nil {
+		a = &MailDetail{}
+	}
+	if b == nil {
+		b = &MailDetail{}
+	}
+
+	return &MailDiff{
+		SubjectChanged: normalizeLine(a.Subject) != normalizeLine(b.Subject),
+		Text:           diffLines(normalizedLines(a.TextBody), normalizedLines(b.TextBody)),
+		HTMLStructure:  diffLines(htmlStructure(a.HTMLBody), htmlStructure(b.HTMLBody)),
+		HTMLText:       diffLines(htmlTextLines(a.HTMLBody), htmlTextLines(b.HTMLBody)),
+	}
+}
+
+// diffLines 基于最长公共子序列计算逐行差异
+func diffLines(a, b []string) []DiffLine {
+	// 去掉公共前缀和后缀，缩小 LCS 表的规模
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		result = append(result, DiffLine{Op: DiffEqual, Text: line})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(ma), len(mb)
+
+	// lcs[i][j] 表示 ma[i:] 与 mb[j:] 的最长公共子序列长度
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case ma[i] == mb[j]:
+			result = append(result, DiffLine{Op: DiffEqual, Text: ma[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: DiffDelete, Text: ma[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: DiffInsert, Text: mb[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, DiffLine{Op: DiffDelete, Text: ma[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, DiffLine{Op: DiffInsert, Text: mb[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package mail2sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// domainHeaders 只对部分请求附加的请求头（见 WithDomainHeaders）
+type domainHeaders struct {
+	baseURL string      // 以 API 地址匹配时为规范化后的地址，否则为空
+	domain  string      // 以邮箱域名匹配时为小写域名，否则为空
+	headers http.Header // 附加的请求头
+}
+
+// WithDomainHeaders 为发往指定邮箱域名或 API 地址的请求附加请求头
+//
+// 部分邮箱域名由需要额外请求头的网关提供服务时使用。target 有两种形式：
+//   - 邮箱域名（如 "corp-mail.example"）：操作该域名下邮箱的请求（读取邮件、删除邮箱、
+//     在该域名下创建邮箱等）附加这些请求头，子域名同样匹配，不区分大小写
+//   - API 地址（如 "https://gw.example.com"，带协议）：发往该地址的请求附加这些请求头，
+//     可以配合 WithEndpoints 为不同的网关设置不同的请求头
+//
+// 请求头在 WithHeader 之后、WithRequestHeader 之前写入，同名时覆盖客户端级别的值；
+// 多条规则都匹配时按设置顺序写入，后设置的生效。受保护的请求头（X-API-Key 等）
+// 同样会被忽略。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithDomainHeaders("corp-mail.example", map[string]string{"X-Gateway-Token": gwToken}),
+//       mail2sdk.WithDomainHeaders("https://eu.mail.cwn.cc", map[string]string{"X-Region": "eu"}),
+//   )
+func WithDomainHeaders(target string, headers map[string]string) Option {
+	return func(c *Client) {
+		target = strings.TrimSpace(target)
+		if target == "" || len(headers) == 0 {
+			return
+		}
+		rule := domainHeaders{headers: make(http.Header, len(headers))}
+		for key, value := range headers {
+			rule.headers.Set(key, value)
+		}
+		if strings.Contains(target, "://") {
+			rule.baseURL = normalizeBaseURL(target)
+		} else {
+			rule.domain = strings.ToLower(strings.TrimPrefix(target, "@"))
+		}
+		c.domainHeaders = append(c.domainHeaders, rule)
+	}
+}
+
+// withTargetDomain 标记请求操作的邮箱域名（创建邮箱时请求路径中没有邮箱地址）
+func withTargetDomain(ctx context.Context, domain string) context.Context {
+	return context.WithValue(ctx, ctxKeyTargetDomain, domain)
+}
+
+// targetDomain 返回请求操作的邮箱域名（无法确定时返回空字符串）
+func targetDomain(req *http.Request) string {
+	if domain, _ := req.Context().Value(ctxKeyTargetDomain).(string); domain != "" {
+		return strings.ToLower(domain)
+	}
+
+	const prefix = "/api/mailbox/"
+	path := req.URL.Path
+	i := strings.Index(path, prefix)
+	if i < 0 {
+		return ""
+	}
+	address := path[i+len(prefix):]
+	if end := strings.IndexByte(address, '/'); end >= 0 {
+		address = address[:end]
+	}
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(address[at+1:])
+}
+
+// matchDomainHeaders 返回请求匹配的规则中的请求头（按设置顺序）
+func (c *Client) matchDomainHeaders(req *http.Request) []http.Header {
+	if len(c.domainHeaders) == 0 {
+		return nil
+	}
+
+	url := req.URL.String()
+	domain := targetDomain(req)
+	var matched []http.Header
+	for _, rule := range c.domainHeaders {
+		switch {
+		case rule.baseURL != "":
+			if url == rule.baseURL || strings.HasPrefix(url, rule.baseURL+"/") {
+				matched = append(matched, rule.headers)
+			}
+		case domain != "":
+			if domainMatches(domain, []string{rule.domain}) {
+				matched = append(matched, rule.headers)
+			}
+		}
+	}
+	return matched
+}
@@ -0,0 +1,71 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// DomainUsage 服务端统计的单个域名使用情况
+type DomainUsage struct {
+	Domain        string `json:"domain"`         // 域名
+	Creations     int    `json:"creations"`      // 服务端记录的邮箱创建次数
+	MailsReceived int    `json:"mails_received"` // 收到的邮件数量
+	Bounces       int    `json:"bounces"`        // 退信/投递失败指标
+
+	// LocalCreations 客户端域名选择器记录的使用次数（见 Client.DomainSelector），
+	// 便于与服务端数据对比
+	LocalCreations int `json:"-"`
+}
+
+// DomainUsage 获取服务端的按域名统计数据
+//
+// 返回结果按 Creations 降序排列，并附带本地选择器的计数（LocalCreations）。
+// 服务端不支持统计接口时，只返回可用域名列表和本地计数（服务端字段均为 0）。
+//
+// 示例:
+//   usage, _ := client.DomainUsage(ctx)
+//   for _, u := range usage {
+//       fmt.Printf("%s 服务端 %d 次 / 本地 %d 次，收信 %d，退信 %d\n",
+//           u.Domain, u.Creations, u.LocalCreations, u.MailsReceived, u.Bounces)
+//   }
+func (c *Client) DomainUsage(ctx context.Context) ([]DomainUsage, error) {
+	var result struct {
+		Records []DomainUsage `json:"records"`
+	}
+
+	err := c.requireFeature(ctx, FeatureDomainStats)
+	if err == nil {
+		err = c.markUnsupportedOn404(FeatureDomainStats, c.do(ctx, "GET", "/api/domains/stats", nil, &result))
+	}
+	if errors.Is(err, ErrNotSupportedByServer) {
+		// 旧版本服务端：退化为只包含本地计数的域名列表
+		result.Records, err = c.localDomainUsage(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	local := c.selector.Stats()
+	for i := range result.Records {
+		result.Records[i].LocalCreations = local[result.Records[i].Domain]
+	}
+
+	sort.SliceStable(result.Records, func(i, j int) bool {
+		return result.Records[i].Creations > result.Records[j].Creations
+	})
+	return result.Records, nil
+}
+
+// localDomainUsage 根据可用域名列表构建只包含本地计数的统计
+func (c *Client) localDomainUsage(ctx context.Context) ([]DomainUsage, error) {
+	domains, err := c.GetDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]DomainUsage, len(domains))
+	for i, domain := range domains {
+		records[i].Domain = domain
+	}
+	return records, nil
+}
@@ -0,0 +1,102 @@
+package mail2sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DryRunDomain 试运行模式下返回的合成域名
+const DryRunDomain = "dryrun.mail2.invalid"
+
+// WithDryRun 启用试运行模式
+//
+// 试运行模式下客户端不会发起任何网络请求：创建邮箱返回合成的邮箱信息，
+// 读取操作返回空列表，删除操作直接成功。适用于在没有 Mail2 凭据的环境中
+// 对流水线做冒烟测试。
+//
+// 示例:
+//   client := mail2sdk.NewClient("https://mail.example.com", "", mail2sdk.WithDryRun())
+//   mailbox, _ := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+//   // mailbox.Address 形如 "dry3f9a1c@dryrun.mail2.invalid"
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.transport = dryRunTransport{client: c}
+	}
+}
+
+// dryRunTransport 返回合成响应的传输层
+type dryRunTransport struct {
+	client *Client // 用于按客户端的响应结构（见 WithEnvelope）生成响应
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	path := req.URL.Path
+	if idx := strings.Index(path, "/api/"); idx >= 0 {
+		path = path[idx:]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var data interface{}
+	switch {
+	case req.Method == http.MethodGet && path == "/api/domains":
+		data = map[string]interface{}{
+			"records": []map[string]interface{}{{"name": DryRunDomain, "enabled": true}},
+		}
+	case req.Method == http.MethodPost && path == "/api/mailbox":
+		var body struct {
+			Domain   string `json:"domain"`
+			Username string `json:"username"`
+		}
+		if req.Body != nil {
+			raw, _ := io.ReadAll(req.Body)
+			json.Unmarshal(raw, &body)
+		}
+		data = dryRunMailbox(body.Domain, body.Username)
+	case req.Method == http.MethodGet && len(segments) == 4 && segments[3] == "mails":
+		data = map[string]interface{}{"count": 0, "mails": []Mail{}}
+	case req.Method == http.MethodGet && len(segments) == 5 && segments[3] == "mails":
+		return syntheticResponse(req, http.StatusNotFound, `{"code":404,"msg":"mail not found (dry run)"}`), nil
+	case req.Method == http.MethodGet && len(segments) == 4 && segments[3] == "code":
+		data = CodeResult{AllCodes: []string{}}
+	default:
+		data = nil
+	}
+
+	env := t.client.envelopeOrDefault()
+	envelope := map[string]interface{}{env.MessageKey: "dry run", env.DataKey: data}
+	if env.CodeKey != "-" {
+		envelope[env.CodeKey] = env.SuccessCodes[0]
+	}
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return syntheticResponse(req, http.StatusOK, string(raw)), nil
+}
+
+// dryRunMailbox 生成一个合成邮箱（username 为空时随机生成）
+func dryRunMailbox(domain, username string) Mailbox {
+	if domain == "" {
+		domain = DryRunDomain
+	}
+	if username == "" {
+		username = fmt.Sprintf("dry%06x", randIntn(1<<24))
+	}
+	now := time.Now()
+	return Mailbox{
+		Address:   username + "@" + domain,
+		Username:  username,
+		Domain:    domain,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
@@ -0,0 +1,129 @@
+package mail2sdk
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrDecryptFailed 表示密文无法解密（密钥错误或数据被篡改）
+var ErrDecryptFailed = errors.New("decrypt failed")
+
+// 密文格式版本（便于以后更换算法）
+const cipherVersion byte = 1
+
+// Cipher 基于 AES-GCM 的对称加密
+//
+// 密文格式为 版本(1 字节) + nonce(12 字节) + 密文和认证标签。每次加密使用随机 nonce，
+// 同一明文两次加密的结果不同。Cipher 可以在多个 goroutine 中共享。
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher 创建加密器
+//
+// key 的长度必须为 16、24 或 32 字节（分别对应 AES-128、AES-192、AES-256）。
+//
+// 示例:
+//   key, _ := hex.DecodeString(os.Getenv("MAIL2_STATE_KEY")) // 32 字节
+//   c, err := mail2sdk.NewCipher(key)
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher failed: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher failed: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal 加密 plaintext
+//
+// aad 为附加认证数据（不加密但参与认证），解密时必须提供相同的 aad，
+// 可以用来把密文绑定到它的存储位置，防止密文被挪用到其他键下。
+func (c *Cipher) Seal(plaintext, aad []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	out := make([]byte, 1+nonceSize, 1+nonceSize+len(plaintext)+c.aead.Overhead())
+	out[0] = cipherVersion
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, fmt.Errorf("generate nonce failed: %w", err)
+	}
+	return c.aead.Seal(out, out[1:], plaintext, aad), nil
+}
+
+// Open 解密 Seal 生成的密文，失败时返回 ErrDecryptFailed
+func (c *Cipher) Open(data, aad []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(data) < 1+nonceSize+c.aead.Overhead() || data[0] != cipherVersion {
+		return nil, fmt.Errorf("%w: malformed ciphertext", ErrDecryptFailed)
+	}
+	plaintext, err := c.aead.Open(nil, data[1:1+nonceSize], data[1+nonceSize:], aad)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
+
+// EncryptedStore 加密值的 Store 包装
+//
+// 写入时用 AES-GCM 加密值，读取时解密，键本身不加密（需要支持按前缀列出）。
+// 每个值都以键作为附加认证数据，被挪到其他键下的密文无法解密。适合在共享的
+// CI 机器上持久化邮箱地址、邮件内容等敏感状态。
+//
+// 示例:
+//   files, _ := mail2sdk.NewFileStore("/var/lib/myapp/mail2")
+//   store, err := mail2sdk.NewEncryptedStore(files, key)
+//   consumer := webhook.NewConsumer(store, handler, webhook.ConsumerOptions{})
+type EncryptedStore struct {
+	inner  Store
+	cipher *Cipher
+}
+
+// NewEncryptedStore 创建加密存储（key 的长度必须为 16、24 或 32 字节）
+func NewEncryptedStore(inner Store, key []byte) (*EncryptedStore, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	c, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedStore{inner: inner, cipher: c}, nil
+}
+
+// Get 实现 Store 接口
+func (s *EncryptedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.inner.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cipher.Open(data, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put 实现 Store 接口
+func (s *EncryptedStore) Put(ctx context.Context, key string, value []byte) error {
+	data, err := s.cipher.Seal(value, []byte(key))
+	if err != nil {
+		return err
+	}
+	return s.inner.Put(ctx, key, data)
+}
+
+// Delete 实现 Store 接口
+func (s *EncryptedStore) Delete(ctx context.Context, key string) error {
+	return s.inner.Delete(ctx, key)
+}
+
+// List 实现 Store 接口
+func (s *EncryptedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return s.inner.List(ctx, prefix)
+}
@@ -0,0 +1,235 @@
+package mail2sdk
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// endpointSmoothing 延迟和错误率滑动平均中新样本的权重
+	endpointSmoothing = 0.3
+	// endpointErrorPenalty 错误率对评分的放大倍数（错误率 10% 相当于延迟翻倍）
+	endpointErrorPenalty = 10
+	// endpointMaxFailures 连续失败达到该次数后暂停使用该地址
+	endpointMaxFailures = 3
+	// endpointDownTime 暂停使用的时长，到期后重新参与选择
+	endpointDownTime = 30 * time.Second
+	// endpointExploreEvery 每隔多少次请求发往最久未使用的地址，使非最优地址的统计保持更新
+	endpointExploreEvery = 20
+)
+
+// EndpointStats 一个 API 地址的统计信息
+type EndpointStats struct {
+	URL       string        // API 基础地址（规范化后，包含路径前缀）
+	Latency   time.Duration // 响应耗时的滑动平均（没有样本时为 0）
+	ErrorRate float64       // 错误率的滑动平均（0-1）
+	Requests  int64         // 发往该地址的请求数
+	Failures  int64         // 失败的请求数（网络错误和 5xx）
+	DownUntil time.Time     // 暂停使用的截止时间（零值表示可用）
+}
+
+// endpoint 一个 API 地址及其统计
+type endpoint struct {
+	base string
+
+	mu        sync.Mutex
+	latency   float64 // 纳秒
+	errorRate float64
+	requests  int64
+	failures  int64
+	streak    int // 连续失败次数
+	downUntil time.Time
+	lastUsed  time.Time
+}
+
+// score 评分（越小越好，没有样本的地址优先，以便尽快测得延迟）
+func (e *endpoint) score() float64 {
+	return e.latency * (1 + endpointErrorPenalty*e.errorRate)
+}
+
+// endpointSet 客户端配置的全部 API 地址
+type endpointSet struct {
+	list  []*endpoint
+	calls atomic.Int64
+}
+
+// WithEndpoints 设置额外的 API 地址（镜像或其他地区的部署）
+//
+// NewClient 的 baseURL 为主地址，与这里的地址一起参与选择。客户端记录每个地址的
+// 响应耗时和错误率（网络错误和 5xx 计为错误），每次请求发往评分最好的地址；
+// 连续失败 3 次的地址暂停使用 30 秒，失败的请求重试时会自然地换到其他地址。
+// 每 20 次请求中有一次发往最久未使用的地址，使恢复的地址能重新被选中。
+//
+// 所有地址应指向数据互通的同一服务（邮箱和邮件 ID 在各地址间通用）。WithBasePath
+// 同样作用于这里的地址；BaseURL 返回主地址，邮件详情缓存也以主地址为准。
+//
+// 示例:
+//   client := mail2sdk.NewClient("https://mail.cwn.cc", apiKey,
+//       mail2sdk.WithEndpoints("https://hk.mail.cwn.cc", "https://us.mail.cwn.cc"),
+//   )
+//   for _, e := range client.Endpoints() {
+//       log.Printf("%s 延迟 %s 错误率 %.1f%%", e.URL, e.Latency, e.ErrorRate*100)
+//   }
+func WithEndpoints(baseURLs ...string) Option {
+	return func(c *Client) {
+		c.mirrors = append(c.mirrors, baseURLs...)
+	}
+}
+
+// newEndpointSet 由主地址和额外地址构建地址集合（没有额外地址时返回 nil）
+func newEndpointSet(primary string, mirrors []string, basePath string) *endpointSet {
+	seen := map[string]bool{primary: true}
+	set := &endpointSet{list: []*endpoint{{base: primary}}}
+	for _, raw := range mirrors {
+		base := normalizeBaseURL(raw)
+		if base == "" {
+			continue
+		}
+		base += normalizeBasePath(basePath)
+		if !seen[base] {
+			seen[base] = true
+			set.list = append(set.list, &endpoint{base: base})
+		}
+	}
+	if len(set.list) == 1 {
+		return nil
+	}
+	return set
+}
+
+// pick 选择本次请求使用的地址
+func (s *endpointSet) pick() *endpoint {
+	now := time.Now()
+	explore := s.calls.Add(1)%endpointExploreEvery == 0
+
+	var best, fallback *endpoint
+	var bestScore float64
+	for _, e := range s.list {
+		e.mu.Lock()
+		down := now.Before(e.downUntil)
+		score, lastUsed := e.score(), e.lastUsed
+		e.mu.Unlock()
+
+		// 全部暂停时使用最早恢复的地址
+		if down {
+			if fallback == nil || e.downUntil.Before(fallback.downUntil) {
+				fallback = e
+			}
+			continue
+		}
+		if explore {
+			score = float64(lastUsed.UnixNano())
+		}
+		if best == nil || score < bestScore {
+			best, bestScore = e, score
+		}
+	}
+	if best == nil {
+		best = fallback
+	}
+
+	best.mu.Lock()
+	best.lastUsed = now
+	best.mu.Unlock()
+	return best
+}
+
+// find 返回请求地址所属的地址（不属于任何地址时返回 nil）
+func (s *endpointSet) find(rawURL string) *endpoint {
+	var found *endpoint
+	for _, e := range s.list {
+		// 取最长的匹配，避免一个地址是另一个地址的前缀时误判
+		if strings.HasPrefix(rawURL, e.base) && (found == nil || len(e.base) > len(found.base)) {
+			found = e
+		}
+	}
+	return found
+}
+
+// observe 记录一次请求的结果，返回该地址是否因此被暂停使用
+func (e *endpoint) observe(elapsed time.Duration, failed bool) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	sample := 0.0
+	if failed {
+		sample = 1
+		e.failures++
+		e.streak++
+		// 还没有成功样本时以失败耗时作为延迟，避免失败的地址因延迟为 0 一直被优先选择
+		if e.latency == 0 {
+			e.latency = float64(max(elapsed, time.Millisecond))
+		}
+	} else {
+		e.streak = 0
+		if e.latency == 0 {
+			e.latency = float64(elapsed)
+		} else {
+			e.latency += endpointSmoothing * (float64(elapsed) - e.latency)
+		}
+	}
+	e.errorRate += endpointSmoothing * (sample - e.errorRate)
+
+	if e.streak >= endpointMaxFailures {
+		e.streak = 0
+		e.downUntil = time.Now().Add(endpointDownTime)
+		return true
+	}
+	return false
+}
+
+// stats 返回统计快照
+func (e *endpoint) stats() EndpointStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := EndpointStats{
+		URL:       e.base,
+		Latency:   time.Duration(e.latency),
+		ErrorRate: e.errorRate,
+		Requests:  e.requests,
+		Failures:  e.failures,
+	}
+	if time.Now().Before(e.downUntil) {
+		stats.DownUntil = e.downUntil
+	}
+	return stats
+}
+
+// Endpoints 返回各个 API 地址的统计信息（主地址在前，未设置 WithEndpoints 时只有主地址）
+func (c *Client) Endpoints() []EndpointStats {
+	if c.endpoints == nil {
+		return []EndpointStats{{URL: c.baseURL}}
+	}
+	stats := make([]EndpointStats, len(c.endpoints.list))
+	for i, e := range c.endpoints.list {
+		stats[i] = e.stats()
+	}
+	return stats
+}
+
+// requestBase 返回本次请求使用的基础地址
+func (c *Client) requestBase() string {
+	if c.endpoints == nil {
+		return c.baseURL
+	}
+	return c.endpoints.pick().base
+}
+
+// observeEndpoint 记录请求结果（网络错误和 5xx 计为失败，ctx 结束导致的错误不计入）
+func (c *Client) observeEndpoint(req *http.Request, elapsed time.Duration, resp *http.Response, err error) {
+	if c.endpoints == nil || req.Context().Err() != nil {
+		return
+	}
+	e := c.endpoints.find(req.URL.String())
+	if e == nil {
+		return
+	}
+	failed := err != nil || resp.StatusCode >= 500
+	if e.observe(elapsed, failed) {
+		c.emit(req.Context(), ClientEvent{Type: EventEndpointDown, Endpoint: e.base, Err: err})
+	}
+}
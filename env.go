@@ -0,0 +1,104 @@
+package mail2sdk
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 环境变量名称
+const (
+	EnvBaseURL      = "MAIL2_BASE_URL"      // API 基础地址（必填）
+	EnvEndpoints    = "MAIL2_ENDPOINTS"     // 额外的 API 地址（逗号分隔，见 WithEndpoints）
+	EnvAPIKey       = "MAIL2_API_KEY"       // API 密钥
+	EnvMailboxToken = "MAIL2_MAILBOX_TOKEN" // 邮箱级访问令牌（见 WithMailboxToken）
+	EnvTimeout      = "MAIL2_TIMEOUT"       // 请求超时（如 "10s"，纯数字按秒计算）
+	EnvMode         = "MAIL2_MODE"          // 默认生成模式（auto/random/chinese/english，见 WithDefaultMode）
+	EnvBlacklist    = "MAIL2_BLACKLIST"     // 域名黑名单（逗号分隔）
+	EnvMaxRetries   = "MAIL2_MAX_RETRIES"   // 最大重试次数
+	EnvReadOnly     = "MAIL2_READ_ONLY"     // 只读模式（true/false，见 WithReadOnly）
+	EnvDryRun       = "MAIL2_DRY_RUN"       // 试运行模式（true/false，见 WithDryRun）
+)
+
+// NewClientFromEnv 根据环境变量创建客户端
+//
+// 读取 MAIL2_BASE_URL、MAIL2_API_KEY、MAIL2_TIMEOUT、MAIL2_BLACKLIST 等环境变量
+// （见 Env 开头的常量），CI 任务和容器无需修改代码即可配置 SDK。opts 在环境变量之后
+// 应用，可以覆盖环境变量中的配置。
+//
+// 返回:
+//   *Client: 客户端实例
+//   error: 缺少 MAIL2_BASE_URL 或环境变量的值不合法（ErrInvalidConfig）
+//
+// 示例:
+//   // MAIL2_BASE_URL=https://mail.cwn.cc MAIL2_API_KEY=xxx MAIL2_TIMEOUT=10s
+//   client, err := mail2sdk.NewClientFromEnv()
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	return newClientFromLookup(os.LookupEnv, opts...)
+}
+
+// newClientFromLookup 根据 lookup 返回的环境变量创建客户端
+func newClientFromLookup(lookup func(string) (string, bool), opts ...Option) (*Client, error) {
+	cfg, err := configFromEnv(lookup)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.NewClient(opts...)
+}
+
+// configFromEnv 根据环境变量构建配置
+func configFromEnv(lookup func(string) (string, bool)) (*Config, error) {
+	get := func(name string) string {
+		value, _ := lookup(name)
+		return strings.TrimSpace(value)
+	}
+
+	cfg := &Config{
+		BaseURL:      get(EnvBaseURL),
+		APIKey:       get(EnvAPIKey),
+		MailboxToken: get(EnvMailboxToken),
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidConfig, EnvBaseURL)
+	}
+
+	var err error
+	invalid := func(name string) error {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, name, err)
+	}
+	if value := get(EnvTimeout); value != "" {
+		if cfg.Timeout, err = configDuration(value); err != nil {
+			return nil, invalid(EnvTimeout)
+		}
+	}
+	if value := get(EnvMode); value != "" {
+		if cfg.Mode, err = configMode(value); err != nil {
+			return nil, invalid(EnvMode)
+		}
+	}
+	if value := get(EnvBlacklist); value != "" {
+		cfg.Blacklist, _ = configStrings(value)
+	}
+	if value := get(EnvEndpoints); value != "" {
+		cfg.Endpoints, _ = configStrings(value)
+	}
+	if value := get(EnvMaxRetries); value != "" {
+		if cfg.Retry.MaxRetries, err = configInt(value); err != nil {
+			return nil, invalid(EnvMaxRetries)
+		}
+	}
+	if value := get(EnvReadOnly); value != "" {
+		if cfg.ReadOnly, err = configBool(value); err != nil {
+			return nil, invalid(EnvReadOnly)
+		}
+	}
+	if value := get(EnvDryRun); value != "" {
+		if cfg.DryRun, err = configBool(value); err != nil {
+			return nil, invalid(EnvDryRun)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,128 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Envelope API 响应外层结构的字段名
+//
+// 标准 Mail2 服务端的响应形如 {"code": 0, "msg": "ok", "data": {...}}。部分自行部署的
+// 分支会改用其他字段名或成功码，可以通过 WithEnvelope 适配，无需修改 SDK。
+type Envelope struct {
+	CodeKey      string // 业务码字段（为空表示 "code"，为 "-" 表示响应中没有业务码）
+	MessageKey   string // 错误信息字段（为空表示 "msg"）
+	DataKey      string // 数据字段（为空表示 "data"）
+	SuccessCodes []int  // 表示成功的业务码（为空表示 0 和 200）
+}
+
+// defaultEnvelope 标准 Mail2 服务端的响应结构
+var defaultEnvelope = Envelope{CodeKey: "code", MessageKey: "msg", DataKey: "data", SuccessCodes: []int{0, 200}}
+
+// WithEnvelope 设置 API 响应外层结构的字段名和成功码
+//
+// 未设置的字段使用标准值。业务码可以是数字或数字字符串；业务码不在 SuccessCodes
+// 中时返回 APIError。试运行模式和响应 Schema 校验同样使用这里的字段名。
+//
+// 示例:
+//   // 服务端响应形如 {"status": 1, "message": "ok", "result": {...}}
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithEnvelope(mail2sdk.Envelope{
+//       CodeKey:      "status",
+//       MessageKey:   "message",
+//       DataKey:      "result",
+//       SuccessCodes: []int{1},
+//   }))
+func WithEnvelope(env Envelope) Option {
+	return func(c *Client) {
+		if env.CodeKey == "" {
+			env.CodeKey = defaultEnvelope.CodeKey
+		}
+		if env.MessageKey == "" {
+			env.MessageKey = defaultEnvelope.MessageKey
+		}
+		if env.DataKey == "" {
+			env.DataKey = defaultEnvelope.DataKey
+		}
+		if len(env.SuccessCodes) == 0 {
+			env.SuccessCodes = defaultEnvelope.SuccessCodes
+		}
+		c.envelope = &env
+	}
+}
+
+// envelopeOrDefault 返回客户端使用的响应结构
+func (c *Client) envelopeOrDefault() *Envelope {
+	if c.envelope != nil {
+		return c.envelope
+	}
+	return &defaultEnvelope
+}
+
+// decode 解析响应并将数据字段写入 result（strict 为 true 时不允许未知字段）
+//
+// 标准结构使用 decodeEnvelope 的快速路径，这里只处理自定义字段名。
+func (e *Envelope) decode(respBody []byte, result interface{}, strict bool) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(respBody, &fields); err != nil {
+		return fmt.Errorf("parse response failed: %w", err)
+	}
+
+	if e.CodeKey != "-" {
+		if raw, ok := fields[e.CodeKey]; ok {
+			code, err := parseEnvelopeCode(raw)
+			if err != nil {
+				return fmt.Errorf("parse response failed: %s: %w", e.CodeKey, err)
+			}
+			if !e.success(code) {
+				return &APIError{Code: code, Message: envelopeMessage(fields[e.MessageKey])}
+			}
+		}
+	}
+
+	data := fields[e.DataKey]
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if strict {
+		return decodeStrict(data, result)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("parse data failed: %w", err)
+	}
+	return nil
+}
+
+// success 判断业务码是否表示成功
+func (e *Envelope) success(code int) bool {
+	for _, ok := range e.SuccessCodes {
+		if code == ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEnvelopeCode 解析数字或数字字符串形式的业务码
+func parseEnvelopeCode(raw json.RawMessage) (int, error) {
+	var code int
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return code, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("expected a number, got %s", raw)
+	}
+	return strconv.Atoi(strings.TrimSpace(s))
+}
+
+// envelopeMessage 取出错误信息（不是字符串时返回原始 JSON）
+func envelopeMessage(raw json.RawMessage) string {
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		return msg
+	}
+	return string(raw)
+}
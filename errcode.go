@@ -0,0 +1,265 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrorCode SDK 错误的稳定分类码
+//
+// 错误信息（err.Error()）统一为英文，内容可能随版本调整；ErrorCode 不会改变，
+// 适合用于监控指标、告警规则和面向用户的提示映射。
+type ErrorCode string
+
+// 错误分类码
+const (
+	CodeUnknown            ErrorCode = "unknown"             // 无法归类的错误
+	CodeCanceled           ErrorCode = "canceled"            // ctx 被取消
+	CodeTimeout            ErrorCode = "timeout"             // 超时（ctx 到期或网络超时）
+	CodeNetwork            ErrorCode = "network"             // 网络错误（连接失败、DNS 解析失败等）
+	CodeUnauthorized       ErrorCode = "unauthorized"        // API 密钥无效或没有权限（HTTP 401/403）
+	CodeNotFound           ErrorCode = "not_found"           // 邮箱或邮件不存在（HTTP 404）
+	CodeRateLimited        ErrorCode = "rate_limited"        // 请求过于频繁（HTTP 429）
+	CodeServer             ErrorCode = "server_error"        // 服务端错误（HTTP 5xx）
+	CodeAPI                ErrorCode = "api_error"           // 其他服务端返回的错误（APIError）
+	CodeDecode             ErrorCode = "decode_error"        // 响应不是 API 的 JSON 格式（DecodeError）
+	CodeUnexpectedResponse ErrorCode = "unexpected_response" // 严格解析模式下响应结构不一致（ErrUnexpectedResponse）
+	CodeChallenge          ErrorCode = "waf_challenge"       // 被 WAF/CDN 人机验证拦截（ErrChallengeDetected）
+	CodeRedirectedToLogin  ErrorCode = "redirected_to_login" // 被重定向到登录页（ErrRedirectedToLogin）
+	CodeNotSupported       ErrorCode = "not_supported"       // 服务端不支持该功能（ErrNotSupportedByServer）
+	CodeNoDomains          ErrorCode = "no_domains"          // 没有可用域名（ErrNoDomainsAvailable）
+	CodeReadOnly           ErrorCode = "read_only"           // 只读客户端拒绝修改（ErrReadOnly）
+	CodeBudgetExceeded     ErrorCode = "budget_exceeded"     // 耗时预算已用完（ErrBudgetExceeded）
+	CodeClientClosed       ErrorCode = "client_closed"       // 客户端已关闭（ErrClientClosed）
+	CodeNoDefaultClient    ErrorCode = "no_default_client"   // 没有设置默认客户端（ErrNoDefaultClient）
+	CodeTooManyWatchers    ErrorCode = "too_many_watchers"   // 同时监听的数量超出上限（ErrTooManyWatchers）
+	CodeUnsafeLink         ErrorCode = "unsafe_link"         // 链接未通过安全检查（ErrUnsafeLink）
+	CodeLowConfidence      ErrorCode = "low_confidence"      // 验证码置信度过低（ErrLowConfidence）
+	CodeAttachmentRejected ErrorCode = "attachment_rejected" // 附件被拒绝下载（ErrAttachmentRejected）
+	CodePoolClosed         ErrorCode = "pool_closed"         // 邮箱池已关闭（ErrPoolClosed）
+	CodeListLimitExceeded  ErrorCode = "list_limit_exceeded" // 邮箱列表超出上限（ErrListLimitExceeded）
+	CodeInvalidConfig      ErrorCode = "invalid_config"      // 配置无效（ErrInvalidConfig）
+	CodeInvalidTemplate    ErrorCode = "invalid_template"    // 邮箱用户名模板无效（ErrInvalidNameTemplate）
+	CodeDecryptFailed      ErrorCode = "decrypt_failed"      // 解密失败（ErrDecryptFailed）
+	CodeKeyNotFound        ErrorCode = "key_not_found"       // 状态存储中不存在该键（ErrNotFound）
+	CodeUnknownPattern     ErrorCode = "unknown_pattern"     // 引用的正则没有注册（ErrUnknownPattern）
+)
+
+// sentinelCodes 哨兵错误对应的分类码（按顺序匹配，外层错误优先）
+var sentinelCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrClientClosed, CodeClientClosed},
+	{ErrNoDefaultClient, CodeNoDefaultClient},
+	{ErrBudgetExceeded, CodeBudgetExceeded},
+	{ErrReadOnly, CodeReadOnly},
+	{ErrChallengeDetected, CodeChallenge},
+	{ErrRedirectedToLogin, CodeRedirectedToLogin},
+	{ErrUnexpectedResponse, CodeUnexpectedResponse},
+	{ErrNotSupportedByServer, CodeNotSupported},
+	{ErrNoDomainsAvailable, CodeNoDomains},
+	{ErrTooManyWatchers, CodeTooManyWatchers},
+	{ErrUnsafeLink, CodeUnsafeLink},
+	{ErrLowConfidence, CodeLowConfidence},
+	{ErrAttachmentRejected, CodeAttachmentRejected},
+	{ErrPoolClosed, CodePoolClosed},
+	{ErrListLimitExceeded, CodeListLimitExceeded},
+	{ErrInvalidConfig, CodeInvalidConfig},
+	{ErrInvalidNameTemplate, CodeInvalidTemplate},
+	{ErrDecryptFailed, CodeDecryptFailed},
+	{ErrNotFound, CodeKeyNotFound},
+	{ErrUnknownPattern, CodeUnknownPattern},
+}
+
+// ErrorCodeOf 返回错误的分类码（err 为 nil 时返回空字符串）
+//
+// 示例:
+//   switch mail2sdk.ErrorCodeOf(err) {
+//   case mail2sdk.CodeRateLimited:
+//       time.Sleep(time.Minute)
+//   case mail2sdk.CodeUnauthorized:
+//       log.Fatal("API 密钥无效")
+//   }
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == 401 || apiErr.StatusCode == 403:
+			return CodeUnauthorized
+		case apiErr.StatusCode == 404:
+			return CodeNotFound
+		case apiErr.StatusCode == 429:
+			return CodeRateLimited
+		case apiErr.StatusCode >= 500:
+			return CodeServer
+		}
+		return CodeAPI
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return CodeDecode
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return CodeTimeout
+		}
+		return CodeNetwork
+	}
+	return CodeUnknown
+}
+
+// Locale 错误提示的语言
+type Locale string
+
+// 支持的语言
+const (
+	LocaleEnglish Locale = "en" // 英文
+	LocaleChinese Locale = "zh" // 中文
+)
+
+// errorMessages 各语言的错误提示
+var errorMessages = map[Locale]map[ErrorCode]string{
+	LocaleEnglish: {
+		CodeUnknown:            "unexpected error",
+		CodeCanceled:           "operation canceled",
+		CodeTimeout:            "operation timed out",
+		CodeNetwork:            "cannot reach the mail service",
+		CodeUnauthorized:       "invalid API key or permission denied",
+		CodeNotFound:           "mailbox or mail not found",
+		CodeRateLimited:        "too many requests, please retry later",
+		CodeServer:             "mail service is temporarily unavailable",
+		CodeAPI:                "mail service returned an error",
+		CodeDecode:             "mail service returned a non-JSON response",
+		CodeUnexpectedResponse: "mail service response does not match the sdk",
+		CodeChallenge:          "request was blocked by a WAF/CDN challenge",
+		CodeRedirectedToLogin:  "request was redirected to a login page",
+		CodeNotSupported:       "feature is not supported by the mail service",
+		CodeNoDomains:          "no domains available",
+		CodeReadOnly:           "client is read-only",
+		CodeBudgetExceeded:     "operation budget exceeded",
+		CodeClientClosed:       "client is closed",
+		CodeNoDefaultClient:    "default client is not set",
+		CodeTooManyWatchers:    "too many active watchers",
+		CodeUnsafeLink:         "link failed the safety check",
+		CodeLowConfidence:      "verification code confidence is too low",
+		CodeAttachmentRejected: "attachment was rejected",
+		CodePoolClosed:         "mailbox pool is closed",
+		CodeListLimitExceeded:  "mailbox list exceeds the limit",
+		CodeInvalidConfig:      "invalid configuration",
+		CodeInvalidTemplate:    "invalid mailbox name template",
+		CodeDecryptFailed:      "decryption failed",
+		CodeKeyNotFound:        "key not found in state store",
+		CodeUnknownPattern:     "pattern is not registered",
+	},
+	LocaleChinese: {
+		CodeUnknown:            "未知错误",
+		CodeCanceled:           "操作已取消",
+		CodeTimeout:            "操作超时",
+		CodeNetwork:            "无法连接邮箱服务",
+		CodeUnauthorized:       "API 密钥无效或没有权限",
+		CodeNotFound:           "邮箱或邮件不存在",
+		CodeRateLimited:        "请求过于频繁，请稍后重试",
+		CodeServer:             "邮箱服务暂时不可用",
+		CodeAPI:                "邮箱服务返回错误",
+		CodeDecode:             "邮箱服务返回了非 JSON 响应",
+		CodeUnexpectedResponse: "邮箱服务的响应结构与 SDK 不一致",
+		CodeChallenge:          "请求被 WAF/CDN 人机验证拦截",
+		CodeRedirectedToLogin:  "请求被重定向到登录页",
+		CodeNotSupported:       "邮箱服务不支持该功能",
+		CodeNoDomains:          "没有可用域名",
+		CodeReadOnly:           "客户端为只读模式",
+		CodeBudgetExceeded:     "操作耗时预算已用完",
+		CodeClientClosed:       "客户端已关闭",
+		CodeNoDefaultClient:    "没有设置默认客户端",
+		CodeTooManyWatchers:    "同时监听的数量超出上限",
+		CodeUnsafeLink:         "链接未通过安全检查",
+		CodeLowConfidence:      "验证码置信度过低",
+		CodeAttachmentRejected: "附件被拒绝下载",
+		CodePoolClosed:         "邮箱池已关闭",
+		CodeListLimitExceeded:  "邮箱列表超出上限",
+		CodeInvalidConfig:      "配置无效",
+		CodeInvalidTemplate:    "邮箱用户名模板无效",
+		CodeDecryptFailed:      "解密失败",
+		CodeKeyNotFound:        "状态存储中不存在该键",
+		CodeUnknownPattern:     "引用的正则没有注册",
+	},
+}
+
+// ErrorMessage 返回错误在指定语言下的提示
+//
+// 提示由 ErrorCodeOf 的分类码决定，同一分类在两种语言下含义一致；服务端返回的错误
+// 附带状态码或业务码和服务端的原始信息。不支持的语言按英文处理。完整的排查细节
+// 请记录 err.Error()。
+//
+// 示例:
+//   if err != nil {
+//       showToast(mail2sdk.ErrorMessage(err, mail2sdk.LocaleChinese)) // 如"请求过于频繁，请稍后重试"
+//       log.Printf("[%s] %v", mail2sdk.ErrorCodeOf(err), err)
+//   }
+func ErrorMessage(err error, locale Locale) string {
+	if err == nil {
+		return ""
+	}
+	messages, ok := errorMessages[locale]
+	if !ok {
+		locale, messages = LocaleEnglish, errorMessages[LocaleEnglish]
+	}
+	code := ErrorCodeOf(err)
+	msg := messages[code]
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (code == CodeAPI || code == CodeServer) {
+		detail := fmt.Sprintf("status=%d", apiErr.StatusCode)
+		if apiErr.StatusCode == 0 {
+			detail = fmt.Sprintf("code=%d", apiErr.Code)
+		}
+		if apiErr.Message != "" {
+			detail += ": " + truncateBody([]byte(apiErr.Message), 200)
+		}
+		if locale == LocaleChinese {
+			return msg + "（" + detail + "）"
+		}
+		return msg + " (" + detail + ")"
+	}
+	return msg
+}
+
+// WithLocale 设置 Client.ErrorMessage 使用的语言（默认英文）
+//
+// 只影响 ErrorMessage 的提示，err.Error() 始终为英文。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithLocale(mail2sdk.LocaleChinese))
+//   if _, err := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil); err != nil {
+//       fmt.Println(client.ErrorMessage(err)) // 如"没有可用域名"
+//   }
+func WithLocale(locale Locale) Option {
+	return func(c *Client) {
+		c.locale = locale
+	}
+}
+
+// ErrorMessage 按客户端设置的语言返回错误提示（见 WithLocale 和包级函数 ErrorMessage）
+func (c *Client) ErrorMessage(err error) string {
+	return ErrorMessage(err, c.settings().locale)
+}
@@ -0,0 +1,102 @@
+package mail2sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNoDomainsAvailable 表示过滤黑名单、禁用域名后没有可用于创建邮箱的域名
+var ErrNoDomainsAvailable = errors.New("no domains available")
+
+// APIError 表示服务端返回的错误
+//
+// HTTP 状态码非 2xx 时 StatusCode 为对应状态码；HTTP 请求成功但业务码非 0/200 时
+// StatusCode 为 0，Code 为业务码。可以通过 errors.As 获取：
+//
+//   var apiErr *mail2sdk.APIError
+//   if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+//       // 邮箱不存在
+//   }
+type APIError struct {
+	StatusCode int    // HTTP 状态码（业务错误时为 0）
+	Code       int    // 业务错误码（HTTP 错误时为 0）
+	Message    string // 错误信息（HTTP 错误时为原始响应体）
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("API error (status=%d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error (code=%d): %s", e.Code, e.Message)
+}
+
+// 服务端表示"域名已禁用"的常见错误信息（小写）
+var domainDisabledMessages = []string{
+	"domain disabled",
+	"domain is disabled",
+	"domain has been disabled",
+	"domain not enabled",
+	"域名已禁用",
+	"域名已被禁用",
+	"域名不可用",
+}
+
+// isDomainDisabledError 判断错误是否表示所选域名已被服务端禁用
+func isDomainDisabledError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Message)
+	for _, s := range domainDisabledMessages {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxDecodeErrorBody DecodeError 中保留的原始响应体长度
+const maxDecodeErrorBody = 1024
+
+// DecodeError 表示响应无法解析为 API 的 JSON 格式
+//
+// 最常见的原因是 CDN、WAF 或代理以 200 状态码返回了 HTML 页面（如 Cloudflare 的验证页），
+// Body 中保留了响应体的前 1KB（已脱敏），便于直接看出拿到的是什么内容。可以通过
+// errors.As 获取：
+//
+//   var decodeErr *mail2sdk.DecodeError
+//   if errors.As(err, &decodeErr) {
+//       log.Printf("响应不是 JSON（%s）: %s", decodeErr.ContentType, decodeErr.Body)
+//   }
+type DecodeError struct {
+	StatusCode  int    // HTTP 状态码
+	ContentType string // 响应的 Content-Type
+	Body        string // 响应体的前 1KB
+	Err         error  // 解析错误
+}
+
+// Error 实现 error 接口
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%v (status=%d, content-type=%q, body: %q)", e.Err, e.StatusCode, e.ContentType, e.Body)
+}
+
+// Unwrap 返回解析错误
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// truncateBody 截取响应体的前 max 字节（不截断 UTF-8 字符）
+func truncateBody(body []byte, max int) string {
+	if len(body) <= max {
+		return string(body)
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "..."
+}
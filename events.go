@@ -0,0 +1,68 @@
+package mail2sdk
+
+import (
+	"context"
+	"time"
+)
+
+// ClientEventType 客户端事件类型
+type ClientEventType string
+
+// 客户端事件类型常量
+const (
+	// EventDomainDisabled 创建邮箱时发现域名已被服务端禁用，该域名已移出自动选择
+	EventDomainDisabled ClientEventType = "domain.disabled"
+
+	// EventDomainCooling 在该域名上等待验证码超时，域名进入冷却期（见 WithDomainCooldown）
+	EventDomainCooling ClientEventType = "domain.cooling"
+
+	// EventCodeInSpam WaitForCode 在垃圾邮件文件夹中找到了验证码（见 WaitCodeOptions.IncludeSpam），
+	// 说明该域名的投递可能有问题
+	EventCodeInSpam ClientEventType = "code.in_spam"
+
+	// EventEndpointDown 某个 API 地址连续失败，暂停使用一段时间（见 WithEndpoints）
+	EventEndpointDown ClientEventType = "endpoint.down"
+)
+
+// ClientEvent 客户端在运行过程中产生的事件
+type ClientEvent struct {
+	Type   ClientEventType // 事件类型
+	Time   time.Time       // 事件发生时间
+	Domain string          // 相关域名
+	Err    error           // 触发事件的错误
+
+	Address string // 相关邮箱地址（EventCodeInSpam）
+	MailID  string // 相关邮件 ID（EventCodeInSpam）
+
+	Endpoint string // 相关 API 地址（EventEndpointDown）
+
+	Attrs map[string]string // 触发事件的调用通过 WithAttributes 附加的属性（只读，没有时为 nil）
+}
+
+// WithEventHandler 设置客户端事件回调
+//
+// 回调在发起请求的 goroutine 中同步执行，应尽快返回。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithEventHandler(func(e mail2sdk.ClientEvent) {
+//       if e.Type == mail2sdk.EventDomainDisabled {
+//           log.Printf("域名 %s 已被禁用: %v", e.Domain, e.Err)
+//       }
+//   }))
+func WithEventHandler(handler func(ClientEvent)) Option {
+	return func(c *Client) {
+		c.eventHandler = handler
+	}
+}
+
+// emit 附加调用方属性后触发客户端事件（未设置回调时忽略）
+func (c *Client) emit(ctx context.Context, event ClientEvent) {
+	if c.eventHandler == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Attrs = Attributes(ctx)
+	c.eventHandler(event)
+}
@@ -0,0 +1,523 @@
+package mail2sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 规则表达式：一个类似 CEL 的极简表达式语言，用于在配置文件中描述提取规则。
+//
+// 支持的语法：
+//   - 变量: subject、from、to、text、html、body（主题 + 正文）
+//   - 字符串字面量: "..." 或 '...'（支持 \" \\ \n 等转义）
+//   - 字符串方法: contains(s)、startsWith(s)、endsWith(s)、matches(re) 返回布尔值；
+//     find(re) 返回第一个分组（没有分组时为整个匹配）、lower()、upper()、trim() 返回字符串
+//   - 运算符: == != && || ! 和括号
+//
+// 示例:
+//   from.endsWith("@github.com") && subject.lower().contains("verify")
+//   body.find("code[:：]\\s*([0-9]{6})")
+
+// exprNode 表达式语法树节点
+type exprNode interface {
+	eval(env map[string]string) (interface{}, error)
+}
+
+// exprLiteral 字符串字面量
+type exprLiteral struct{ value string }
+
+// exprVar 变量引用
+type exprVar struct{ name string }
+
+// exprNot 逻辑非
+type exprNot struct{ operand exprNode }
+
+// exprBinary 二元运算
+type exprBinary struct {
+	op          string
+	left, right exprNode
+}
+
+// exprCall 字符串方法调用
+type exprCall struct {
+	recv   exprNode
+	method string
+	args   []exprNode
+	re     *regexp.Regexp // 参数为字面量时预编译的正则
+}
+
+// 表达式中可用的变量
+var exprVars = map[string]bool{
+	"subject": true, "from": true, "to": true, "text": true, "html": true, "body": true,
+}
+
+// 方法 -> 参数个数
+var exprMethods = map[string]int{
+	"contains": 1, "startsWith": 1, "endsWith": 1, "matches": 1, "find": 1,
+	"lower": 0, "upper": 0, "trim": 0,
+}
+
+func (n *exprLiteral) eval(env map[string]string) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *exprVar) eval(env map[string]string) (interface{}, error) {
+	return env[n.name], nil
+}
+
+func (n *exprNot) eval(env map[string]string) (interface{}, error) {
+	v, err := evalBool(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return !v, nil
+}
+
+func (n *exprBinary) eval(env map[string]string) (interface{}, error) {
+	switch n.op {
+	case "&&", "||":
+		left, err := evalBool(n.left, env)
+		if err != nil {
+			return nil, err
+		}
+		// 短路求值
+		if (n.op == "&&" && !left) || (n.op == "||" && left) {
+			return left, nil
+		}
+		return evalBool(n.right, env)
+	default: // == !=
+		left, err := n.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return (left == right) == (n.op == "=="), nil
+	}
+}
+
+func (n *exprCall) eval(env map[string]string) (interface{}, error) {
+	recv, err := evalString(n.recv, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var arg string
+	if len(n.args) == 1 {
+		if arg, err = evalString(n.args[0], env); err != nil {
+			return nil, err
+		}
+	}
+
+	switch n.method {
+	case "contains":
+		return strings.Contains(recv, arg), nil
+	case "startsWith":
+		return strings.HasPrefix(recv, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(recv, arg), nil
+	case "lower":
+		return strings.ToLower(recv), nil
+	case "upper":
+		return strings.ToUpper(recv), nil
+	case "trim":
+		return strings.TrimSpace(recv), nil
+	}
+
+	// matches / find
+	re := n.re
+	if re == nil {
+		if re, err = regexp.Compile(arg); err != nil {
+			return nil, fmt.Errorf("%s: invalid regexp: %w", n.method, err)
+		}
+	}
+	if n.method == "matches" {
+		return re.MatchString(recv), nil
+	}
+	m := re.FindStringSubmatch(recv)
+	switch {
+	case m == nil:
+		return "", nil
+	case len(m) > 1:
+		return m[1], nil
+	default:
+		return m[0], nil
+	}
+}
+
+// evalBool 求值并要求结果为布尔值
+func evalBool(n exprNode, env map[string]string) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected bool, got string %q", v)
+	}
+	return b, nil
+}
+
+// evalString 求值并要求结果为字符串
+func evalString(n exprNode, env map[string]string) (string, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string, got bool")
+	}
+	return s, nil
+}
+
+// exprType 表达式的结果类型
+type exprType string
+
+// 表达式结果类型常量
+const (
+	exprTypeString exprType = "string"
+	exprTypeBool   exprType = "bool"
+)
+
+// 方法 -> 返回值类型
+var exprMethodTypes = map[string]exprType{
+	"contains": exprTypeBool, "startsWith": exprTypeBool, "endsWith": exprTypeBool, "matches": exprTypeBool,
+	"find": exprTypeString, "lower": exprTypeString, "upper": exprTypeString, "trim": exprTypeString,
+}
+
+// checkExpr 在编译时检查表达式的类型，返回结果类型
+func checkExpr(n exprNode) (exprType, error) {
+	switch n := n.(type) {
+	case *exprLiteral, *exprVar:
+		return exprTypeString, nil
+	case *exprNot:
+		return exprTypeBool, expectExprType(n.operand, exprTypeBool, "operand of !")
+	case *exprBinary:
+		if n.op == "&&" || n.op == "||" {
+			if err := expectExprType(n.left, exprTypeBool, "left operand of "+n.op); err != nil {
+				return "", err
+			}
+			return exprTypeBool, expectExprType(n.right, exprTypeBool, "right operand of "+n.op)
+		}
+		left, err := checkExpr(n.left)
+		if err != nil {
+			return "", err
+		}
+		right, err := checkExpr(n.right)
+		if err != nil {
+			return "", err
+		}
+		if left != right {
+			return "", fmt.Errorf("%s compares %s with %s", n.op, left, right)
+		}
+		return exprTypeBool, nil
+	case *exprCall:
+		if err := expectExprType(n.recv, exprTypeString, "receiver of "+n.method); err != nil {
+			return "", err
+		}
+		for _, arg := range n.args {
+			if err := expectExprType(arg, exprTypeString, "argument of "+n.method); err != nil {
+				return "", err
+			}
+		}
+		return exprMethodTypes[n.method], nil
+	default:
+		return "", fmt.Errorf("unknown expression node %T", n)
+	}
+}
+
+// expectExprType 检查表达式的结果类型是否为 want
+func expectExprType(n exprNode, want exprType, what string) error {
+	got, err := checkExpr(n)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("%s must be %s, got %s", what, want, got)
+	}
+	return nil
+}
+
+// exprEnv 构建表达式求值所需的变量
+func exprEnv(detail *MailDetail) map[string]string {
+	return map[string]string{
+		"subject": detail.Subject,
+		"from":    detail.From,
+		"to":      strings.Join(detail.To, ","),
+		"text":    detail.TextBody,
+		"html":    detail.HTMLBody,
+		"body":    mailText(detail),
+	}
+}
+
+// exprParser 递归下降解析器
+type exprParser struct {
+	src    string
+	tokens []exprToken
+	pos    int
+}
+
+// exprToken 词法单元
+type exprToken struct {
+	kind  byte // 'i' 标识符, 's' 字符串, 'o' 运算符/标点
+	value string
+	pos   int
+}
+
+// parseExpr 解析表达式
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+	return node, nil
+}
+
+// tokenizeExpr 词法分析
+func tokenizeExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '"' || ch == '\'':
+			start := i
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if src[i] == ch {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					case '"', '\'', '\\':
+						b.WriteByte(src[i])
+					default:
+						// 保留其他转义，便于直接书写正则（如 \d、\s）
+						b.WriteByte('\\')
+						b.WriteByte(src[i])
+					}
+					continue
+				}
+				b.WriteByte(src[i])
+			}
+			tokens = append(tokens, exprToken{kind: 's', value: b.String(), pos: start})
+		case ch == '_' || unicode.IsLetter(rune(ch)):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: 'i', value: src[start:i], pos: start})
+		default:
+			op := ""
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "&&", "||", "==", "!=":
+					op = two
+				}
+			}
+			if op == "" {
+				if !strings.ContainsRune("!().,", rune(ch)) {
+					return nil, fmt.Errorf("unexpected character %q at %d", ch, i)
+				}
+				op = string(ch)
+			}
+			tokens = append(tokens, exprToken{kind: 'o', value: op, pos: i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	pos := len(p.src)
+	if p.pos < len(p.tokens) {
+		pos = p.tokens[p.pos].pos
+	}
+	return fmt.Errorf("parse expression at %d: %s", pos, fmt.Sprintf(format, args...))
+}
+
+// accept 当前词法单元为指定运算符时消耗它
+func (p *exprParser) accept(op string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == 'o' && p.tokens[p.pos].value == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.accept(op) {
+			right, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &exprBinary{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(".") {
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != 'i' {
+			return nil, p.errorf("expected method name")
+		}
+		method := p.tokens[p.pos].value
+		nargs, ok := exprMethods[method]
+		if !ok {
+			return nil, p.errorf("unknown method %q", method)
+		}
+		p.pos++
+
+		if !p.accept("(") {
+			return nil, p.errorf("expected (")
+		}
+		var args []exprNode
+		if !p.accept(")") {
+			for {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.accept(")") {
+					break
+				}
+				if !p.accept(",") {
+					return nil, p.errorf("expected , or )")
+				}
+			}
+		}
+		if len(args) != nargs {
+			return nil, p.errorf("%s expects %d argument(s), got %d", method, nargs, len(args))
+		}
+
+		call := &exprCall{recv: node, method: method, args: args}
+		if lit, ok := firstLiteral(args); ok && (method == "matches" || method == "find") {
+			re, err := regexp.Compile(lit)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid regexp: %w", method, err)
+			}
+			call.re = re
+		}
+		node = call
+	}
+	return node, nil
+}
+
+// firstLiteral 返回第一个参数的字面量值（不是字面量时返回 false）
+func firstLiteral(args []exprNode) (string, bool) {
+	if len(args) == 0 {
+		return "", false
+	}
+	lit, ok := args[0].(*exprLiteral)
+	if !ok {
+		return "", false
+	}
+	return lit.value, true
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+
+	tok := p.tokens[p.pos]
+	switch {
+	case tok.kind == 's':
+		p.pos++
+		return &exprLiteral{value: tok.value}, nil
+	case tok.kind == 'i':
+		if !exprVars[tok.value] {
+			return nil, p.errorf("unknown variable %q", tok.value)
+		}
+		p.pos++
+		return &exprVar{name: tok.value}, nil
+	case p.accept("("):
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected )")
+		}
+		return node, nil
+	default:
+		return nil, p.errorf("unexpected %s", strconv.Quote(tok.value))
+	}
+}
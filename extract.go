@@ -0,0 +1,167 @@
+package mail2sdk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Extractor 从邮件中提取结构化数据
+//
+// 返回的 map 以字段名为键（如 "code"、"link"），没有提取到内容时返回空 map。
+type Extractor interface {
+	Extract(detail *MailDetail) (map[string]string, error)
+}
+
+// ExtractorFunc 将普通函数适配为 Extractor
+type ExtractorFunc func(detail *MailDetail) (map[string]string, error)
+
+// Extract 实现 Extractor 接口
+func (f ExtractorFunc) Extract(detail *MailDetail) (map[string]string, error) {
+	return f(detail)
+}
+
+// Pipeline 按顺序执行的一组提取器
+//
+// 多个提取器产生相同字段时，先执行的提取器结果优先。
+type Pipeline []Extractor
+
+// Run 对邮件依次执行所有提取器并合并结果
+//
+// 单个提取器失败不会中断其他提取器，所有错误合并后返回。
+func (p Pipeline) Run(detail *MailDetail) (map[string]string, error) {
+	result := make(map[string]string)
+	var errs []error
+	for _, e := range p {
+		fields, err := e.Extract(detail)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for k, v := range fields {
+			if _, ok := result[k]; !ok {
+				result[k] = v
+			}
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+var (
+	codeCandidatePattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9-])([0-9]{4,8})(?:[^A-Za-z0-9-]|$)`)
+	linkPattern          = regexp.MustCompile(`(?i)https?://[^\s"'<>]+`)
+	invoicePattern       = regexp.MustCompile(`(?i)(?:invoice|receipt|order|发票|订单)[ \t]*(?:no\.?|number|id|#|号|编号)?[ \t]*[:：#]?[ \t]*([A-Z0-9][A-Z0-9-]*[0-9][A-Z0-9-]*)`)
+	trackingPattern      = regexp.MustCompile(`(?i)(?:tracking|运单|快递单)[ \t]*(?:no\.?|number|id|#|号|编号)?[ \t]*[:：#]?[ \t]*([A-Z0-9]{8,})`)
+)
+
+// CodeExtractor 提取验证码（字段 "code"）
+//
+// 在本地对邮件正文中独立出现的 4-8 位数字评分（见 RankCodes），取得分最高的候选。
+func CodeExtractor() Extractor {
+	return ExtractorFunc(func(detail *MailDetail) (map[string]string, error) {
+		text := mailText(detail)
+		candidates := RankCodes(findCodeCandidates(text), text)
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+		return map[string]string{"code": candidates[0].Code}, nil
+	})
+}
+
+// findCodeCandidates 查找文本中独立出现的 4-8 位数字（不属于订单号等标识符）
+func findCodeCandidates(text string) []string {
+	var codes []string
+	for _, m := range codeCandidatePattern.FindAllStringSubmatch(text, -1) {
+		codes = append(codes, m[1])
+	}
+	return codes
+}
+
+// LinkExtractor 提取链接（字段 "link" 为第一个链接，"links" 为全部链接，以换行分隔）
+//
+// 优先从 HTML 正文提取，退订链接会被排在最后。
+func LinkExtractor() Extractor {
+	return ExtractorFunc(func(detail *MailDetail) (map[string]string, error) {
+		links := mailLinks(detail)
+		if len(links) == 0 {
+			return nil, nil
+		}
+		return map[string]string{"link": links[0], "links": strings.Join(links, "\n")}, nil
+	})
+}
+
+// mailLinks 提取邮件中的链接（去重，退订链接排在最后）
+func mailLinks(detail *MailDetail) []string {
+	body := detail.HTMLBody
+	if strings.TrimSpace(body) == "" {
+		body = detail.TextBody
+	}
+
+	var links, unsubscribe []string
+	seen := make(map[string]bool)
+	for _, link := range linkPattern.FindAllString(body, -1) {
+		link = strings.TrimRight(strings.ReplaceAll(link, "&amp;", "&"), ".,;)")
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		if unsubscribeLink(link) {
+			unsubscribe = append(unsubscribe, link)
+		} else {
+			links = append(links, link)
+		}
+	}
+	return append(links, unsubscribe...)
+}
+
+// unsubscribeLink 判断是否为退订链接
+func unsubscribeLink(link string) bool {
+	return strings.Contains(strings.ToLower(link), "unsubscribe")
+}
+
+// InvoiceExtractor 提取发票号或订单号（字段 "invoice"）
+func InvoiceExtractor() Extractor {
+	return RegexExtractor("invoice", invoicePattern)
+}
+
+// TrackingExtractor 提取物流运单号（字段 "tracking"）
+func TrackingExtractor() Extractor {
+	return RegexExtractor("tracking", trackingPattern)
+}
+
+// RegexExtractor 使用正则表达式提取字段
+//
+// 正则包含命名分组时，每个命名分组作为一个字段；否则有分组时取第一个分组，
+// 没有分组时取整个匹配，字段名为 name。匹配范围为主题 + 正文。
+//
+// 示例:
+//   mail2sdk.RegexExtractor("coupon", regexp.MustCompile(`CODE-(\d{6})`))
+func RegexExtractor(name string, re *regexp.Regexp) Extractor {
+	return ExtractorFunc(func(detail *MailDetail) (map[string]string, error) {
+		if re == nil {
+			return nil, fmt.Errorf("extractor %q: regexp is required", name)
+		}
+
+		m := re.FindStringSubmatch(mailText(detail))
+		if m == nil {
+			return nil, nil
+		}
+
+		result := make(map[string]string)
+		for i, group := range re.SubexpNames() {
+			if i > 0 && group != "" && m[i] != "" {
+				result[group] = m[i]
+			}
+		}
+		if len(result) > 0 {
+			return result, nil
+		}
+
+		if len(m) > 1 {
+			result[name] = m[1]
+		} else {
+			result[name] = m[0]
+		}
+		return result, nil
+	})
+}
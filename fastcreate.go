@@ -0,0 +1,59 @@
+package mail2sdk
+
+import (
+	"context"
+	"strings"
+)
+
+// WithFastCreate 设置黑名单后创建邮箱时跳过预先获取域名列表
+//
+// 默认情况下，CreateMailbox 未指定域名但设置了黑名单时，会先调用 GetDomains 获取
+// 可用域名、在本地过滤后再选择，首次创建多一次往返。启用后直接发送创建请求，并在
+// 请求体中附带 exclude_domains 让服务端避开黑名单域名；旧版本服务端会忽略该字段，
+// 此时如果选中了黑名单域名，SDK 会删除该邮箱并退回常规流程，之后的创建也不再尝试
+// 快速路径。
+//
+// 快速路径由服务端选择域名，不参与客户端的轮询计数；使用 WithDomainKey 或
+// CreateMailboxWithDomains 时不受影响。适合对首次创建延迟敏感的交互式流程。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithBlacklist("eu.org"),
+//       mail2sdk.WithFastCreate(),
+//   )
+//   mailbox, err := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+func WithFastCreate() Option {
+	return func(c *Client) {
+		c.fastCreate = true
+	}
+}
+
+// createFast 请求服务端按黑名单选择域名创建邮箱
+//
+// 返回的 ok 为 false 表示服务端不支持 exclude_domains（选中了黑名单域名），
+// 调用方应退回预先获取域名列表的流程。
+func (c *Client) createFast(ctx context.Context, apiMode string, blacklist []string) (mailbox *Mailbox, ok bool, err error) {
+	if c.knownUnsupported(FeatureExcludeDomains) {
+		return nil, false, nil
+	}
+
+	mailbox, err = c.createMailbox(ctx, apiMode, "", blacklist)
+	if err != nil {
+		return nil, true, err
+	}
+
+	domain := strings.ToLower(mailbox.Domain)
+	if domain == "" {
+		domain = addressDomain(strings.ToLower(mailbox.Address))
+	}
+	if domain == "" || len(filterDomains([]string{domain}, blacklist)) > 0 {
+		return mailbox, true, nil
+	}
+
+	// 服务端忽略了 exclude_domains：删除该邮箱，之后不再尝试快速路径
+	c.markUnsupported(FeatureExcludeDomains)
+	if err := c.DeleteMailbox(ctx, mailbox.Address); err != nil {
+		c.log(ctx, LogEntry{Level: LogWarn, Message: "delete blacklisted mailbox failed", Err: err})
+	}
+	return nil, false, nil
+}
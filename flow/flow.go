@@ -0,0 +1,225 @@
+// Package flow 以结构化并发的方式批量执行邮箱流程
+//
+// Group 返回的 Scope 提供 Go/Wait 语义：每个流程从邮箱池中获得一个独占的邮箱，
+// 所有流程共享并发数和速率限制，任一流程返回致命错误（Fatal）时整组取消。
+// 流程结束后邮箱默认被删除，即使整组被取消也不会遗留邮箱。
+//
+// 使用示例:
+//   pool := client.NewPool(mail2sdk.PoolOptions{Mode: mail2sdk.ModeRandom})
+//   defer pool.Close(context.Background())
+//
+//   g := flow.Group(ctx, pool, flow.Options{Concurrency: 10})
+//   for i := 0; i < 50; i++ {
+//       g.Go(fmt.Sprintf("signup-%d", i), func(ctx context.Context, s *mail2sdk.Session) error {
+//           if err := signup(ctx, s.Address()); err != nil {
+//               return flow.Fatal(err) // 停止所有流程
+//           }
+//           _, err := s.WaitForMail(ctx, mail2sdk.FromDomain("example.com"))
+//           return err
+//       })
+//   }
+//   if err := g.Wait(); err != nil {
+//       log.Fatal(err)
+//   }
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// Flow 一个流程，s 绑定该流程独占的邮箱
+type Flow func(ctx context.Context, s *mail2sdk.Session) error
+
+// Limiter 速率限制接口（与 golang.org/x/time/rate.Limiter 兼容）
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Options 流程组配置
+type Options struct {
+	Concurrency int     // 同时执行的最大流程数（<= 0 表示不限制）
+	Limiter     Limiter // 所有流程共享的速率限制（可选），每个流程开始前和调用 Throttle 时等待
+	Reuse       bool    // 为 true 时流程成功后把邮箱归还到池中，而不是删除
+}
+
+// fatalError 标记致命错误
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string { return e.err.Error() }
+func (e *fatalError) Unwrap() error { return e.err }
+
+// Fatal 将错误标记为致命错误，流程返回该错误时整组取消
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &fatalError{err: err}
+}
+
+// IsFatal 判断错误是否为致命错误
+func IsFatal(err error) bool {
+	var fe *fatalError
+	return errors.As(err, &fe)
+}
+
+// FlowError 单个流程的错误
+type FlowError struct {
+	Name    string // 流程名称
+	Address string // 流程使用的邮箱地址（获取邮箱失败时为空）
+	Err     error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *FlowError) Error() string {
+	if e.Address == "" {
+		return fmt.Sprintf("flow %s: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("flow %s (%s): %v", e.Name, e.Address, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *FlowError) Unwrap() error {
+	return e.Err
+}
+
+// Scope 一组共同取消的流程
+type Scope struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	pool   *mail2sdk.Pool
+	opts   Options
+	sem    chan struct{}
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Group 创建流程组
+//
+// 返回的 Scope 在 ctx 被取消、任一流程返回致命错误或 Wait 返回后取消。
+//
+// 参数:
+//   ctx: 上下文
+//   pool: 为每个流程提供邮箱的邮箱池
+//   opts: 流程组配置
+func Group(ctx context.Context, pool *mail2sdk.Pool, opts Options) *Scope {
+	ctx, cancel := context.WithCancelCause(ctx)
+	s := &Scope{ctx: ctx, cancel: cancel, pool: pool, opts: opts}
+	if opts.Concurrency > 0 {
+		s.sem = make(chan struct{}, opts.Concurrency)
+	}
+	return s
+}
+
+// Context 返回流程组的上下文（整组取消时结束）
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Go 启动一个流程
+//
+// 流程在获得并发名额、通过速率限制并从邮箱池取得邮箱后执行。整组已取消时
+// 尚未开始的流程会被跳过，不计入错误。
+func (s *Scope) Go(name string, fn Flow) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.run(name, fn); err != nil {
+			s.mu.Lock()
+			s.errs = append(s.errs, err)
+			s.mu.Unlock()
+			if IsFatal(err) {
+				s.cancel(err)
+			}
+		}
+	}()
+}
+
+// run 执行单个流程并释放其邮箱
+func (s *Scope) run(name string, fn Flow) error {
+	if s.sem != nil {
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case s.sem <- struct{}{}:
+		}
+		defer func() { <-s.sem }()
+	}
+	if s.ctx.Err() != nil {
+		return nil
+	}
+	if s.opts.Limiter != nil {
+		if err := s.opts.Limiter.Wait(s.ctx); err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+			return &FlowError{Name: name, Err: err}
+		}
+	}
+
+	mailbox, err := s.pool.Get(s.ctx)
+	if err != nil {
+		if s.ctx.Err() != nil {
+			return nil
+		}
+		return &FlowError{Name: name, Err: fmt.Errorf("get mailbox failed: %w", err)}
+	}
+
+	session := s.pool.Client().NewSession(mailbox)
+	err = fn(withScope(s.ctx, s), session)
+	s.release(session, err == nil)
+
+	if err != nil {
+		return &FlowError{Name: name, Address: mailbox.Address, Err: err}
+	}
+	return nil
+}
+
+// release 归还或删除流程使用的邮箱（整组已取消时仍会删除）
+func (s *Scope) release(session *mail2sdk.Session, ok bool) {
+	if ok && s.opts.Reuse && s.pool.Put(session.Mailbox()) == nil {
+		session.Detach()
+		return
+	}
+	s.pool.Discard(session.Mailbox())
+	session.Close(context.WithoutCancel(s.ctx))
+}
+
+// Wait 等待所有流程结束
+//
+// 返回所有失败流程的错误（*FlowError，可用 errors.As 逐个检查），全部成功时返回 nil。
+func (s *Scope) Wait() error {
+	s.wg.Wait()
+	s.cancel(nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.errs...)
+}
+
+type scopeKey struct{}
+
+// withScope 在上下文中记录所属的流程组
+func withScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// Throttle 在流程内部等待流程组共享的速率限制
+//
+// 流程中需要额外请求（如重发验证码）时调用，使所有流程共同遵守同一个速率。
+// ctx 不属于任何流程组或流程组未设置 Limiter 时立即返回。
+func Throttle(ctx context.Context) error {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	if s == nil || s.opts.Limiter == nil {
+		return nil
+	}
+	return s.opts.Limiter.Wait(ctx)
+}
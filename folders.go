@@ -0,0 +1,154 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 邮件文件夹
+const (
+	FolderInbox = "inbox" // 收件箱（GetMails 返回的邮件）
+	FolderSpam  = "spam"  // 垃圾邮件
+)
+
+// FolderInfo 邮箱文件夹信息
+type FolderInfo struct {
+	Name   string `json:"name"`   // 文件夹名称（如 FolderInbox、FolderSpam）
+	Count  int    `json:"count"`  // 邮件数量
+	Unread int    `json:"unread"` // 未读邮件数量（服务端未返回时为 0）
+}
+
+// ListFolders 获取邮箱的文件夹列表
+//
+// 服务端不支持文件夹时返回 ErrNotSupportedByServer，此时所有邮件都在收件箱中。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//
+// 返回:
+//   []FolderInfo: 文件夹列表
+//   error: 错误信息
+func (c *Client) ListFolders(ctx context.Context, address string) ([]FolderInfo, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.requireFeature(ctx, FeatureFolders); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Folders []FolderInfo `json:"folders"`
+	}
+
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "GET", "/api/mailbox/"+escaped+"/folders", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Folders, nil
+}
+
+// GetFolderMails 获取邮箱某个文件夹中的邮件列表
+//
+// folder 为空或 FolderInbox 时读取收件箱。临时域名的验证邮件经常被归入垃圾邮件，
+// 收不到邮件时可以检查 FolderSpam。返回的邮件 Folder 字段为所在文件夹。
+// 与 GetMails(ctx, address, WithFolder(folder)) 相同。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   folder: 文件夹名称
+//   opts: 可选的调用参数（如 WithSince、WithLimit）
+//
+// 返回:
+//   []Mail: 邮件列表
+//   error: 错误信息（服务端不支持文件夹时为 ErrNotSupportedByServer）
+//
+// 示例:
+//   spam, err := client.GetFolderMails(ctx, mailbox.Address, mail2sdk.FolderSpam)
+func (c *Client) GetFolderMails(ctx context.Context, address, folder string, opts ...CallOption) ([]Mail, error) {
+	if folder == "" {
+		folder = FolderInbox
+	}
+	return c.listMails(ctx, address, folder, newCallOptions(opts))
+}
+
+// RescueFromSpam 将垃圾邮件文件夹中的邮件标记为非垃圾邮件并移回收件箱
+//
+// 服务端不支持文件夹时返回 ErrNotSupportedByServer。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   mailID: 邮件 ID
+//
+// 返回:
+//   error: 错误信息
+//
+// 示例:
+//   spam, _ := client.GetFolderMails(ctx, address, mail2sdk.FolderSpam)
+//   for _, m := range spam {
+//       client.RescueFromSpam(ctx, address, m.ID)
+//   }
+func (c *Client) RescueFromSpam(ctx context.Context, address, mailID string) error {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return err
+	}
+	escapedID, err := escapePathSegment("mailID", mailID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.requireFeature(ctx, FeatureFolders); err != nil {
+		return err
+	}
+
+	path := "/api/mailbox/" + escaped + "/mails/" + escapedID + "/not-spam"
+	if err := c.do(ctx, "POST", path, nil, nil); err != nil {
+		return err
+	}
+
+	// 缓存的详情中记录的文件夹已经过时
+	if cache := getDetailCache(); cache != nil {
+		cache.removeMail(c.baseURL, address, mailID)
+	}
+	return nil
+}
+
+// withFolder 为服务端未标注文件夹的邮件补上文件夹名称
+func withFolder(mails []Mail, folder string) []Mail {
+	for i := range mails {
+		if mails[i].Folder == "" {
+			mails[i].Folder = folder
+		}
+	}
+	return mails
+}
+
+// watchMails 获取监听需要的邮件（IncludeSpam 时包含垃圾邮件）
+//
+// 垃圾邮件文件夹获取失败时只在服务端不支持文件夹的情况下忽略，spamOff 会被置为 true，
+// 之后的轮询不再请求；其他错误与收件箱的错误一样作为事件发出。
+func (c *Client) watchMails(ctx context.Context, address string, opts WatchOptions, spamOff *bool) ([]Mail, error) {
+	mails, err := c.GetMails(ctx, address)
+	if err != nil || !opts.IncludeSpam || *spamOff {
+		return mails, err
+	}
+
+	spam, err := c.GetFolderMails(ctx, address, FolderSpam)
+	if err != nil {
+		var apiErr *APIError
+		if errors.Is(err, ErrNotSupportedByServer) || (errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+			*spamOff = true
+			return mails, nil
+		}
+		return mails, fmt.Errorf("get spam folder failed: %w", err)
+	}
+	return append(mails, spam...), nil
+}
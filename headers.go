@@ -0,0 +1,121 @@
+package mail2sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// protectedHeaders 由 SDK 管理、不能通过自定义请求头覆盖的请求头
+var protectedHeaders = map[string]bool{
+	"X-Api-Key":       true,
+	"X-Mailbox-Token": true,
+	"Content-Type":    true,
+}
+
+// WithHeader 为客户端发出的每个请求添加请求头
+//
+// 适用于租户 ID、链路追踪、CDN 访问令牌等需要随请求发送的信息。X-API-Key、
+// X-Mailbox-Token 和 Content-Type 由 SDK 管理，设置这些请求头会被忽略。
+// 日志回调中的请求头同样经过脱敏，令牌类的值可以配合 WithRedactor 隐藏。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithHeader("X-Tenant-ID", "team-a"),
+//       mail2sdk.WithHeader("CF-Access-Client-Id", clientID),
+//   )
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set(key, value)
+	}
+}
+
+// WithHeaders 为客户端发出的每个请求添加多个请求头（规则同 WithHeader）
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		for key, value := range headers {
+			WithHeader(key, value)(c)
+		}
+	}
+}
+
+// WithUserAgentSuffix 在 SDK 的 User-Agent（Mail2SDK-Go/x.y.z）之后附加应用标识
+//
+// 服务端日志可以据此区分不同的接入方。多次调用时按顺序追加，空白字符串会被忽略。
+// User-Agent 由 SDK 管理，通过 WithHeader 设置的同名请求头不会生效。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithUserAgentSuffix("mybot/1.2"))
+//   // User-Agent: Mail2SDK-Go/1.1.0 mybot/1.2
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		normalized := strings.Join(strings.Fields(suffix), " ")
+		if normalized == "" {
+			return
+		}
+		if c.userAgentSuffix != "" {
+			normalized = c.userAgentSuffix + " " + normalized
+		}
+		c.userAgentSuffix = normalized
+	}
+}
+
+// requestHeaders 单次调用附加的请求头
+type requestHeaders struct {
+	parent *requestHeaders
+	key    string
+	value  string
+}
+
+// WithRequestHeader 返回一个上下文，使用该上下文发出的请求会额外携带该请求头
+//
+// 可以多次调用叠加多个请求头，与 WithHeader 同名时覆盖客户端级别的值。适合
+// 每次调用都不同的值，如链路追踪 ID。
+//
+// 示例:
+//   ctx = mail2sdk.WithRequestHeader(ctx, "X-Request-ID", requestID)
+//   mails, err := client.GetMails(ctx, address)
+func WithRequestHeader(ctx context.Context, key, value string) context.Context {
+	parent, _ := ctx.Value(ctxKeyRequestHeaders).(*requestHeaders)
+	return context.WithValue(ctx, ctxKeyRequestHeaders, &requestHeaders{parent: parent, key: key, value: value})
+}
+
+// applyHeaders 写入客户端级别、按域名匹配（见 WithDomainHeaders）和上下文中的自定义请求头
+//
+// overwrite 为 false 时不覆盖请求中已有的请求头（用于 DoRaw 传入的请求）。
+func (c *Client) applyHeaders(req *http.Request, overwrite bool) {
+	var original http.Header
+	if !overwrite {
+		original = req.Header.Clone()
+	}
+	set := func(key, value string) {
+		key = http.CanonicalHeaderKey(key)
+		if protectedHeaders[key] || original[key] != nil {
+			return
+		}
+		req.Header[key] = []string{value}
+	}
+
+	for key, values := range c.settings().headers {
+		if len(values) > 0 {
+			set(key, values[0])
+		}
+	}
+	for _, headers := range c.matchDomainHeaders(req) {
+		for key, values := range headers {
+			set(key, values[0])
+		}
+	}
+
+	// 链表从最近一次调用开始，先写入较早的值，后设置的同名请求头生效
+	var chain []*requestHeaders
+	for h, _ := req.Context().Value(ctxKeyRequestHeaders).(*requestHeaders); h != nil; h = h.parent {
+		chain = append(chain, h)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		set(chain[i].key, chain[i].value)
+	}
+}
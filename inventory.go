@@ -0,0 +1,143 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 本地记录邮箱的来源
+const (
+	InventorySession = "session"  // 尚未关闭的 Session
+	InventoryPool    = "pool"     // 邮箱池中的空闲邮箱
+	InventoryPoolOut = "pool_out" // 从邮箱池取出、尚未归还的邮箱
+)
+
+// InventoryEntry 邮箱清单中的一项
+type InventoryEntry struct {
+	Mailbox   Mailbox       // 邮箱信息（服务端有记录时以服务端为准）
+	Local     []string      // 本地记录的来源（InventorySession、InventoryPool，为空表示本地没有记录）
+	OnServer  bool          // 服务端是否有记录
+	ExpiresIn time.Duration // 距离过期的时间（已过期为负数，未返回过期时间时为 0）
+
+	LocalOnly  bool // 只在本地有记录：邮箱可能已过期或被其他途径删除
+	ServerOnly bool // 只在服务端有记录：可能是忘记关闭的会话或进程重启前创建的邮箱
+}
+
+// Inventory 合并本地记录与服务端邮箱列表，按剩余有效期排列
+//
+// 本地记录包括尚未关闭的会话（NewSession、OpenSession）和该客户端所有邮箱池中的
+// 空闲邮箱、已取出尚未归还的邮箱。即将过期的邮箱排在前面，没有过期时间的排在最后。LocalOnly、ServerOnly
+// 标出两边不一致的邮箱，用于排查客户端记录与服务端之间的泄漏。
+//
+// 服务端不支持列出邮箱时返回 ErrNotSupportedByServer。
+//
+// 返回:
+//   []InventoryEntry: 邮箱清单
+//   error: 错误信息
+//
+// 示例:
+//   entries, err := client.Inventory(ctx)
+//   for _, e := range entries {
+//       if e.ServerOnly {
+//           fmt.Printf("可能泄漏: %s（%s 后过期）\n", e.Mailbox.Address, e.ExpiresIn)
+//       }
+//   }
+func (c *Client) Inventory(ctx context.Context) ([]InventoryEntry, error) {
+	mailboxes, err := c.ListMailboxes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list mailboxes failed: %w", err)
+	}
+
+	entries := make(map[string]*InventoryEntry, len(mailboxes))
+	var order []string
+	add := func(mailbox Mailbox) *InventoryEntry {
+		key := strings.ToLower(mailbox.Address)
+		entry, ok := entries[key]
+		if !ok {
+			entry = &InventoryEntry{Mailbox: mailbox}
+			entries[key] = entry
+			order = append(order, key)
+		}
+		return entry
+	}
+
+	for _, mailbox := range mailboxes {
+		add(mailbox).OnServer = true
+	}
+	for _, local := range c.localMailboxes() {
+		entry := add(*local.mailbox)
+		if !containsString(entry.Local, local.source) {
+			entry.Local = append(entry.Local, local.source)
+		}
+	}
+
+	now := time.Now()
+	result := make([]InventoryEntry, 0, len(order))
+	for _, key := range order {
+		entry := entries[key]
+		entry.LocalOnly = !entry.OnServer
+		entry.ServerOnly = len(entry.Local) == 0
+		if !entry.Mailbox.ExpiresAt.IsZero() {
+			entry.ExpiresIn = entry.Mailbox.ExpiresAt.Sub(now)
+		}
+		result = append(result, *entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].Mailbox.ExpiresAt, result[j].Mailbox.ExpiresAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return result, nil
+}
+
+// localMailbox 本地记录的一个邮箱
+type localMailbox struct {
+	mailbox *Mailbox
+	source  string
+}
+
+// localMailboxes 返回尚未关闭的会话、邮箱池空闲邮箱和已取出的邮箱
+func (c *Client) localMailboxes() []localMailbox {
+	var locals []localMailbox
+
+	c.sessionsMu.Lock()
+	for s := range c.sessions {
+		locals = append(locals, localMailbox{mailbox: s.mailbox, source: InventorySession})
+	}
+	c.sessionsMu.Unlock()
+
+	c.poolsMu.Lock()
+	pools := make([]*Pool, 0, len(c.pools))
+	for p := range c.pools {
+		pools = append(pools, p)
+	}
+	c.poolsMu.Unlock()
+
+	for _, p := range pools {
+		p.mu.Lock()
+		for _, mailbox := range p.idle {
+			locals = append(locals, localMailbox{mailbox: mailbox, source: InventoryPool})
+		}
+		for mailbox := range p.out {
+			locals = append(locals, localMailbox{mailbox: mailbox, source: InventoryPoolOut})
+		}
+		p.mu.Unlock()
+	}
+	return locals
+}
+
+// containsString 判断切片中是否包含 s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
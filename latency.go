@@ -0,0 +1,180 @@
+package mail2sdk
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 每个发件域名/邮箱域名组合最多保留的样本数（超出后丢弃最旧的样本）
+const maxLatencySamples = 1000
+
+// LatencyStats 一组邮件到达延迟的统计结果
+type LatencyStats struct {
+	Sender string        // 发件人域名
+	Domain string        // 收件邮箱域名
+	Count  int           // 样本数
+	Mean   time.Duration // 平均延迟
+	P50    time.Duration // 50 分位延迟
+	P90    time.Duration // 90 分位延迟
+	P99    time.Duration // 99 分位延迟
+	Max    time.Duration // 最大延迟
+}
+
+// latencyKey 统计分组
+type latencyKey struct {
+	sender string
+	domain string
+}
+
+// LatencyTracker 记录邮件到达延迟（从触发发送到收到邮件）
+//
+// 按发件人域名和收件邮箱域名分组统计，用于量化哪些服务商、哪些域名收信慢，
+// 并据此调整等待超时。LatencyTracker 可以在多个 goroutine 中共享。
+type LatencyTracker struct {
+	mu      sync.Mutex
+	samples map[latencyKey][]time.Duration
+}
+
+// NewLatencyTracker 创建延迟统计器
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{samples: make(map[latencyKey][]time.Duration)}
+}
+
+// Observe 记录一封邮件的到达延迟
+//
+// 优先使用服务端记录的接收时间；接收时间缺失或因时钟偏差落在触发时间之前、
+// 当前时间之后时，使用当前时间。
+//
+// 参数:
+//   trigger: 触发发送的时间（如点击"发送验证码"的时间）
+//   mailbox: 收件邮箱地址
+//   detail: 收到的邮件
+//
+// 返回:
+//   time.Duration: 记录的延迟
+func (t *LatencyTracker) Observe(trigger time.Time, mailbox string, detail *MailDetail) time.Duration {
+	now := time.Now()
+	arrived := detail.ReceivedAt
+	if arrived.IsZero() || arrived.Before(trigger) || arrived.After(now) {
+		arrived = now
+	}
+	latency := arrived.Sub(trigger)
+
+	key := latencyKey{sender: addressDomain(senderAddress(detail.From)), domain: addressDomain(strings.ToLower(mailbox))}
+
+	t.mu.Lock()
+	samples := append(t.samples[key], latency)
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	t.samples[key] = samples
+	t.mu.Unlock()
+
+	return latency
+}
+
+// Stats 返回按发件人域名、收件邮箱域名排序的统计结果
+func (t *LatencyTracker) Stats() []LatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]LatencyStats, 0, len(t.samples))
+	for key, samples := range t.samples {
+		sorted := append([]time.Duration(nil), samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		var total time.Duration
+		for _, d := range sorted {
+			total += d
+		}
+		stats = append(stats, LatencyStats{
+			Sender: key.sender,
+			Domain: key.domain,
+			Count:  len(sorted),
+			Mean:   total / time.Duration(len(sorted)),
+			P50:    latencyPercentile(sorted, 0.50),
+			P90:    latencyPercentile(sorted, 0.90),
+			P99:    latencyPercentile(sorted, 0.99),
+			Max:    sorted[len(sorted)-1],
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Sender != stats[j].Sender {
+			return stats[i].Sender < stats[j].Sender
+		}
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// Reset 清空所有样本
+func (t *LatencyTracker) Reset() {
+	t.mu.Lock()
+	t.samples = make(map[latencyKey][]time.Duration)
+	t.mu.Unlock()
+}
+
+// latencyPercentile 计算已排序延迟的分位数
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// addressDomain 返回邮箱地址的域名部分（没有 @ 时原样返回）
+func addressDomain(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return address
+}
+
+// WithLatencyTracker 记录邮件到达延迟
+//
+// 设置后，携带触发时间（见 MarkTrigger 和 Session.Trigger）的等待调用
+// （WaitForMailMatching、WaitForCode、WaitForCodeOrLink）收到邮件时会记录延迟。
+//
+// 示例:
+//   tracker := mail2sdk.NewLatencyTracker()
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithLatencyTracker(tracker))
+//
+//   session.Trigger()
+//   site.SendCode(session.Address())
+//   session.WaitForCode(ctx, nil)
+//
+//   for _, s := range tracker.Stats() {
+//       fmt.Printf("%s -> %s: n=%d p50=%s p90=%s\n", s.Sender, s.Domain, s.Count, s.P50, s.P90)
+//   }
+func WithLatencyTracker(tracker *LatencyTracker) Option {
+	return func(c *Client) {
+		c.latency = tracker
+	}
+}
+
+// MarkTrigger 返回记录了触发时间的上下文
+//
+// 使用该上下文等待邮件时，收到的邮件会以 trigger 为起点记录到达延迟。
+func MarkTrigger(ctx context.Context, trigger time.Time) context.Context {
+	return context.WithValue(ctx, ctxKeyTrigger, trigger)
+}
+
+// observeArrival 记录等待到的邮件的到达延迟（未设置统计器或触发时间时忽略）
+func (c *Client) observeArrival(ctx context.Context, address string, detail *MailDetail) {
+	if c.latency == nil {
+		return
+	}
+	trigger, ok := ctx.Value(ctxKeyTrigger).(time.Time)
+	if !ok || trigger.IsZero() {
+		return
+	}
+	c.latency.Observe(trigger, address, detail)
+}
@@ -0,0 +1,81 @@
+package mail2sdk
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// legacyHook 旧 API 调用通知回调（见 SetLegacyCallHook）
+var legacyHook atomic.Pointer[func(LogEntry)]
+
+// legacyReported 已通知过的函数和调用位置
+var legacyReported sync.Map
+
+// SetLegacyCallHook 设置旧 API 调用通知回调，传 nil 关闭通知
+//
+// 以 baseURL、apiKey 为参数的包级函数（CreateMailbox、GetMailsCtx 等）仍然可用，
+// 内部同样通过 Client 发送请求。设置回调后，每个函数在每个调用位置第一次被调用时
+// 触发一次 LogWarn 级别的通知，Attrs 中包含 function（被调用的函数）、replacement
+// （建议替换为的 Client 方法）和 caller（调用位置，文件:行号），便于大型代码库逐步
+// 迁移并找出剩余的旧调用。回调可能在多个 goroutine 中被调用，应尽快返回。
+//
+// 示例:
+//   mail2sdk.SetLegacyCallHook(func(e mail2sdk.LogEntry) {
+//       log.Printf("%s（%s）", e.Message, e.Attrs["caller"])
+//   })
+func SetLegacyCallHook(hook func(LogEntry)) {
+	if hook == nil {
+		legacyHook.Store(nil)
+		return
+	}
+	legacyHook.Store(&hook)
+}
+
+// reportLegacyCall 向回调报告调用包级函数的位置（未设置回调或已报告过时忽略）
+//
+// 从调用栈中跳过本包的函数，第一个本包以外的栈帧即为调用位置，紧挨着它的本包函数即为
+// 被调用的包级函数。按函数名而不是模块路径判断是否属于本包，复制源文件使用时同样有效。
+func reportLegacyCall() {
+	hook := legacyHook.Load()
+	if hook == nil {
+		return
+	}
+
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	self, more := frames.Next()
+	pkg := self.Function[:strings.LastIndex(self.Function, ".")+1]
+	var function string
+	for more {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		name, ok := strings.CutPrefix(frame.Function, pkg)
+		if ok {
+			function = name
+			continue
+		}
+		if function == "" {
+			return
+		}
+
+		caller := frame.File + ":" + strconv.Itoa(frame.Line)
+		if _, reported := legacyReported.LoadOrStore(function+" "+caller, true); reported {
+			return
+		}
+		replacement := "Client." + strings.TrimSuffix(function, "Ctx")
+		(*hook)(LogEntry{
+			Time:    time.Now(),
+			Level:   LogWarn,
+			Message: fmt.Sprintf("mail2sdk.%s is a legacy function, use %s instead", function, replacement),
+			Attrs:   map[string]string{"function": function, "replacement": replacement, "caller": caller},
+		})
+		return
+	}
+}
@@ -0,0 +1,187 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// 列出邮箱的默认分页参数
+const (
+	defaultListPageSize = 100
+	defaultListMaxItems = 10000
+)
+
+// ErrListLimitExceeded 表示邮箱数量超过了 ListOptions.MaxItems
+var ErrListLimitExceeded = errors.New("mailbox list exceeds limit")
+
+// ListOptions 列出邮箱的分页配置
+type ListOptions struct {
+	PageSize int // 每页数量（<= 0 表示 100）
+	MaxItems int // 最多获取的邮箱数量，超过时返回 ErrListLimitExceeded（<= 0 表示 10000）
+}
+
+// withDefaults 填充默认值
+func (o *ListOptions) withDefaults() ListOptions {
+	var opts ListOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultListPageSize
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = defaultListMaxItems
+	}
+	return opts
+}
+
+// ListMailboxes 获取当前 API 密钥下的邮箱列表
+//
+// 自动翻页获取全部邮箱，相当于 ListAllMailboxes(ctx, nil)。
+// 服务端不支持该接口时返回 ErrNotSupportedByServer。
+//
+// 返回:
+//   []Mailbox: 邮箱列表
+//   error: 错误信息
+func (c *Client) ListMailboxes(ctx context.Context) ([]Mailbox, error) {
+	return c.ListAllMailboxes(ctx, nil)
+}
+
+// ListAllMailboxes 自动翻页获取当前 API 密钥下的全部邮箱
+//
+// 邮箱数量超过 opts.MaxItems 时停止翻页，返回已获取的前 MaxItems 个邮箱和
+// ErrListLimitExceeded，避免账号下邮箱过多时一次性占用大量内存。
+// 不支持分页的旧版本服务端会一次返回全部邮箱。
+//
+// 参数:
+//   ctx: 上下文
+//   opts: 分页配置（传 nil 使用默认值）
+//
+// 返回:
+//   []Mailbox: 邮箱列表
+//   error: 错误信息
+func (c *Client) ListAllMailboxes(ctx context.Context, opts *ListOptions) ([]Mailbox, error) {
+	it := c.IterateMailboxes(ctx, opts)
+	var mailboxes []Mailbox
+	for it.Next() {
+		mailboxes = append(mailboxes, it.Mailbox())
+	}
+	return mailboxes, it.Err()
+}
+
+// MailboxIterator 逐个遍历邮箱的迭代器
+//
+// 只在需要时请求下一页，适合在遍历过程中直接处理（如删除）邮箱。
+// 迭代器不是并发安全的。
+type MailboxIterator struct {
+	client *Client
+	ctx    context.Context
+	opts   ListOptions
+
+	page    []Mailbox
+	current Mailbox
+	cursor  string
+	started bool
+	done    bool // 已经没有下一页
+	seen    int
+	err     error
+}
+
+// IterateMailboxes 返回遍历当前 API 密钥下全部邮箱的迭代器
+//
+// 示例:
+//   it := client.IterateMailboxes(ctx, &mail2sdk.ListOptions{PageSize: 500})
+//   for it.Next() {
+//       mailbox := it.Mailbox()
+//       if mailbox.CreatedAt.Before(cutoff) {
+//           client.DeleteMailbox(ctx, mailbox.Address)
+//       }
+//   }
+//   if err := it.Err(); err != nil {
+//       log.Fatal(err)
+//   }
+func (c *Client) IterateMailboxes(ctx context.Context, opts *ListOptions) *MailboxIterator {
+	return &MailboxIterator{client: c, ctx: ctx, opts: opts.withDefaults()}
+}
+
+// Next 前进到下一个邮箱，没有更多邮箱或出错时返回 false
+func (it *MailboxIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	if it.seen >= it.opts.MaxItems {
+		it.err = fmt.Errorf("%w: more than %d mailboxes", ErrListLimitExceeded, it.opts.MaxItems)
+		return false
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	it.seen++
+	return true
+}
+
+// eachMailbox 遍历当前 API 密钥下的全部邮箱（不受 MaxItems 限制），供清理类操作使用
+func (c *Client) eachMailbox(ctx context.Context, fn func(Mailbox)) error {
+	it := c.IterateMailboxes(ctx, &ListOptions{MaxItems: math.MaxInt})
+	for it.Next() {
+		fn(it.Mailbox())
+	}
+	return it.Err()
+}
+
+// Mailbox 返回当前邮箱
+func (it *MailboxIterator) Mailbox() Mailbox {
+	return it.current
+}
+
+// Err 返回遍历过程中的错误
+func (it *MailboxIterator) Err() error {
+	return it.err
+}
+
+// fetch 获取下一页
+func (it *MailboxIterator) fetch() error {
+	c := it.client
+	if !it.started {
+		if err := c.requireFeature(it.ctx, FeatureListMailboxes); err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(it.opts.PageSize))
+	if it.cursor != "" {
+		query.Set("cursor", it.cursor)
+	}
+
+	var result struct {
+		Count      int       `json:"count"`
+		Mailboxes  []Mailbox `json:"mailboxes"`
+		NextCursor string    `json:"next_cursor"`
+	}
+	if err := c.do(it.ctx, "GET", "/api/mailboxes?"+query.Encode(), nil, &result); err != nil {
+		return c.markUnsupportedOn404(FeatureListMailboxes, err)
+	}
+
+	// 服务端没有返回新的游标时视为最后一页，防止游标不变导致死循环
+	if result.NextCursor == "" || result.NextCursor == it.cursor {
+		it.done = true
+	}
+	it.started = true
+	it.cursor = result.NextCursor
+	it.page = result.Mailboxes
+	return nil
+}
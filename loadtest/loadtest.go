@@ -0,0 +1,312 @@
+// Package loadtest 提供针对 Mail2 服务端的简易压测工具
+//
+// 按照指定速率依次执行 创建邮箱 -> 轮询邮件 -> 删除邮箱 三个阶段，
+// 输出每类操作的延迟分位数和错误统计，用于活动开始前评估自建实例的容量。
+//
+// 使用示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey)
+//   report, err := loadtest.Run(ctx, client, loadtest.Profile{
+//       Mailboxes: 200,
+//       RPS:       20,
+//       Duration:  time.Minute,
+//   })
+//   fmt.Println(report)
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// 操作名称
+const (
+	OpCreate = "create"
+	OpPoll   = "poll"
+	OpDelete = "delete"
+)
+
+// 每类操作最多保留的错误样例数量
+const maxErrorSamples = 10
+
+// Profile 压测配置
+type Profile struct {
+	Mailboxes   int           // 创建的邮箱数量
+	RPS         float64       // 每秒最多发起的操作数
+	Duration    time.Duration // 轮询阶段持续时间（0 表示跳过轮询阶段）
+	Mode        int           // 邮箱生成模式（mail2sdk.ModeAuto 等）
+	Domains     []string      // 候选域名（为空表示由服务端选择）
+	Concurrency int           // 同时进行中的最大请求数（<= 0 表示 32）
+	KeepMailbox bool          // 为 true 时跳过删除阶段
+}
+
+// OpStats 单类操作的统计结果
+type OpStats struct {
+	Count        int           // 总次数
+	Errors       int           // 失败次数
+	Mean         time.Duration // 平均延迟
+	P50          time.Duration // 50 分位延迟
+	P90          time.Duration // 90 分位延迟
+	P99          time.Duration // 99 分位延迟
+	Max          time.Duration // 最大延迟
+	ErrorSamples []string      // 错误样例（最多 10 条）
+}
+
+// Report 压测报告
+type Report struct {
+	Profile  Profile            // 使用的配置
+	Elapsed  time.Duration      // 总耗时
+	Ops      map[string]OpStats // 操作名称 -> 统计结果
+	Created  int                // 成功创建的邮箱数
+	Leftover []string           // 删除失败（需要手动清理）的邮箱
+}
+
+// String 以表格形式输出报告
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed=%s created=%d leftover=%d\n", r.Elapsed.Round(time.Millisecond), r.Created, len(r.Leftover))
+	fmt.Fprintf(&b, "%-8s %7s %7s %10s %10s %10s %10s %10s\n", "op", "count", "errors", "mean", "p50", "p90", "p99", "max")
+	for _, op := range []string{OpCreate, OpPoll, OpDelete} {
+		s, ok := r.Ops[op]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "%-8s %7d %7d %10s %10s %10s %10s %10s\n", op, s.Count, s.Errors,
+			s.Mean.Round(time.Millisecond), s.P50.Round(time.Millisecond), s.P90.Round(time.Millisecond),
+			s.P99.Round(time.Millisecond), s.Max.Round(time.Millisecond))
+	}
+	return b.String()
+}
+
+// recorder 并发安全的延迟记录器
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	samples   map[string][]string
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+		samples:   make(map[string][]string),
+	}
+}
+
+// record 记录一次操作
+func (r *recorder) record(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies[op] = append(r.latencies[op], d)
+	if err != nil {
+		r.errors[op]++
+		if len(r.samples[op]) < maxErrorSamples {
+			r.samples[op] = append(r.samples[op], err.Error())
+		}
+	}
+}
+
+// stats 汇总统计结果
+func (r *recorder) stats() map[string]OpStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string]OpStats, len(r.latencies))
+	for op, ls := range r.latencies {
+		sorted := append([]time.Duration(nil), ls...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		var total time.Duration
+		for _, d := range sorted {
+			total += d
+		}
+		result[op] = OpStats{
+			Count:        len(sorted),
+			Errors:       r.errors[op],
+			Mean:         total / time.Duration(len(sorted)),
+			P50:          percentile(sorted, 0.50),
+			P90:          percentile(sorted, 0.90),
+			P99:          percentile(sorted, 0.99),
+			Max:          sorted[len(sorted)-1],
+			ErrorSamples: r.samples[op],
+		}
+	}
+	return result
+}
+
+// percentile 计算已排序延迟的分位数
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// pacer 按固定速率放行操作，并限制同时进行中的操作数量
+type pacer struct {
+	ticker *time.Ticker
+	sem    chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newPacer(rps float64, concurrency int) *pacer {
+	interval := time.Duration(float64(time.Second) / rps)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+	return &pacer{
+		ticker: time.NewTicker(interval),
+		sem:    make(chan struct{}, concurrency),
+	}
+}
+
+// goPaced 等待下一个时间片后异步执行 fn，ctx 取消时返回 false
+func (p *pacer) goPaced(ctx context.Context, fn func()) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-p.ticker.C:
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case p.sem <- struct{}{}:
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.sem }()
+		fn()
+	}()
+	return true
+}
+
+// wait 等待所有进行中的操作结束
+func (p *pacer) wait() {
+	p.wg.Wait()
+}
+
+func (p *pacer) stop() {
+	p.ticker.Stop()
+}
+
+// Run 执行压测
+//
+// 三个阶段依次执行：按速率创建 Mailboxes 个邮箱；在 Duration 时间内按速率
+// 轮流获取这些邮箱的邮件列表；最后按速率删除所有邮箱。ctx 被取消时会跳过
+// 剩余的创建和轮询，但仍会尝试删除已创建的邮箱（使用独立的上下文）。
+//
+// 参数:
+//   ctx: 上下文
+//   client: 要压测的客户端
+//   profile: 压测配置
+//
+// 返回:
+//   *Report: 压测报告
+//   error: 配置错误
+func Run(ctx context.Context, client *mail2sdk.Client, profile Profile) (*Report, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if profile.Mailboxes <= 0 {
+		return nil, fmt.Errorf("profile.Mailboxes must be positive")
+	}
+	if profile.RPS <= 0 {
+		return nil, fmt.Errorf("profile.RPS must be positive")
+	}
+	concurrency := profile.Concurrency
+	if concurrency <= 0 {
+		concurrency = 32
+	}
+
+	start := time.Now()
+	rec := newRecorder()
+	p := newPacer(profile.RPS, concurrency)
+	defer p.stop()
+
+	// 阶段一：创建邮箱
+	var (
+		mu        sync.Mutex
+		addresses []string
+	)
+	for i := 0; i < profile.Mailboxes; i++ {
+		ok := p.goPaced(ctx, func() {
+			t0 := time.Now()
+			mailbox, err := client.CreateMailboxWithDomains(ctx, profile.Mode, profile.Domains, nil)
+			rec.record(OpCreate, time.Since(t0), err)
+			if err == nil {
+				mu.Lock()
+				addresses = append(addresses, mailbox.Address)
+				mu.Unlock()
+			}
+		})
+		if !ok {
+			break
+		}
+	}
+	p.wait()
+
+	// 阶段二：轮询邮件
+	if profile.Duration > 0 && len(addresses) > 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, profile.Duration)
+		for i := 0; ; i++ {
+			address := addresses[i%len(addresses)]
+			ok := p.goPaced(pollCtx, func() {
+				t0 := time.Now()
+				_, err := client.GetMails(pollCtx, address)
+				// 阶段结束导致的取消不计入错误
+				if err != nil && pollCtx.Err() != nil {
+					return
+				}
+				rec.record(OpPoll, time.Since(t0), err)
+			})
+			if !ok {
+				break
+			}
+		}
+		p.wait()
+		cancel()
+	}
+
+	// 阶段三：删除邮箱（即使 ctx 已取消也要清理）
+	var leftover []string
+	if !profile.KeepMailbox {
+		cleanupCtx := context.Background()
+		for _, address := range addresses {
+			address := address
+			p.goPaced(cleanupCtx, func() {
+				t0 := time.Now()
+				err := client.DeleteMailbox(cleanupCtx, address)
+				rec.record(OpDelete, time.Since(t0), err)
+				if err != nil {
+					mu.Lock()
+					leftover = append(leftover, address)
+					mu.Unlock()
+				}
+			})
+		}
+		p.wait()
+	}
+
+	return &Report{
+		Profile:  profile,
+		Elapsed:  time.Since(start),
+		Ops:      rec.stats(),
+		Created:  len(addresses),
+		Leftover: leftover,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package mail2sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// LogLevel 日志级别
+type LogLevel int
+
+// 日志级别常量
+const (
+	LogDebug LogLevel = iota // 每次 HTTP 请求
+	LogInfo                  // 一般信息
+	LogWarn                  // 可恢复的异常（如请求失败后重试）
+	LogError                 // 请求最终失败
+)
+
+// String 返回日志级别名称
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// LogEntry 一条客户端日志
+//
+// 传给日志回调前，Message、URL、Err 和 Header 都已经脱敏（见 WithRedactor）。
+type LogEntry struct {
+	Time     time.Time     // 记录时间
+	Level    LogLevel      // 日志级别
+	Message  string        // 日志内容
+	Method   string        // HTTP 方法
+	URL      string        // 请求地址
+	Status   int           // HTTP 状态码（请求未完成时为 0）
+	Attempt  int           // 第几次尝试（从 0 开始）
+	Duration time.Duration // 请求耗时
+	Header   http.Header   // 请求头（敏感请求头已替换为 [REDACTED]）
+	Err      error         // 错误信息
+
+	Attrs map[string]string // 调用方通过 WithAttributes 附加的属性（只读，没有时为 nil）
+}
+
+// WithLogHook 设置日志回调
+//
+// 每次 HTTP 请求完成后都会调用一次回调（成功为 LogDebug，失败为 LogWarn/LogError）。
+// 回调在发起请求的 goroutine 中同步执行，应尽快返回。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithLogHook(func(e mail2sdk.LogEntry) {
+//       if e.Level >= mail2sdk.LogWarn {
+//           log.Printf("[%s] %s %s status=%d err=%v", e.Level, e.Method, e.URL, e.Status, e.Err)
+//       }
+//   }))
+func WithLogHook(hook func(LogEntry)) Option {
+	return func(c *Client) {
+		c.logHook = hook
+	}
+}
+
+// log 附加调用方属性并脱敏后调用日志回调（未设置回调时忽略）
+func (c *Client) log(ctx context.Context, entry LogEntry) {
+	if c.logHook == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Attrs = Attributes(ctx)
+	entry.Message = c.redact(entry.Message)
+	entry.URL = c.redact(entry.URL)
+	entry.Err = c.redactError(entry.Err)
+	if entry.Header != nil {
+		entry.Header = c.redactHeader(entry.Header)
+	}
+	c.logHook(entry)
+}
@@ -1,13 +1,13 @@
 // Package mail2sdk 提供 Mail2 临时邮箱系统的 Go SDK
 //
-// 这是一个单文件 SDK，用户只需复制此文件到项目中即可使用。
+// SDK 仅依赖标准库，用户可以通过 go get 安装，也可以复制根目录下的全部 .go 文件到项目中使用。
 //
 // 功能特性:
 //   - 创建临时邮箱（支持 3 种模式 + 自动混用）
 //   - 获取可用域名列表
 //   - 指定域名或域名组创建邮箱
 //   - 获取邮件列表
-//   - 获取邮件详情（完整内容，支持用户自定义正则）
+//   - 获取邮件详情（完整内容，支持用户自定义正则，可选 LRU+TTL 缓存）
 //   - 提取验证码（API 内置）
 //   - 删除邮箱
 //
@@ -18,15 +18,10 @@
 package mail2sdk
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
+	"hash/fnv"
 	"math/rand"
-	"net/http"
-	"net/url"
-	"strconv"
 	"sync"
 	"time"
 )
@@ -34,10 +29,11 @@ import (
 // 版本信息
 const Version = "1.1.0"
 
-// 全局随机数生成器和域名选择器（线程安全）
+// 全局随机数生成器和包级函数使用的默认域名选择器（线程安全）
 var (
 	rng            *rand.Rand
 	rngOnce        sync.Once
+	rngMu          sync.Mutex // rand.Rand 本身不是并发安全的
 	domainSelector *DomainSelector
 	selectorOnce   sync.Once
 )
@@ -46,9 +42,19 @@ var (
 type DomainSelector struct {
 	mu      sync.Mutex
 	counters map[string]int // 每个域名的使用计数
+	disabled map[string]bool // 已被服务端禁用的域名（选择时跳过）
+	cooling  map[string]domainCooldown // 处于冷却期的域名
 }
 
-// getRand 获取线程安全的随机数生成器
+// domainCooldown 域名的冷却状态
+//
+// until 之前完全避开该域名；之后的 ramp 时间内按比例逐步恢复选择概率。
+type domainCooldown struct {
+	until time.Time
+	ramp  time.Duration
+}
+
+// getRand 获取全局随机数生成器（并发调用请使用 randIntn / randInt63n）
 func getRand() *rand.Rand {
 	rngOnce.Do(func() {
 		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -56,20 +62,58 @@ func getRand() *rand.Rand {
 	return rng
 }
 
-// getDomainSelector 获取全局域名选择器
+// randIntn 并发安全地返回 [0, n) 范围内的随机数
+func randIntn(n int) int {
+	r := getRand()
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return r.Intn(n)
+}
+
+// randInt63n 并发安全地返回 [0, n) 范围内的随机数
+func randInt63n(n int64) int64 {
+	r := getRand()
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return r.Int63n(n)
+}
+
+// getDomainSelector 获取包级函数使用的默认域名选择器
 func getDomainSelector() *DomainSelector {
 	selectorOnce.Do(func() {
-		domainSelector = &DomainSelector{
-			counters: make(map[string]int),
-		}
+		domainSelector = NewDomainSelector()
 	})
 	return domainSelector
 }
 
+// newDefaultClient 创建包级函数使用的客户端（共享默认域名选择器），并报告旧 API 调用（见 SetLegacyCallHook）
+func newDefaultClient(baseURL, apiKey string) *Client {
+	reportLegacyCall()
+	return NewClient(baseURL, apiKey, WithDomainSelector(getDomainSelector()))
+}
+
 // selectDomain 使用轮询策略选择域名（确保所有域名均匀使用）
 //
-// 策略：选择使用次数最少的域名，如果有多个最少使用的域名则随机选择一个
+// 策略：选择使用次数最少的域名，如果有多个最少使用的域名则随机选择一个。
+// 已被禁用的域名会被跳过，全部被禁用时返回空字符串。
 func (ds *DomainSelector) selectDomain(domains []string) string {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	// 跳过已被禁用的域名
+	if len(ds.disabled) > 0 {
+		enabled := make([]string, 0, len(domains))
+		for _, domain := range domains {
+			if !ds.disabled[domain] {
+				enabled = append(enabled, domain)
+			}
+		}
+		domains = enabled
+	}
+
+	// 避开冷却期中的域名
+	domains = ds.warmDomainsLocked(domains, time.Now(), true)
+
 	if len(domains) == 0 {
 		return ""
 	}
@@ -77,9 +121,6 @@ func (ds *DomainSelector) selectDomain(domains []string) string {
 		return domains[0]
 	}
 
-	ds.mu.Lock()
-	defer ds.mu.Unlock()
-
 	// 初始化计数器（如果是新域名）
 	for _, domain := range domains {
 		if _, exists := ds.counters[domain]; !exists {
@@ -102,7 +143,7 @@ func (ds *DomainSelector) selectDomain(domains []string) string {
 	}
 
 	// 从候选域名中随机选择一个
-	selected := candidates[getRand().Intn(len(candidates))]
+	selected := candidates[randIntn(len(candidates))]
 
 	// 增加使用计数
 	ds.counters[selected]++
@@ -110,6 +151,82 @@ func (ds *DomainSelector) selectDomain(domains []string) string {
 	return selected
 }
 
+// selectDomainByKey 按 key 确定性地选择域名
+//
+// 使用最高随机权重（rendezvous）哈希：同一个 key 在同一组域名中总是选中同一个域名，
+// 域名列表增减时只有原本落在变动域名上的 key 会改变。选中的域名被禁用时
+// 顺延到权重次高的域名。
+func (ds *DomainSelector) selectDomainByKey(domains []string, key string) string {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	enabled := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if !ds.disabled[domain] {
+			enabled = append(enabled, domain)
+		}
+	}
+	// 冷却期中的域名同样避开，但恢复阶段不做随机，保证结果确定
+	domains = ds.warmDomainsLocked(enabled, time.Now(), false)
+
+	selected := ""
+	var best uint64
+	for _, domain := range domains {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(domain))
+		if weight := h.Sum64(); selected == "" || weight > best {
+			selected, best = domain, weight
+		}
+	}
+
+	if selected != "" {
+		ds.counters[selected]++
+	}
+	return selected
+}
+
+// warmDomainsLocked 过滤掉冷却期中的域名（调用方需持有 ds.mu）
+//
+// ramp 为 true 时，冷却结束后的恢复阶段按经过时间的比例随机放行；全部域名都在
+// 冷却期时不做过滤，避免因此无法创建邮箱。
+func (ds *DomainSelector) warmDomainsLocked(domains []string, now time.Time, ramp bool) []string {
+	if len(ds.cooling) == 0 {
+		return domains
+	}
+
+	warm := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		cd, ok := ds.cooling[domain]
+		switch {
+		case !ok:
+			warm = append(warm, domain)
+		case now.Before(cd.until):
+			// 冷却期内
+		case now.Sub(cd.until) >= cd.ramp:
+			delete(ds.cooling, domain)
+			warm = append(warm, domain)
+		case !ramp || randInt63n(int64(cd.ramp)) < int64(now.Sub(cd.until)):
+			warm = append(warm, domain)
+		}
+	}
+
+	if len(warm) == 0 {
+		return domains
+	}
+	return warm
+}
+
+// cool 让域名进入冷却期，返回该域名之前是否不在冷却期
+func (ds *DomainSelector) cool(domain string, window, ramp time.Duration) bool {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	_, existed := ds.cooling[domain]
+	ds.cooling[domain] = domainCooldown{until: time.Now().Add(window), ramp: ramp}
+	return !existed
+}
+
 // resetCounter 重置指定域名的计数（可选功能）
 func (ds *DomainSelector) resetCounter(domain string) {
 	ds.mu.Lock()
@@ -117,6 +234,35 @@ func (ds *DomainSelector) resetCounter(domain string) {
 	delete(ds.counters, domain)
 }
 
+// disable 将域名标记为已禁用，返回该域名之前是否处于可用状态
+func (ds *DomainSelector) disable(domain string) bool {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if ds.disabled[domain] {
+		return false
+	}
+	ds.disabled[domain] = true
+	return true
+}
+
+// enable 取消域名的禁用标记
+func (ds *DomainSelector) enable(domain string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	delete(ds.disabled, domain)
+}
+
+// disabledDomains 返回已禁用的域名列表
+func (ds *DomainSelector) disabledDomains() []string {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	domains := make([]string, 0, len(ds.disabled))
+	for domain := range ds.disabled {
+		domains = append(domains, domain)
+	}
+	return domains
+}
+
 // getStats 获取域名使用统计（内部使用）
 func (ds *DomainSelector) getStats() map[string]int {
 	ds.mu.Lock()
@@ -131,7 +277,8 @@ func (ds *DomainSelector) getStats() map[string]int {
 
 // GetDomainStats 获取域名使用统计（导出函数）
 //
-// 返回每个域名的使用次数，用于验证轮询策略的有效性
+// 返回包级函数使用的默认选择器中每个域名的使用次数，用于验证轮询策略的有效性。
+// 客户端的统计见 Client.DomainSelector().Stats()。
 //
 // 示例:
 //   stats := mail2sdk.GetDomainStats()
@@ -139,25 +286,50 @@ func (ds *DomainSelector) getStats() map[string]int {
 //       fmt.Printf("%s: %d 次\n", domain, count)
 //   }
 func GetDomainStats() map[string]int {
-	return getDomainSelector().getStats()
+	return getDomainSelector().Stats()
 }
 
 // ResetDomainStats 重置所有域名的使用计数（导出函数）
 //
 // 用于清空计数器，重新开始计数
 func ResetDomainStats() {
-	ds := getDomainSelector()
-	ds.mu.Lock()
-	defer ds.mu.Unlock()
-	ds.counters = make(map[string]int)
+	getDomainSelector().Reset()
+}
+
+// DisabledDomains 返回被自动判定为已禁用的域名（导出函数）
+//
+// 创建邮箱时如果服务端返回"域名已禁用"，SDK 会把该域名加入禁用列表，
+// 之后的自动选择都会跳过它。只包含默认选择器的状态，客户端的状态见
+// Client.DomainSelector().Disabled()。
+func DisabledDomains() []string {
+	return getDomainSelector().Disabled()
+}
+
+// EnableDomain 将域名移出禁用列表，重新参与自动选择（导出函数）
+func EnableDomain(domain string) {
+	getDomainSelector().Enable(domain)
+}
+
+// CoolDomain 让域名进入冷却期（导出函数）
+//
+// window 内自动选择完全避开该域名，之后的 ramp 时间内逐步恢复选择概率。
+// 配置了 WithDomainCooldown 时，WaitForCode 超时后会对客户端的选择器自动调用。
+func CoolDomain(domain string, window, ramp time.Duration) {
+	getDomainSelector().Cool(domain, window, ramp)
+}
+
+// CoolingDomains 返回处于冷却期（包括恢复阶段）的域名及其冷却结束时间（导出函数）
+func CoolingDomains() map[string]time.Time {
+	return getDomainSelector().Cooling()
 }
 
 // 邮箱生成模式常量
 const (
-	ModeAuto    = 0 // 自动混用（SDK 随机选择 random/chinese/english）
-	ModeRandom  = 1 // 随机字符（如: bd4232）
-	ModeChinese = 2 // 中文拼音（如: liufeng802）
-	ModeEnglish = 3 // 英文名（如: lindaanderson）
+	ModeAuto    = 0  // 自动混用（SDK 随机选择 random/chinese/english）
+	ModeRandom  = 1  // 随机字符（如: bd4232）
+	ModeChinese = 2  // 中文拼音（如: liufeng802）
+	ModeEnglish = 3  // 英文名（如: lindaanderson）
+	ModeDefault = -1 // 使用客户端的默认模式（见 WithDefaultMode，未设置时等同 ModeAuto）
 )
 
 // Mailbox 表示一个临时邮箱
@@ -167,6 +339,11 @@ type Mailbox struct {
 	Domain    string    `json:"domain"`       // 域名
 	ExpiresAt time.Time `json:"expires_at"`   // 过期时间
 	CreatedAt time.Time `json:"created_at"`   // 创建时间
+
+	// 以下字段仅部分服务端版本返回（旧版本服务端为空字符串）
+	AccessToken          string    `json:"access_token,omitempty"`  // 邮箱级只读访问令牌（可配合 WithMailboxToken 使用）
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"` // 访问令牌过期时间（零值表示不过期，见 Session.EnableTokenRefresh）
+	WebURL               string    `json:"web_url,omitempty"`       // 可分享的网页收件箱地址
 }
 
 // Mail 表示邮件基本信息
@@ -175,6 +352,8 @@ type Mail struct {
 	From       string    `json:"from"`        // 发件人
 	Subject    string    `json:"subject"`     // 主题
 	ReceivedAt time.Time `json:"received_at"` // 接收时间
+	Folder     string    `json:"folder,omitempty"` // 所在文件夹（见 FolderInbox、FolderSpam，旧版本服务端为空）
+	Size       int64     `json:"size,omitempty"`   // 邮件大小（字节，旧版本服务端为 0）
 }
 
 // MailDetail 表示邮件完整详情
@@ -186,6 +365,18 @@ type MailDetail struct {
 	TextBody string    `json:"text_content"` // 纯文本内容（用户可自己写正则提取）
 	HTMLBody string    `json:"html_content"` // HTML 内容（用户可自己写正则提取）
 	ReceivedAt time.Time `json:"received_at"` // 接收时间
+	Attachments []Attachment `json:"attachments,omitempty"` // 附件信息（服务端未返回时为空）
+	Folder      string       `json:"folder,omitempty"`      // 所在文件夹（见 FolderInbox、FolderSpam，旧版本服务端为空）
+	Size        int64        `json:"size,omitempty"`        // 邮件原始大小（字节，旧版本服务端为 0）
+	Truncated   bool         `json:"truncated,omitempty"`   // 正文是否被服务端截断（见 GetMailDetailFull）
+}
+
+// Attachment 表示邮件附件的元数据
+type Attachment struct {
+	ID          string `json:"id,omitempty"` // 附件 ID（用于 DownloadAttachment，旧版本服务端为空）
+	Filename    string `json:"filename"`     // 文件名
+	ContentType string `json:"content_type"` // MIME 类型
+	Size        int64  `json:"size"`         // 大小（字节）
 }
 
 // CodeResult 表示验证码提取结果
@@ -195,6 +386,9 @@ type CodeResult struct {
 	AllCodes     []string `json:"all_codes"`      // 所有找到的验证码
 	CheckedMails int      `json:"checked_mails"`  // 检查的邮件数量
 	LatestMailID string   `json:"latest_mail_id"` // 最新邮件 ID
+
+	// Candidates 按置信度降序排列的候选验证码（仅 ExtractCodeRanked 填充）
+	Candidates []CodeCandidate `json:"candidates,omitempty"`
 }
 
 // apiResponse 表示 API 标准响应
@@ -204,65 +398,6 @@ type apiResponse struct {
 	Data json.RawMessage `json:"data"` // 响应数据
 }
 
-// doRequest 执行 HTTP 请求的内部辅助函数
-func doRequest(ctx context.Context, baseURL, apiKey, method, path string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("marshal request body failed: %w", err)
-		}
-		reqBody = bytes.NewReader(jsonData)
-	}
-
-	fullURL := baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
-	if err != nil {
-		return fmt.Errorf("create request failed: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", apiKey)
-	req.Header.Set("User-Agent", fmt.Sprintf("Mail2SDK-Go/%s", Version))
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read response failed: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("API error (status=%d): %s", resp.StatusCode, string(respBody))
-	}
-
-	if result == nil {
-		return nil
-	}
-
-	var apiResp apiResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return fmt.Errorf("parse response failed: %w", err)
-	}
-
-	if apiResp.Code != 0 && apiResp.Code != 200 {
-		return fmt.Errorf("API error (code=%d): %s", apiResp.Code, apiResp.Msg)
-	}
-
-	if len(apiResp.Data) > 0 {
-		if err := json.Unmarshal(apiResp.Data, result); err != nil {
-			return fmt.Errorf("parse data failed: %w", err)
-		}
-	}
-
-	return nil
-}
-
 // filterDomains 过滤黑名单域名
 //
 // 参数:
@@ -340,27 +475,19 @@ func indexSubstring(s, substr string) int {
 // 示例:
 //   domains, err := mail2sdk.GetDomains("https://mail.cwn.cc", "your-api-key")
 func GetDomains(baseURL, apiKey string) ([]string, error) {
-	ctx := context.Background()
-	
-	var result struct {
-		Records []struct {
-			Name    string `json:"name"`
-			Enabled bool   `json:"enabled"`
-		} `json:"records"`
-	}
-
-	if err := doRequest(ctx, baseURL, apiKey, "GET", "/api/domains", nil, &result); err != nil {
-		return nil, err
-	}
-
-	domains := make([]string, 0, len(result.Records))
-	for _, d := range result.Records {
-		if d.Enabled {
-			domains = append(domains, d.Name)
-		}
-	}
+	return GetDomainsCtx(context.Background(), baseURL, apiKey)
+}
 
-	return domains, nil
+// GetDomainsCtx 与 GetDomains 相同，但使用调用方传入的上下文
+//
+// ctx 被取消或超时后请求会立即返回 ctx.Err()。
+//
+// 示例:
+//   ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//   defer cancel()
+//   domains, err := mail2sdk.GetDomainsCtx(ctx, baseURL, apiKey)
+func GetDomainsCtx(ctx context.Context, baseURL, apiKey string) ([]string, error) {
+	return newDefaultClient(baseURL, apiKey).GetDomains(ctx)
 }
 
 // CreateMailbox 创建临时邮箱
@@ -387,56 +514,12 @@ func GetDomains(baseURL, apiKey string) ([]string, error) {
 //   blacklist := []string{"eu.org", "edu.kg"}
 //   mailbox, _ := mail2sdk.CreateMailbox(baseURL, apiKey, 0, "", blacklist)
 func CreateMailbox(baseURL, apiKey string, mode int, domain string, blacklist []string) (*Mailbox, error) {
-	ctx := context.Background()
-
-	// 处理模式
-	var apiMode string
-	switch mode {
-	case 0: // 自动混用
-		modes := []string{"random", "chinese", "english"}
-		apiMode = modes[getRand().Intn(3)]
-	case 1:
-		apiMode = "random"
-	case 2:
-		apiMode = "chinese"
-	case 3:
-		apiMode = "english"
-	default:
-		apiMode = "random"
-	}
-
-	// 如果没有指定域名但有黑名单，需要从可用域名中选择
-	if domain == "" && len(blacklist) > 0 {
-		allDomains, err := GetDomains(baseURL, apiKey)
-		if err != nil {
-			return nil, fmt.Errorf("获取域名列表失败: %w", err)
-		}
-
-		filtered := filterDomains(allDomains, blacklist)
-		if len(filtered) == 0 {
-			return nil, fmt.Errorf("黑名单过滤后没有可用域名")
-		}
-
-		// 使用轮询策略选择域名（确保所有域名均匀使用）
-		domain = getDomainSelector().selectDomain(filtered)
-	}
-
-	// 构建请求体
-	reqBody := map[string]interface{}{
-		"mode": apiMode,
-	}
-
-	// 如果指定了域名
-	if domain != "" {
-		reqBody["domain"] = domain
-	}
-
-	var mailbox Mailbox
-	if err := doRequest(ctx, baseURL, apiKey, "POST", "/api/mailbox", reqBody, &mailbox); err != nil {
-		return nil, err
-	}
+	return CreateMailboxCtx(context.Background(), baseURL, apiKey, mode, domain, blacklist)
+}
 
-	return &mailbox, nil
+// CreateMailboxCtx 与 CreateMailbox 相同，但使用调用方传入的上下文
+func CreateMailboxCtx(ctx context.Context, baseURL, apiKey string, mode int, domain string, blacklist []string) (*Mailbox, error) {
+	return newDefaultClient(baseURL, apiKey).CreateMailbox(ctx, mode, domain, blacklist)
 }
 
 // CreateMailboxWithDomains 从指定域名组中随机选择一个创建邮箱
@@ -460,20 +543,12 @@ func CreateMailbox(baseURL, apiKey string, mode int, domain string, blacklist []
 //   blacklist := []string{"eu.org"}
 //   mailbox, _ := mail2sdk.CreateMailboxWithDomains(baseURL, apiKey, 1, domains, blacklist)
 func CreateMailboxWithDomains(baseURL, apiKey string, mode int, domains []string, blacklist []string) (*Mailbox, error) {
-	if len(domains) == 0 {
-		return CreateMailbox(baseURL, apiKey, mode, "", blacklist)
-	}
-
-	// 过滤黑名单域名
-	filtered := filterDomains(domains, blacklist)
-	if len(filtered) == 0 {
-		return nil, fmt.Errorf("黑名单过滤后没有可用域名")
-	}
-
-	// 使用轮询策略选择域名（确保所有域名均匀使用）
-	domain := getDomainSelector().selectDomain(filtered)
+	return CreateMailboxWithDomainsCtx(context.Background(), baseURL, apiKey, mode, domains, blacklist)
+}
 
-	return CreateMailbox(baseURL, apiKey, mode, domain, nil)
+// CreateMailboxWithDomainsCtx 与 CreateMailboxWithDomains 相同，但使用调用方传入的上下文
+func CreateMailboxWithDomainsCtx(ctx context.Context, baseURL, apiKey string, mode int, domains []string, blacklist []string) (*Mailbox, error) {
+	return newDefaultClient(baseURL, apiKey).CreateMailboxWithDomains(ctx, mode, domains, blacklist)
 }
 
 // GetMails 获取邮箱的邮件列表
@@ -490,23 +565,12 @@ func CreateMailboxWithDomains(baseURL, apiKey string, mode int, domains []string
 // 示例:
 //   mails, err := mail2sdk.GetMails(baseURL, apiKey, "test@example.com")
 func GetMails(baseURL, apiKey, address string) ([]Mail, error) {
-	if address == "" {
-		return nil, fmt.Errorf("address is required")
-	}
-
-	ctx := context.Background()
-	path := fmt.Sprintf("/api/mailbox/%s/mails", url.PathEscape(address))
-
-	var result struct {
-		Count int    `json:"count"`
-		Mails []Mail `json:"mails"`
-	}
-
-	if err := doRequest(ctx, baseURL, apiKey, "GET", path, nil, &result); err != nil {
-		return nil, err
-	}
+	return GetMailsCtx(context.Background(), baseURL, apiKey, address)
+}
 
-	return result.Mails, nil
+// GetMailsCtx 与 GetMails 相同，但使用调用方传入的上下文
+func GetMailsCtx(ctx context.Context, baseURL, apiKey, address string, opts ...CallOption) ([]Mail, error) {
+	return newDefaultClient(baseURL, apiKey).GetMails(ctx, address, opts...)
 }
 
 // GetMailDetail 获取邮件的完整详情
@@ -531,22 +595,12 @@ func GetMails(baseURL, apiKey, address string) ([]Mail, error) {
 //   re := regexp.MustCompile(`https://[^\s"<>]+`)
 //   links := re.FindAllString(detail.HTMLBody, -1)
 func GetMailDetail(baseURL, apiKey, address, mailID string) (*MailDetail, error) {
-	if address == "" {
-		return nil, fmt.Errorf("address is required")
-	}
-	if mailID == "" {
-		return nil, fmt.Errorf("mailID is required")
-	}
-
-	ctx := context.Background()
-	path := fmt.Sprintf("/api/mailbox/%s/mails/%s", url.PathEscape(address), url.PathEscape(mailID))
-
-	var detail MailDetail
-	if err := doRequest(ctx, baseURL, apiKey, "GET", path, nil, &detail); err != nil {
-		return nil, err
-	}
+	return GetMailDetailCtx(context.Background(), baseURL, apiKey, address, mailID)
+}
 
-	return &detail, nil
+// GetMailDetailCtx 与 GetMailDetail 相同，但使用调用方传入的上下文
+func GetMailDetailCtx(ctx context.Context, baseURL, apiKey, address, mailID string) (*MailDetail, error) {
+	return newDefaultClient(baseURL, apiKey).GetMailDetail(ctx, address, mailID)
 }
 
 // ExtractCode 提取验证码（使用 API 内置算法）
@@ -569,23 +623,12 @@ func GetMailDetail(baseURL, apiKey, address, mailID string) (*MailDetail, error)
 //       fmt.Println("验证码:", result.Code)
 //   }
 func ExtractCode(baseURL, apiKey, address string, maxMails int) (*CodeResult, error) {
-	if address == "" {
-		return nil, fmt.Errorf("address is required")
-	}
-
-	ctx := context.Background()
-	path := fmt.Sprintf("/api/mailbox/%s/code", url.PathEscape(address))
-
-	if maxMails > 0 {
-		path += "?max_mails=" + strconv.Itoa(maxMails)
-	}
-
-	var result CodeResult
-	if err := doRequest(ctx, baseURL, apiKey, "GET", path, nil, &result); err != nil {
-		return nil, err
-	}
+	return ExtractCodeCtx(context.Background(), baseURL, apiKey, address, maxMails)
+}
 
-	return &result, nil
+// ExtractCodeCtx 与 ExtractCode 相同，但使用调用方传入的上下文
+func ExtractCodeCtx(ctx context.Context, baseURL, apiKey, address string, maxMails int) (*CodeResult, error) {
+	return newDefaultClient(baseURL, apiKey).ExtractCode(ctx, address, maxMails)
 }
 
 // DeleteMailbox 删除邮箱及其所有邮件
@@ -603,12 +646,10 @@ func ExtractCode(baseURL, apiKey, address string, maxMails int) (*CodeResult, er
 // 示例:
 //   err := mail2sdk.DeleteMailbox(baseURL, apiKey, "test@example.com")
 func DeleteMailbox(baseURL, apiKey, address string) error {
-	if address == "" {
-		return fmt.Errorf("address is required")
-	}
-
-	ctx := context.Background()
-	path := fmt.Sprintf("/api/mailbox/%s", url.PathEscape(address))
+	return DeleteMailboxCtx(context.Background(), baseURL, apiKey, address)
+}
 
-	return doRequest(ctx, baseURL, apiKey, "DELETE", path, nil, nil)
+// DeleteMailboxCtx 与 DeleteMailbox 相同，但使用调用方传入的上下文
+func DeleteMailboxCtx(ctx context.Context, baseURL, apiKey, address string) error {
+	return newDefaultClient(baseURL, apiKey).DeleteMailbox(ctx, address)
 }
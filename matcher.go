@@ -0,0 +1,203 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MailMatcher 邮件匹配条件
+//
+// 内置条件可以通过 All、Any、Not 自由组合。
+type MailMatcher interface {
+	Match(detail *MailDetail) bool
+}
+
+// MatcherFunc 将普通函数适配为 MailMatcher
+type MatcherFunc func(detail *MailDetail) bool
+
+// Match 实现 MailMatcher 接口
+func (f MatcherFunc) Match(detail *MailDetail) bool {
+	return f(detail)
+}
+
+// FromDomain 匹配发件人域名（不区分大小写，子域名也会匹配）
+//
+// 示例:
+//   mail2sdk.FromDomain("github.com") // 匹配 noreply@github.com、x@mail.github.com
+func FromDomain(domain string) MailMatcher {
+	domains := []string{strings.ToLower(strings.TrimPrefix(domain, "@"))}
+	return MatcherFunc(func(detail *MailDetail) bool {
+		from := senderAddress(detail.From)
+		at := strings.LastIndex(from, "@")
+		if at < 0 {
+			return false
+		}
+		return domainMatches(from[at+1:], domains)
+	})
+}
+
+// SubjectRegexp 匹配主题符合正则表达式的邮件
+func SubjectRegexp(re *regexp.Regexp) MailMatcher {
+	return MatcherFunc(func(detail *MailDetail) bool {
+		return re.MatchString(detail.Subject)
+	})
+}
+
+// BodyContains 匹配正文包含指定文本的邮件（不区分大小写）
+//
+// 纯文本正文为空时会检查 HTML 转换后的文本。
+func BodyContains(text string) MailMatcher {
+	text = strings.ToLower(text)
+	return MatcherFunc(func(detail *MailDetail) bool {
+		body := detail.TextBody
+		if strings.TrimSpace(body) == "" {
+			body = htmlToText(detail.HTMLBody)
+		}
+		return strings.Contains(strings.ToLower(body), text)
+	})
+}
+
+// HasAttachment 匹配带有附件的邮件
+func HasAttachment() MailMatcher {
+	return MatcherFunc(func(detail *MailDetail) bool {
+		return len(detail.Attachments) > 0
+	})
+}
+
+// All 所有条件都满足时匹配（没有条件时总是匹配）
+func All(matchers ...MailMatcher) MailMatcher {
+	return MatcherFunc(func(detail *MailDetail) bool {
+		for _, m := range matchers {
+			if !m.Match(detail) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Any 任一条件满足时匹配（没有条件时不匹配）
+func Any(matchers ...MailMatcher) MailMatcher {
+	return MatcherFunc(func(detail *MailDetail) bool {
+		for _, m := range matchers {
+			if m.Match(detail) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not 条件不满足时匹配
+func Not(matcher MailMatcher) MailMatcher {
+	return MatcherFunc(func(detail *MailDetail) bool {
+		return !matcher.Match(detail)
+	})
+}
+
+// WaitForMailMatching 等待第一封满足条件的邮件
+//
+// 按固定间隔（见 Client.PollInterval）轮询邮件列表，逐封读取详情并检查条件，已有的邮件也会参与匹配。
+// 单次轮询或读取详情失败时会在下一轮重试，直到 ctx 被取消或超时。
+// 配置了 WithNoiseFilter 时，噪音发件人的邮件不会被读取。
+//
+// 参数:
+//   ctx: 上下文（建议设置超时）
+//   address: 邮箱地址
+//   matcher: 匹配条件
+//
+// 返回:
+//   *MailDetail: 第一封匹配的邮件
+//   error: ctx 被取消时返回错误（包含最后一次请求错误）
+//
+// 示例:
+//   ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+//   defer cancel()
+//   detail, err := client.WaitForMailMatching(ctx, address, mail2sdk.All(
+//       mail2sdk.FromDomain("github.com"),
+//       mail2sdk.SubjectRegexp(regexp.MustCompile(`(?i)verify`)),
+//   ))
+func (c *Client) WaitForMailMatching(ctx context.Context, address string, matcher MailMatcher) (*MailDetail, error) {
+	return c.waitForMail(ctx, address, matcher, false)
+}
+
+// waitForMail 轮询邮件直到满足条件，includeSpam 为 true 时同时检查垃圾邮件文件夹
+func (c *Client) waitForMail(ctx context.Context, address string, matcher MailMatcher, includeSpam bool) (detail *MailDetail, err error) {
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	if matcher == nil {
+		return nil, fmt.Errorf("matcher is required")
+	}
+
+	// 整个等待过程（包括轮询间隔）计入耗时预算
+	ctx, finish, err := beginBudget(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = finish(err) }()
+
+	release, err := c.acquireWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, stop := c.bindLifetime(ctx)
+	defer stop()
+
+	checked := make(map[string]bool)
+	tally := c.senders.watch(address)
+	spamOff := false
+	var lastErr error
+	for {
+		mails, err := c.watchMails(ctx, address, WatchOptions{IncludeSpam: includeSpam}, &spamOff)
+		if stopPolling(err) {
+			return nil, err
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		for _, mail := range mails {
+			tally.observe(mail)
+			if checked[mail.ID] {
+				continue
+			}
+			if c.noiseFilter.isNoise(mail.From) {
+				checked[mail.ID] = true
+				continue
+			}
+
+			detail, err := c.detailForExtraction(ctx, address, mail.ID)
+			if stopPolling(err) {
+				return nil, err
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			checked[mail.ID] = true
+			if detail.Folder == "" {
+				detail.Folder = mail.Folder
+			}
+
+			if matcher.Match(detail) {
+				c.observeArrival(ctx, address, detail)
+				return detail, nil
+			}
+		}
+
+		if err := sleepContext(ctx, c.pollInterval(0)); err != nil {
+			if c.Closed() {
+				return nil, ErrClientClosed
+			}
+			if lastErr != nil {
+				return nil, fmt.Errorf("wait for mail failed: %w (last error: %v)", err, lastErr)
+			}
+			return nil, fmt.Errorf("wait for mail failed: %w", err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidNameTemplate 表示用户名模板无法解析或生成了不合法的用户名
+var ErrInvalidNameTemplate = errors.New("invalid mailbox name template")
+
+// 用户名最大长度
+const maxUsernameLength = 64
+
+// NameTemplate 邮箱用户名模板
+//
+// Pattern 中的占位符在创建邮箱时替换：
+//   {runID}  本次运行的标识（RunID，为空时自动生成 8 位随机十六进制）
+//   {seq}    该客户端创建邮箱的序号（从 1 开始）
+//   {rand}   6 位随机十六进制
+//   {date}   当前日期（如 20251231）
+//   {name}   Vars 中同名的值
+//
+// 替换后的用户名会转为小写，只允许字母、数字和 "."、"_"、"-"。
+type NameTemplate struct {
+	Pattern string            // 模板（如 "qa-{runID}-{seq}"）
+	RunID   string            // {runID} 的值
+	Vars    map[string]string // 自定义占位符的值
+}
+
+// nameTemplate 客户端持有的模板及其状态
+type nameTemplate struct {
+	NameTemplate
+	seq int64 // 已使用的序号
+}
+
+// WithNameTemplate 按模板生成邮箱用户名
+//
+// 服务端支持自定义用户名（FeatureCustomUsername）时，创建邮箱会按模板生成用户名，
+// 让邮箱在服务端界面中一眼可以看出来源，也方便在日志中检索；服务端不支持时仍由
+// 服务端按生成模式命名。模板不合法时创建邮箱返回 ErrInvalidNameTemplate。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithNameTemplate(mail2sdk.NameTemplate{
+//       Pattern: "qa-{runID}-{seq}",
+//       RunID:   os.Getenv("CI_PIPELINE_ID"),
+//   }))
+//   mailbox, _ := client.CreateMailbox(ctx, mail2sdk.ModeRandom, "", nil)
+//   // mailbox.Username 形如 "qa-81422-1"
+func WithNameTemplate(t NameTemplate) Option {
+	return func(c *Client) {
+		if t.RunID == "" {
+			t.RunID = fmt.Sprintf("%08x", randInt63n(1<<32))
+		}
+		c.names = &nameTemplate{NameTemplate: t}
+	}
+}
+
+// RunID 返回用户名模板使用的运行标识（未设置模板时为空字符串）
+func (c *Client) RunID() string {
+	if c.names == nil {
+		return ""
+	}
+	return c.names.RunID
+}
+
+// next 生成下一个用户名
+func (t *nameTemplate) next(now time.Time) (string, error) {
+	seq := atomic.AddInt64(&t.seq, 1)
+	return t.render(seq, now)
+}
+
+// render 替换模板中的占位符并校验结果
+func (t *nameTemplate) render(seq int64, now time.Time) (string, error) {
+	var b strings.Builder
+	rest := t.Pattern
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unclosed placeholder in %q", ErrInvalidNameTemplate, t.Pattern)
+		}
+		b.WriteString(rest[:open])
+
+		name := rest[open+1 : open+end]
+		switch name {
+		case "runID":
+			b.WriteString(t.RunID)
+		case "seq":
+			b.WriteString(strconv.FormatInt(seq, 10))
+		case "rand":
+			fmt.Fprintf(&b, "%06x", randIntn(1<<24))
+		case "date":
+			b.WriteString(now.Format("20060102"))
+		default:
+			value, ok := t.Vars[name]
+			if !ok {
+				return "", fmt.Errorf("%w: unknown placeholder {%s}", ErrInvalidNameTemplate, name)
+			}
+			b.WriteString(value)
+		}
+		rest = rest[open+end+1:]
+	}
+
+	username := strings.ToLower(b.String())
+	if username == "" || len(username) > maxUsernameLength {
+		return "", fmt.Errorf("%w: username %q must be 1-%d characters", ErrInvalidNameTemplate, username, maxUsernameLength)
+	}
+	for _, r := range username {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return "", fmt.Errorf("%w: username %q contains %q", ErrInvalidNameTemplate, username, r)
+		}
+	}
+	return username, nil
+}
+
+// templateUsername 按模板生成本次创建使用的用户名
+//
+// 未设置模板或服务端不支持自定义用户名时返回空字符串。
+func (c *Client) templateUsername(ctx context.Context) (string, error) {
+	if c.names == nil {
+		return "", nil
+	}
+	if ok, err := c.Supports(ctx, FeatureCustomUsername); err == nil && !ok {
+		return "", nil
+	}
+	return c.names.next(time.Now())
+}
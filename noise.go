@@ -0,0 +1,111 @@
+package mail2sdk
+
+import "strings"
+
+// DefaultNoiseSenders 默认的噪音发件人列表
+//
+// 公共临时邮箱域名经常收到退信通知、滥用投诉和营销邮件，这些邮件与自动化流程无关。
+// 规则格式：
+//   - "user@example.com": 完整地址
+//   - "user@": 任意域名下的该用户名
+//   - "example.com": 该域名及其子域名
+var DefaultNoiseSenders = []string{
+	"mailer-daemon@",
+	"postmaster@",
+	"abuse@",
+	"newsletter@",
+	"newsletters@",
+	"marketing@",
+	"mcsv.net",
+	"mcdlv.net",
+	"rsgsv.net",
+}
+
+// noiseFilter 噪音发件人过滤器
+type noiseFilter struct {
+	addresses map[string]bool // 完整地址
+	locals    map[string]bool // 用户名（不含域名）
+	domains   []string        // 域名
+}
+
+// newNoiseFilter 根据规则列表构建过滤器
+func newNoiseFilter(senders []string) *noiseFilter {
+	f := &noiseFilter{
+		addresses: make(map[string]bool),
+		locals:    make(map[string]bool),
+	}
+	for _, s := range senders {
+		s = strings.ToLower(strings.TrimSpace(s))
+		switch at := strings.LastIndex(s, "@"); {
+		case s == "":
+		case at < 0:
+			f.domains = append(f.domains, s)
+		case at == 0:
+			f.domains = append(f.domains, s[1:])
+		case at == len(s)-1:
+			f.locals[s[:at]] = true
+		default:
+			f.addresses[s] = true
+		}
+	}
+	return f
+}
+
+// isNoise 判断发件人是否属于噪音
+func (f *noiseFilter) isNoise(from string) bool {
+	if f == nil {
+		return false
+	}
+	addr := senderAddress(from)
+	at := strings.LastIndex(addr, "@")
+	if at < 0 {
+		return false
+	}
+	if f.addresses[addr] || f.locals[addr[:at]] {
+		return true
+	}
+	return domainMatches(addr[at+1:], f.domains)
+}
+
+// domainMatches 判断 host 是否为列表中的域名或其子域名
+func domainMatches(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// senderAddress 从发件人字段中提取小写邮箱地址
+//
+// 兼容 "Name <user@example.com>" 格式。
+func senderAddress(from string) string {
+	from = strings.ToLower(strings.TrimSpace(from))
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		from = strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+// WithNoiseFilter 过滤噪音发件人
+//
+// 设置后 Watch 和 WaitForMailMatching 会忽略来自这些发件人的邮件。
+// 不传参数时使用 DefaultNoiseSenders。
+//
+// 示例:
+//   // 使用默认列表
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithNoiseFilter())
+//
+//   // 在默认列表基础上追加
+//   senders := append([]string{"promo@shop.example"}, mail2sdk.DefaultNoiseSenders...)
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithNoiseFilter(senders...))
+func WithNoiseFilter(senders ...string) Option {
+	if len(senders) == 0 {
+		senders = DefaultNoiseSenders
+	}
+	filter := newNoiseFilter(senders)
+	return func(c *Client) {
+		c.noiseFilter = filter
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package mail2sdk_test
+
+const raceEnabled = false
@@ -0,0 +1,151 @@
+package mail2sdk
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 本地提取验证码时默认检查的邮件数量
+const defaultLocalCodeMails = 5
+
+// OCR 识别的图片大小上限
+const maxOCRImageSize = 5 << 20
+
+// OCRProvider 图片文字识别接口
+//
+// 部分服务商把验证码以图片形式发送。ExtractCodeLocal 在正文中找不到验证码、
+// 且邮件带有图片附件时，会调用 OCRProvider 识别图片中的文字。
+// 启用 tesseract 构建标签时，可以使用基于 Tesseract 命令行的 TesseractOCR。
+type OCRProvider interface {
+	// Recognize 识别图片中的文字，contentType 为图片的实际类型（如 "image/png"）
+	Recognize(ctx context.Context, image []byte, contentType string) (string, error)
+}
+
+// OCRFunc 将普通函数适配为 OCRProvider
+type OCRFunc func(ctx context.Context, image []byte, contentType string) (string, error)
+
+// Recognize 实现 OCRProvider 接口
+func (f OCRFunc) Recognize(ctx context.Context, image []byte, contentType string) (string, error) {
+	return f(ctx, image, contentType)
+}
+
+// WithOCRProvider 设置图片验证码的文字识别
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithOCRProvider(mail2sdk.OCRFunc(
+//       func(ctx context.Context, image []byte, contentType string) (string, error) {
+//           return myOCRService.Recognize(ctx, image)
+//       },
+//   )))
+func WithOCRProvider(provider OCRProvider) Option {
+	return func(c *Client) {
+		c.ocr = provider
+	}
+}
+
+// ExtractCodeLocal 在本地提取验证码
+//
+// 与 ExtractCode 不同，该方法获取邮件详情后在本地查找并评分（见 RankCodes），
+// 结果包含 Candidates。某封邮件正文中没有候选验证码、带有图片附件且设置了
+// OCRProvider 时，会下载图片附件并识别其中的文字。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   maxMails: 最多检查的邮件数量（<= 0 表示 5 封）
+//
+// 返回:
+//   *CodeResult: 提取结果（未找到时 Found 为 false）
+//   error: 错误信息（单张图片识别失败不会导致返回错误）
+func (c *Client) ExtractCodeLocal(ctx context.Context, address string, maxMails int) (*CodeResult, error) {
+	if maxMails <= 0 {
+		maxMails = defaultLocalCodeMails
+	}
+
+	mails, err := c.GetMails(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(mails, func(i, j int) bool {
+		return mails[i].ReceivedAt.After(mails[j].ReceivedAt)
+	})
+	if len(mails) > maxMails {
+		mails = mails[:maxMails]
+	}
+
+	result := &CodeResult{CheckedMails: len(mails)}
+	if len(mails) > 0 {
+		result.LatestMailID = mails[0].ID
+	}
+
+	// 用最新一封含验证码的邮件文本评分
+	rankText := ""
+	for _, mail := range mails {
+		detail, err := c.detailForExtraction(ctx, address, mail.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		codes, text := c.mailCodes(ctx, address, detail, nil)
+		if len(codes) > 0 && rankText == "" {
+			rankText = text
+		}
+		result.AllCodes = append(result.AllCodes, codes...)
+	}
+
+	result.Candidates = RankCodes(result.AllCodes, rankText)
+	if len(result.Candidates) > 0 {
+		result.Code = result.Candidates[0].Code
+		result.Found = true
+	}
+	return result, nil
+}
+
+// mailCodes 查找邮件中的候选验证码，返回候选和用于评分的文本
+//
+// 正文中没有候选且设置了 OCRProvider 时，识别图片附件中的文字。re 不为 nil 时
+// 使用它代替内置规则查找候选（见 WaitCodeOptions.CodePattern）。
+func (c *Client) mailCodes(ctx context.Context, address string, detail *MailDetail, re *regexp.Regexp) ([]string, string) {
+	find := findCodeCandidates
+	if re != nil {
+		find = func(text string) []string { return patternCodes(re, text) }
+	}
+
+	text := mailText(detail)
+	codes := find(text)
+	if len(codes) == 0 && c.ocr != nil {
+		if ocrText := c.recognizeImages(ctx, address, detail); ocrText != "" {
+			text += "\n" + ocrText
+			codes = find(ocrText)
+		}
+	}
+	return codes, text
+}
+
+// recognizeImages 识别邮件中所有图片附件的文字
+func (c *Client) recognizeImages(ctx context.Context, address string, detail *MailDetail) string {
+	var texts []string
+	for _, att := range detail.Attachments {
+		if att.ID == "" || !strings.HasPrefix(mediaType(att.ContentType), "image/") {
+			continue
+		}
+
+		data, err := c.DownloadAttachment(ctx, address, detail.ID, att, maxOCRImageSize)
+		if err != nil {
+			continue
+		}
+		// 以实际类型为准，避免把伪装成图片的文件交给 OCR
+		contentType := SniffContentType(data)
+		if !strings.HasPrefix(contentType, "image/") {
+			continue
+		}
+
+		text, err := c.ocr.Recognize(ctx, data, contentType)
+		if err == nil && strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,49 @@
+//go:build tesseract
+
+package mail2sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TesseractOCR 基于 Tesseract 命令行的 OCRProvider 示例实现
+//
+// 需要使用 tesseract 构建标签编译，并在 PATH 中安装 tesseract（4.0 及以上）：
+//
+//   go build -tags tesseract ./...
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithOCRProvider(&mail2sdk.TesseractOCR{}))
+//   result, err := client.ExtractCodeLocal(ctx, address, 3)
+type TesseractOCR struct {
+	Path string // tesseract 可执行文件路径（为空表示 "tesseract"）
+	Lang string // 识别语言（为空表示 "eng"，中文可使用 "chi_sim+eng"）
+}
+
+// Recognize 实现 OCRProvider 接口
+func (t *TesseractOCR) Recognize(ctx context.Context, image []byte, contentType string) (string, error) {
+	path := t.Path
+	if path == "" {
+		path = "tesseract"
+	}
+	lang := t.Lang
+	if lang == "" {
+		lang = "eng"
+	}
+
+	// 从标准输入读取图片，识别结果写到标准输出
+	cmd := exec.CommandContext(ctx, path, "stdin", "stdout", "-l", lang)
+	cmd.Stdin = bytes.NewReader(image)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
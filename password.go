@@ -0,0 +1,81 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetMailboxPassword 为邮箱设置访问密码
+//
+// 设置密码后，可以把邮箱地址和密码交给外部测试人员通过网页收件箱查看邮件，
+// 而不暴露账号下的其他任何资源。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   password: 新密码
+//
+// 返回:
+//   error: 错误信息
+//
+// 示例:
+//   err := client.SetMailboxPassword(ctx, mailbox.Address, "s3cret-for-tester")
+func (c *Client) SetMailboxPassword(ctx context.Context, address, password string) error {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("password is required")
+	}
+
+	if err := c.requireFeature(ctx, FeatureMailboxPassword); err != nil {
+		return err
+	}
+
+	path := "/api/mailbox/" + escaped + "/password"
+	reqBody := map[string]interface{}{
+		"password": password,
+	}
+
+	return c.do(ctx, "PUT", path, reqBody, nil)
+}
+
+// RotateMailboxPassword 由服务端为邮箱生成新的随机访问密码
+//
+// 旧密码立即失效，常用于测试结束后收回外部人员的访问权限。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//
+// 返回:
+//   string: 新密码
+//   error: 错误信息
+//
+// 示例:
+//   newPassword, err := client.RotateMailboxPassword(ctx, mailbox.Address)
+func (c *Client) RotateMailboxPassword(ctx context.Context, address string) (string, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.requireFeature(ctx, FeatureMailboxPassword); err != nil {
+		return "", err
+	}
+
+	path := "/api/mailbox/" + escaped + "/password/rotate"
+
+	var result struct {
+		Password string `json:"password"`
+	}
+	if err := c.do(ctx, "POST", path, nil, &result); err != nil {
+		return "", err
+	}
+
+	if result.Password == "" {
+		return "", fmt.Errorf("server returned empty password")
+	}
+	return result.Password, nil
+}
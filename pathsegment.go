@@ -0,0 +1,51 @@
+package mail2sdk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// 路径参数的最大长度（邮箱地址最长 254 字节，邮件 ID 通常远小于该值）
+const maxPathSegmentLen = 512
+
+// escapePathSegment 校验并转义 URL 路径参数
+//
+// 所有拼接到接口路径中的参数（邮箱地址、邮件 ID 等）都必须经过该函数。
+// 除 PathEscape 外还会拒绝以下取值，避免被入侵的服务端返回恶意邮件 ID 时
+// 构造出意料之外的请求：
+//   - 空字符串、"." 和 ".."
+//   - 包含 "/"、"\"、控制字符或非法 UTF-8
+//   - 超过 512 字节
+//
+// name 为参数名，用于错误信息（如 "address is required"）。
+func escapePathSegment(name, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	if err := validatePathSegment(name, value); err != nil {
+		return "", err
+	}
+	return url.PathEscape(value), nil
+}
+
+// validatePathSegment 校验路径参数（不包括空值检查）
+func validatePathSegment(name, value string) error {
+	if len(value) > maxPathSegmentLen {
+		return fmt.Errorf("%s is too long (%d bytes)", name, len(value))
+	}
+	if value == "." || value == ".." {
+		return fmt.Errorf("%s is not a valid path segment: %q", name, value)
+	}
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("%s contains invalid UTF-8", name)
+	}
+	if i := strings.IndexFunc(value, func(r rune) bool {
+		return r == '/' || r == '\\' || r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0)
+	}); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(value[i:])
+		return fmt.Errorf("%s contains invalid character %q", name, r)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package mail2sdk
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// pathSegmentSeeds 路径参数的种子语料
+var pathSegmentSeeds = []string{
+	"user@example.com",
+	"mail-123",
+	"..",
+	".",
+	"../../admin",
+	"a/b",
+	`a\b`,
+	"%2e%2e",
+	"%2F",
+	"a?b=c",
+	"a#frag",
+	"a;b",
+	"用户@例子.中国",
+	"a\x00b",
+	"\x7f",
+	" ",
+	"\xff",
+}
+
+func FuzzEscapePathSegment(f *testing.F) {
+	for _, seed := range pathSegmentSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		escaped, err := escapePathSegment("value", value)
+		if err != nil {
+			return
+		}
+		if strings.ContainsAny(escaped, "/?#\\") {
+			t.Fatalf("escaped segment %q contains a separator", escaped)
+		}
+		if unescaped, err := url.PathUnescape(escaped); err != nil || unescaped != value {
+			t.Fatalf("escaped segment %q does not round-trip to %q (got %q, %v)", escaped, value, unescaped, err)
+		}
+
+		u, err := url.Parse("https://example.com/api/mailbox/" + escaped + "/mails")
+		if err != nil {
+			t.Fatalf("parse URL with segment %q failed: %v", escaped, err)
+		}
+		if u.Path != "/api/mailbox/"+value+"/mails" || u.RawQuery != "" || u.Fragment != "" {
+			t.Fatalf("segment %q changed the URL structure: path %q query %q fragment %q", value, u.Path, u.RawQuery, u.Fragment)
+		}
+	})
+}
+
+// safeBasePath 测试中使用的路径前缀只包含常见的路径字符，不含 "." 和 ".." 段
+var safeBasePath = regexp.MustCompile(`^[A-Za-z0-9_/-]*$`)
+
+func FuzzURL(f *testing.F) {
+	for _, seed := range pathSegmentSeeds {
+		f.Add("/mailapi/", seed)
+		f.Add("", seed)
+	}
+	f.Add("//a//b/", "user@example.com")
+	f.Fuzz(func(t *testing.T, basePath, value string) {
+		if !safeBasePath.MatchString(basePath) {
+			return
+		}
+		escaped, err := escapePathSegment("value", value)
+		if err != nil {
+			return
+		}
+
+		c := &Client{baseURL: normalizeBaseURL("https://example.com/root") + normalizeBasePath(basePath)}
+		raw := c.url("/api/mailbox/" + escaped + "/mails")
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("url %q does not parse: %v", raw, err)
+		}
+		if u.Host != "example.com" || u.RawQuery != "" || u.Fragment != "" {
+			t.Fatalf("url %q changed host, query or fragment", raw)
+		}
+
+		prefix := strings.TrimPrefix(c.baseURL, "https://example.com")
+		if !strings.HasPrefix(u.Path, prefix+"/") {
+			t.Fatalf("path %q escapes the base path %q", u.Path, prefix)
+		}
+		rest := strings.TrimPrefix(u.EscapedPath(), prefix)
+		if segments := strings.Split(rest, "/"); len(segments) != 5 || segments[3] != escaped {
+			t.Fatalf("path %q has unexpected segments %q", u.EscapedPath(), segments)
+		}
+		if u.Path != prefix+"/api/mailbox/"+value+"/mails" {
+			t.Fatalf("path %q does not round-trip to segment %q", u.Path, value)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package mail2sdk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownPattern 表示引用的正则名称没有通过 RegisterPattern 注册
+var ErrUnknownPattern = errors.New("pattern not registered")
+
+// patternRegistry 按名称注册的预编译正则（见 RegisterPattern）
+type patternRegistry struct {
+	mu       sync.RWMutex
+	patterns map[string]*regexp.Regexp
+}
+
+// RegisterPattern 编译正则表达式并以 name 注册，之后在提取配置中按名称引用
+//
+// 正则只在注册时编译一次，轮询时不再重复调用 regexp.MustCompile；团队共用的正则
+// 可以在创建客户端时集中注册，各处流程只引用名称。同名注册会替换之前的正则，
+// 已经开始的提取继续使用替换前的正则。可以并发调用。
+//
+// 参数:
+//   name: 名称（非空）
+//   expr: 正则表达式（语法见 regexp 包）
+//
+// 返回:
+//   error: 名称为空或正则无效时返回错误
+//
+// 示例:
+//   client.RegisterPattern("github-code", `(?i)verification code[^0-9]*([0-9]{6})`)
+//
+//   result, err := client.WaitForCode(ctx, address, &mail2sdk.WaitCodeOptions{CodePattern: "github-code"})
+//   fields, err := client.PatternExtractor("github-code").Extract(detail)
+func (c *Client) RegisterPattern(name, expr string) error {
+	if name == "" {
+		return fmt.Errorf("pattern name is required")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("pattern %q: %w", name, err)
+	}
+
+	c.patterns.mu.Lock()
+	defer c.patterns.mu.Unlock()
+	if c.patterns.patterns == nil {
+		c.patterns.patterns = make(map[string]*regexp.Regexp)
+	}
+	c.patterns.patterns[name] = re
+	return nil
+}
+
+// Pattern 返回以 name 注册的正则（未注册时返回 ErrUnknownPattern）
+func (c *Client) Pattern(name string) (*regexp.Regexp, error) {
+	c.patterns.mu.RLock()
+	re := c.patterns.patterns[name]
+	c.patterns.mu.RUnlock()
+	if re == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPattern, name)
+	}
+	return re, nil
+}
+
+// Patterns 返回已注册的正则名称（按名称排序）
+func (c *Client) Patterns() []string {
+	c.patterns.mu.RLock()
+	defer c.patterns.mu.RUnlock()
+	names := make([]string, 0, len(c.patterns.patterns))
+	for name := range c.patterns.patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PatternExtractor 使用以 name 注册的正则提取字段（规则同 RegexExtractor，字段名为 name）
+//
+// 每次提取时按名称查找正则，重新注册后立即生效；提取时 name 仍未注册则返回 ErrUnknownPattern。
+func (c *Client) PatternExtractor(name string) Extractor {
+	return ExtractorFunc(func(detail *MailDetail) (map[string]string, error) {
+		re, err := c.Pattern(name)
+		if err != nil {
+			return nil, err
+		}
+		return RegexExtractor(name, re).Extract(detail)
+	})
+}
+
+// patternCodes 使用自定义正则查找候选验证码
+//
+// 正则包含名为 code 的分组时取该分组，否则有分组时取第一个分组，没有分组时取整个匹配。
+func patternCodes(re *regexp.Regexp, text string) []string {
+	group := re.SubexpIndex("code")
+	if group < 0 && re.NumSubexp() > 0 {
+		group = 1
+	}
+	if group < 0 {
+		group = 0
+	}
+
+	var codes []string
+	for _, m := range re.FindAllStringSubmatch(text, -1) {
+		if m[group] != "" {
+			codes = append(codes, m[group])
+		}
+	}
+	return codes
+}
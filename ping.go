@@ -0,0 +1,43 @@
+package mail2sdk
+
+import (
+	"context"
+	"time"
+)
+
+// PingResult 连通性检查的结果
+type PingResult struct {
+	Latency time.Duration // 检查请求的往返耗时
+	Version string        // 服务端版本（旧版本服务端为空）
+}
+
+// Ping 检查服务端是否可达、API 密钥是否有效
+//
+// 发送一次需要认证的轻量请求（获取域名列表，不解析响应），并返回耗时和服务端版本。
+// 密钥无效时返回状态码为 401/403 的 APIError。服务端版本来自缓存的 ServerInfo，
+// 首次调用会额外请求一次 /api/version，不计入 Latency。
+//
+// 返回:
+//   *PingResult: 耗时和服务端版本
+//   error: 服务端不可达或认证失败时的错误
+//
+// 示例:
+//   result, err := client.Ping(ctx)
+//   if err != nil {
+//       log.Fatalf("无法连接邮箱服务: %v", err)
+//   }
+//   log.Printf("服务端 %s，延迟 %s", result.Version, result.Latency)
+func (c *Client) Ping(ctx context.Context) (*PingResult, error) {
+	start := time.Now()
+	if err := c.do(ctx, "GET", "/api/domains", nil, nil); err != nil {
+		return nil, err
+	}
+	result := &PingResult{Latency: time.Since(start)}
+
+	info, err := c.ServerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Version = info.Version
+	return result, nil
+}
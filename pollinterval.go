@@ -0,0 +1,68 @@
+package mail2sdk
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 服务端建议的轮询间隔上限，防止异常的响应头让轮询几乎停止
+const maxServerPollInterval = 5 * time.Minute
+
+// PollInterval 返回轮询类方法默认使用的间隔
+//
+// 服务端通过 X-Poll-Interval 响应头或 /api/version 的 poll_interval 字段给出建议间隔后，
+// 返回该间隔；否则返回默认的 3 秒。
+func (c *Client) PollInterval() time.Duration {
+	return c.pollInterval(0)
+}
+
+// pollInterval 计算实际使用的轮询间隔
+//
+// requested <= 0 表示使用默认值（有服务端建议时使用建议间隔）。调用方指定的间隔比
+// 服务端建议的更短时按服务端建议执行，保证轮询频率不超出运营方预期的负载范围。
+func (c *Client) pollInterval(requested time.Duration) time.Duration {
+	hint := time.Duration(atomic.LoadInt64(&c.pollHint))
+	switch {
+	case requested <= 0 && hint > 0:
+		return hint
+	case requested <= 0:
+		return defaultPollInterval
+	case hint > requested:
+		return hint
+	default:
+		return requested
+	}
+}
+
+// observePollInterval 记录响应头中服务端建议的轮询间隔
+func (c *Client) observePollInterval(header http.Header) {
+	if value := header.Get("X-Poll-Interval"); value != "" {
+		c.setPollHint(parsePollInterval(value))
+	}
+}
+
+// setPollHint 更新服务端建议的轮询间隔（<= 0 表示无效值，忽略）
+func (c *Client) setPollHint(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if d > maxServerPollInterval {
+		d = maxServerPollInterval
+	}
+	atomic.StoreInt64(&c.pollHint, int64(d))
+}
+
+// parsePollInterval 解析轮询间隔，支持秒数（如 "5"、"1.5"）和时长（如 "5s"）
+func parsePollInterval(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	return 0
+}
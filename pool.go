@@ -0,0 +1,289 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed 表示邮箱池已关闭
+var ErrPoolClosed = errors.New("mailbox pool closed")
+
+// PoolOptions 邮箱池配置
+type PoolOptions struct {
+	Mode      int      // 邮箱生成模式
+	Domains   []string // 候选域名（为空时由服务端选择）
+	Blacklist []string // 域名黑名单
+
+	// MinTTL 取出邮箱时要求的最短剩余有效期，剩余时间不足的空闲邮箱会被丢弃并尽量删除
+	// （<= 0 表示只丢弃已过期的邮箱）
+	MinTTL time.Duration
+
+	// Codec Save/Restore 使用的编码（nil 表示 JSON）
+	Codec Codec
+}
+
+// Pool 预先创建的邮箱池
+//
+// 批量任务可以先用 Fill 预热，再通过 Get 取出邮箱、Put 归还邮箱，减少任务
+// 执行期间的创建请求。空闲邮箱数量会计入 CanCreate 的库存。取出后不再归还的邮箱
+// （如已经删除）应调用 Discard，否则会一直被视为正在使用（见 Inventory 和自动清理）。
+// Pool 可以在多个 goroutine 中共享。
+//
+// 示例:
+//   pool := client.NewPool(mail2sdk.PoolOptions{Mode: mail2sdk.ModeRandom})
+//   defer pool.Close(context.Background())
+//
+//   pool.Fill(ctx, 20)
+//   mailbox, err := pool.Get(ctx)
+type Pool struct {
+	client *Client
+	opts   PoolOptions
+
+	mu     sync.Mutex
+	idle   []*Mailbox
+	out    map[*Mailbox]struct{} // 已取出尚未归还的邮箱
+	closed bool
+}
+
+// NewPool 创建邮箱池（不会立即创建邮箱）
+func (c *Client) NewPool(opts PoolOptions) *Pool {
+	p := &Pool{client: c, opts: opts}
+
+	c.poolsMu.Lock()
+	if c.pools == nil {
+		c.pools = make(map[*Pool]struct{})
+	}
+	c.pools[p] = struct{}{}
+	c.poolsMu.Unlock()
+
+	return p
+}
+
+// Client 返回邮箱池使用的客户端
+func (p *Pool) Client() *Client {
+	return p.client
+}
+
+// Fill 创建邮箱直到空闲邮箱数量达到 n
+func (p *Pool) Fill(ctx context.Context, n int) error {
+	for p.Len() < n {
+		mailbox, err := p.create(ctx)
+		if err != nil {
+			return err
+		}
+		if err := p.Put(mailbox); err != nil {
+			p.deleteDropped(ctx, []*Mailbox{mailbox})
+			return err
+		}
+	}
+	return nil
+}
+
+// Get 取出一个空闲邮箱，没有空闲邮箱时创建新邮箱
+func (p *Pool) Get(ctx context.Context) (*Mailbox, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	var dropped []*Mailbox
+	for len(p.idle) > 0 {
+		mailbox := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if p.usable(mailbox, time.Now()) {
+			p.checkOut(mailbox)
+			p.mu.Unlock()
+			p.deleteDropped(ctx, dropped)
+			return mailbox, nil
+		}
+		dropped = append(dropped, mailbox)
+	}
+	p.mu.Unlock()
+	p.deleteDropped(ctx, dropped)
+
+	mailbox, err := p.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.checkOut(mailbox)
+	p.mu.Unlock()
+	return mailbox, nil
+}
+
+// Discard 停止跟踪取出后不再归还的邮箱（不会删除邮箱）
+func (p *Pool) Discard(mailbox *Mailbox) {
+	p.mu.Lock()
+	delete(p.out, mailbox)
+	p.mu.Unlock()
+}
+
+// checkOut 记录取出的邮箱（调用方持有 mu）
+func (p *Pool) checkOut(mailbox *Mailbox) {
+	if p.out == nil {
+		p.out = make(map[*Mailbox]struct{})
+	}
+	p.out[mailbox] = struct{}{}
+}
+
+// Put 将邮箱归还到池中
+//
+// 剩余有效期不足的邮箱不会放回池中，而是尽量删除。邮箱池已关闭时返回 ErrPoolClosed，
+// 邮箱仍由调用方负责删除。
+func (p *Pool) Put(mailbox *Mailbox) error {
+	p.mu.Lock()
+	delete(p.out, mailbox)
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	if p.usable(mailbox, time.Now()) {
+		p.idle = append(p.idle, mailbox)
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+	p.deleteDropped(context.Background(), []*Mailbox{mailbox})
+	return nil
+}
+
+// Len 返回当前空闲邮箱数量
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle)
+}
+
+// Close 关闭邮箱池并删除所有空闲邮箱
+//
+// 已经取出的邮箱由调用方负责删除。返回第一个删除失败的错误。
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	p.client.poolsMu.Lock()
+	delete(p.client.pools, p)
+	p.client.poolsMu.Unlock()
+
+	var firstErr error
+	for _, mailbox := range idle {
+		if err := p.client.DeleteMailbox(ctx, mailbox.Address); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Save 把当前的空闲邮箱写入 store 的 key 下
+//
+// 进程重启后可以用 Restore 恢复，继续使用尚未过期的邮箱。空闲邮箱包含邮箱地址和
+// 访问令牌，在共享机器上应配合 EncryptedStore 加密保存。
+//
+// 示例:
+//   files, _ := mail2sdk.NewFileStore(".mail2-state")
+//   store, _ := mail2sdk.NewEncryptedStore(files, key)
+//   pool.Save(ctx, store, "pool/signup")
+func (p *Pool) Save(ctx context.Context, store Store, key string) error {
+	p.mu.Lock()
+	data, err := codecOrJSON(p.opts.Codec).Marshal(p.idle)
+	p.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode pool state failed: %w", err)
+	}
+	return store.Put(ctx, key, data)
+}
+
+// Restore 从 store 的 key 下恢复 Save 保存的空闲邮箱
+//
+// 剩余有效期不足的邮箱会被丢弃并尽量删除，返回实际恢复的数量。key 不存在时返回 0。
+// PoolOptions.Codec 需要与保存时一致。
+func (p *Pool) Restore(ctx context.Context, store Store, key string) (int, error) {
+	data, err := store.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var saved []*Mailbox
+	if err := codecOrJSON(p.opts.Codec).Unmarshal(data, &saved); err != nil {
+		return 0, fmt.Errorf("decode pool state failed: %w", err)
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return 0, ErrPoolClosed
+	}
+	restored := 0
+	now := time.Now()
+	var dropped []*Mailbox
+	for _, mailbox := range saved {
+		if p.usable(mailbox, now) {
+			p.idle = append(p.idle, mailbox)
+			restored++
+		} else if mailbox != nil {
+			dropped = append(dropped, mailbox)
+		}
+	}
+	p.mu.Unlock()
+
+	p.deleteDropped(ctx, dropped)
+	return restored, nil
+}
+
+// create 按池的配置创建邮箱
+func (p *Pool) create(ctx context.Context) (*Mailbox, error) {
+	return p.client.CreateMailboxWithDomains(ctx, p.opts.Mode, p.opts.Domains, p.opts.Blacklist)
+}
+
+// deleteDropped 尽量删除被丢弃的邮箱（已过期的邮箱由服务端清理，不再发送请求），失败时只记录日志
+func (p *Pool) deleteDropped(ctx context.Context, mailboxes []*Mailbox) {
+	now := time.Now()
+	for _, mailbox := range mailboxes {
+		if !mailbox.ExpiresAt.IsZero() && !mailbox.ExpiresAt.After(now) {
+			continue
+		}
+		if err := p.client.DeleteMailbox(ctx, mailbox.Address); err != nil {
+			p.client.log(ctx, LogEntry{Level: LogWarn, Message: "delete discarded pool mailbox failed", Err: err})
+		}
+	}
+}
+
+// usable 判断邮箱的剩余有效期是否满足要求（未返回过期时间的邮箱视为可用）
+func (p *Pool) usable(mailbox *Mailbox, now time.Time) bool {
+	if mailbox == nil {
+		return false
+	}
+	if mailbox.ExpiresAt.IsZero() {
+		return true
+	}
+	return mailbox.ExpiresAt.Sub(now) > p.opts.MinTTL
+}
+
+// poolInventory 返回该客户端所有邮箱池的空闲邮箱总数
+func (c *Client) poolInventory() int {
+	c.poolsMu.Lock()
+	pools := make([]*Pool, 0, len(c.pools))
+	for p := range c.pools {
+		pools = append(pools, p)
+	}
+	c.poolsMu.Unlock()
+
+	total := 0
+	for _, p := range pools {
+		total += p.Len()
+	}
+	return total
+}
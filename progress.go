@@ -0,0 +1,75 @@
+package mail2sdk
+
+import "sync"
+
+// Progress 批量操作的进度
+type Progress struct {
+	Done   int    // 已完成的条目数（包括失败的）
+	Total  int    // 条目总数
+	Errors int    // 失败的条目数
+	Item   string // 刚完成的条目（如邮箱地址）
+	Err    error  // 该条目的错误（成功时为 nil）
+}
+
+// ProgressFunc 进度回调
+//
+// 批量操作每完成一个条目调用一次。回调在持有内部锁时同步执行，保证 Done 按顺序递增，
+// 因此应尽快返回，且不能在回调中再次调用同一个批量操作。
+type ProgressFunc func(Progress)
+
+// ProgressChan 返回把进度写入通道的回调
+//
+// 写入是阻塞的：调用方需要持续读取 ch，否则批量操作会暂停等待。通道不会被关闭，
+// 批量操作返回即表示不会再有新的进度。
+//
+// 示例:
+//   ch := make(chan mail2sdk.Progress, 16)
+//   go func() {
+//       for p := range ch {
+//           fmt.Printf("\r%d/%d（失败 %d）", p.Done, p.Total, p.Errors)
+//       }
+//   }()
+//   report := client.DeleteMailboxes(ctx, addresses, &mail2sdk.PurgeOptions{OnProgress: mail2sdk.ProgressChan(ch)})
+//   close(ch)
+func ProgressChan(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		ch <- p
+	}
+}
+
+// ProgressTracker 并发安全的进度计数器
+//
+// 供在 SDK 之上实现自己的批量操作时复用，与内置批量操作的进度语义一致。
+type ProgressTracker struct {
+	mu       sync.Mutex
+	fn       ProgressFunc
+	progress Progress
+}
+
+// NewProgressTracker 创建进度计数器（fn 为 nil 时只计数）
+func NewProgressTracker(total int, fn ProgressFunc) *ProgressTracker {
+	return &ProgressTracker{fn: fn, progress: Progress{Total: total}}
+}
+
+// Step 记录一个条目完成并上报进度
+func (t *ProgressTracker) Step(item string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Done++
+	if err != nil {
+		t.progress.Errors++
+	}
+	t.progress.Item = item
+	t.progress.Err = err
+	if t.fn != nil {
+		t.fn(t.progress)
+	}
+}
+
+// Progress 返回当前进度
+func (t *ProgressTracker) Progress() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
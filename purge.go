@@ -0,0 +1,231 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeOptions 批量清理配置
+type PurgeOptions struct {
+	DryRun      bool // 为 true 时只列出将被删除的邮箱，不执行删除
+	Concurrency int  // 删除并发数（<= 0 表示 8）
+
+	// Progress 进度回调（可选），每处理完一个邮箱调用一次
+	//
+	// Deprecated: 使用 OnProgress，它包含 Progress 的全部信息。
+	Progress func(done, total int)
+
+	// OnProgress 详细进度回调（可选），额外包含失败数和刚处理完的邮箱，
+	// 可以配合 ProgressChan 使用
+	OnProgress ProgressFunc
+
+	// CleanupLocal 删除成功后清理本地数据的回调（可选），如删除归档文件；
+	// 邮件详情缓存会被自动清理
+	CleanupLocal func(ctx context.Context, address string) error
+}
+
+// PurgeReport 批量清理结果
+//
+// 内嵌的 BatchResult 记录每个邮箱的删除结果、重试次数和耗时（Value 为邮箱地址，
+// 试运行时为空）；Deleted 和 Failed 是按成功、失败整理后的同一份结果。
+type PurgeReport struct {
+	BatchResult[string]
+
+	DryRun  bool             // 是否为试运行
+	Matched []string         // 符合条件的邮箱
+	Deleted []string         // 已删除的邮箱
+	Failed  map[string]error // 删除失败的邮箱及原因
+
+	// Skipped 跳过的邮箱：服务端没有返回创建时间、无法判断是否满足条件，
+	// 或（RunCleanup）正在被本地会话、邮箱池使用
+	Skipped []string
+
+	// Impact 试运行时每个待删除邮箱的影响（与 Matched 顺序一致，非试运行时为空）
+	Impact []MailboxImpact
+}
+
+// MailboxImpact 删除一个邮箱的影响
+type MailboxImpact struct {
+	Address   string        // 邮箱地址
+	Mails     int           // 邮箱中的邮件数量（获取失败时为 -1）
+	ExpiresAt time.Time     // 过期时间（未知时为零值）
+	TTL       time.Duration // 剩余有效期（未知或已过期时为 0）
+	Err       error         // 获取邮件列表失败的错误
+}
+
+// PurgeAll 删除当前 API 密钥下所有创建时间早于 olderThan 的邮箱
+//
+// 用于活动结束或合规要求下的集中清理。删除成功后会清理该邮箱的本地缓存，
+// 并调用 CleanupLocal 清理调用方自己的本地数据（如归档）。逐页遍历全部邮箱，
+// 不受 ListMailboxes 的数量上限限制。olderThan > 0 时，服务端没有返回创建时间的
+// 邮箱不会被删除，记录在 PurgeReport.Skipped 中。
+//
+// 参数:
+//   ctx: 上下文
+//   olderThan: 只删除创建时间早于 now-olderThan 的邮箱（<= 0 表示全部）
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   *PurgeReport: 清理结果
+//   error: 获取邮箱列表失败时返回错误（单个邮箱的删除失败记录在报告中）
+//
+// 示例:
+//   // 先试运行查看影响范围
+//   report, _ := client.PurgeAll(ctx, 24*time.Hour, &mail2sdk.PurgeOptions{DryRun: true})
+//   fmt.Printf("将删除 %d 个邮箱\n", len(report.Matched))
+func (c *Client) PurgeAll(ctx context.Context, olderThan time.Duration, opts *PurgeOptions) (*PurgeReport, error) {
+	var o PurgeOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	report := &PurgeReport{DryRun: o.DryRun, Failed: make(map[string]error)}
+	known := make(map[string]Mailbox)
+	cutoff := time.Now().Add(-olderThan)
+	err := c.eachMailbox(ctx, func(mailbox Mailbox) {
+		if olderThan > 0 {
+			// 创建时间未知时无法判断是否过期，不删除
+			if mailbox.CreatedAt.IsZero() {
+				report.Skipped = append(report.Skipped, mailbox.Address)
+				return
+			}
+			if !mailbox.CreatedAt.Before(cutoff) {
+				return
+			}
+		}
+		report.Matched = append(report.Matched, mailbox.Address)
+		if o.DryRun {
+			known[mailbox.Address] = mailbox
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list mailboxes failed: %w", err)
+	}
+
+	if o.DryRun {
+		report.Impact = c.inspectMatched(ctx, report.Matched, known, o)
+	} else {
+		c.deleteMatched(ctx, report, o)
+	}
+	return report, nil
+}
+
+// DeleteMailboxes 并发删除一组邮箱
+//
+// 设置 opts.DryRun 时不执行删除，而是在报告的 Impact 中列出每个邮箱的邮件数量和
+// 剩余有效期，便于在执行不可逆的批量清理前先确认影响范围。试运行只发起读取请求，
+// 可以在只读客户端（WithReadOnly）上执行。重复的地址只会处理一次。
+//
+// 参数:
+//   ctx: 上下文
+//   addresses: 邮箱地址列表
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   *PurgeReport: 删除结果（单个邮箱的删除失败记录在 Failed 中）
+//
+// 示例:
+//   report := client.DeleteMailboxes(ctx, addresses, &mail2sdk.PurgeOptions{DryRun: true})
+//   for _, impact := range report.Impact {
+//       fmt.Printf("%s: %d 封邮件，剩余 %s\n", impact.Address, impact.Mails, impact.TTL)
+//   }
+//   report = client.DeleteMailboxes(ctx, addresses, nil) // 确认后执行删除
+func (c *Client) DeleteMailboxes(ctx context.Context, addresses []string, opts *PurgeOptions) *PurgeReport {
+	var o PurgeOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	report := &PurgeReport{
+		DryRun:  o.DryRun,
+		Matched: uniqueAddresses(addresses),
+		Failed:  make(map[string]error),
+	}
+
+	if !o.DryRun {
+		c.deleteMatched(ctx, report, o)
+		return report
+	}
+
+	// 过期时间只能从邮箱列表获取，服务端不支持时留空
+	wanted := make(map[string]bool, len(report.Matched))
+	for _, address := range report.Matched {
+		wanted[address] = true
+	}
+	known := make(map[string]Mailbox)
+	_ = c.eachMailbox(ctx, func(mailbox Mailbox) {
+		if wanted[mailbox.Address] {
+			known[mailbox.Address] = mailbox
+		}
+	})
+	report.Impact = c.inspectMatched(ctx, report.Matched, known, o)
+	return report
+}
+
+// inspectMatched 并发统计待删除邮箱的邮件数量和剩余有效期
+func (c *Client) inspectMatched(ctx context.Context, addresses []string, known map[string]Mailbox, o PurgeOptions) []MailboxImpact {
+	result := RunBatch(ctx, addresses, o.Concurrency, o.progressFunc(),
+		func(ctx context.Context, item *BatchItem[int]) error {
+			mails, err := c.GetMails(ctx, item.Key)
+			item.Value = len(mails)
+			return err
+		})
+
+	impact := make([]MailboxImpact, len(addresses))
+	now := time.Now()
+	for i, item := range result.Items {
+		impact[i] = MailboxImpact{Address: item.Key, Mails: item.Value, Err: item.Err}
+		if item.Err != nil {
+			impact[i].Mails = -1
+		}
+		if mailbox, ok := known[item.Key]; ok && !mailbox.ExpiresAt.IsZero() {
+			impact[i].ExpiresAt = mailbox.ExpiresAt
+			if ttl := mailbox.ExpiresAt.Sub(now); ttl > 0 {
+				impact[i].TTL = ttl
+			}
+		}
+	}
+	return impact
+}
+
+// progressFunc 合并 Progress 和 OnProgress 两个回调
+func (o PurgeOptions) progressFunc() ProgressFunc {
+	if o.Progress == nil && o.OnProgress == nil {
+		return nil
+	}
+	return func(p Progress) {
+		if o.Progress != nil {
+			o.Progress(p.Done, p.Total)
+		}
+		if o.OnProgress != nil {
+			o.OnProgress(p)
+		}
+	}
+}
+
+// deleteMatched 并发删除 report.Matched 中的邮箱，结果写入 report
+func (c *Client) deleteMatched(ctx context.Context, report *PurgeReport, o PurgeOptions) {
+	result := RunBatch(ctx, report.Matched, o.Concurrency, o.progressFunc(),
+		func(ctx context.Context, item *BatchItem[string]) error {
+			item.Value = item.Key
+			if err := c.DeleteMailbox(ctx, item.Key); err != nil {
+				return err
+			}
+			if o.CleanupLocal != nil {
+				if err := o.CleanupLocal(ctx, item.Key); err != nil {
+					return fmt.Errorf("cleanup local data failed: %w", err)
+				}
+			}
+			return nil
+		})
+
+	report.BatchResult = *result
+	for _, item := range result.Items {
+		if item.Err != nil {
+			report.Failed[item.Key] = item.Err
+		} else {
+			report.Deleted = append(report.Deleted, item.Key)
+		}
+	}
+}
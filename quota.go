@@ -0,0 +1,124 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Quota 当前 API 密钥的邮箱创建配额
+type Quota struct {
+	Limit     int       `json:"limit"`     // 配额总数
+	Used      int       `json:"used"`      // 已使用数量
+	Remaining int       `json:"remaining"` // 剩余可创建数量
+	ResetAt   time.Time `json:"reset_at"`  // 配额重置时间（服务端未返回时为零值）
+}
+
+// quotaTracker 创建邮箱时记录的最新剩余配额
+type quotaTracker struct {
+	mu        sync.Mutex
+	remaining int       // 最新剩余配额（-1 表示未知）
+	updatedAt time.Time // 记录时间
+}
+
+// Quota 获取当前 API 密钥的邮箱创建配额
+//
+// 服务端不支持时返回 ErrNotSupportedByServer。
+func (c *Client) Quota(ctx context.Context) (*Quota, error) {
+	if err := c.requireFeature(ctx, FeatureQuota); err != nil {
+		return nil, err
+	}
+
+	var quota Quota
+	if err := c.do(ctx, "GET", "/api/quota", nil, &quota); err != nil {
+		return nil, c.markUnsupportedOn404(FeatureQuota, err)
+	}
+
+	c.recordQuota(quota.Remaining)
+	return &quota, nil
+}
+
+// recordQuota 记录服务端返回的剩余配额
+func (c *Client) recordQuota(remaining int) {
+	if remaining < 0 {
+		return
+	}
+	c.quota.mu.Lock()
+	c.quota.remaining = remaining
+	c.quota.updatedAt = time.Now()
+	c.quota.mu.Unlock()
+}
+
+// lastQuota 返回最近记录的剩余配额（未知时返回 -1）
+func (c *Client) lastQuota() int {
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	if c.quota.updatedAt.IsZero() {
+		return -1
+	}
+	return c.quota.remaining
+}
+
+// Reason CanCreate 判断不能创建的原因
+type Reason string
+
+// CanCreate 判断原因常量
+const (
+	ReasonOK             Reason = ""                // 可以创建
+	ReasonQuotaExhausted Reason = "quota_exhausted" // 剩余配额不足
+	ReasonRateLimited    Reason = "rate_limited"    // 当前限流窗口内剩余请求数不足
+)
+
+// CanCreate 判断现在能否获得 n 个邮箱
+//
+// 依次考虑邮箱池中的空闲邮箱、剩余配额（优先从 /api/quota 获取，服务端不支持时
+// 使用最近一次创建邮箱响应中的 quota_remaining）和最近一次响应的限流状态。
+// 无法获知配额或限流信息时按不受限处理。
+//
+// 参数:
+//   ctx: 上下文
+//   n: 需要的邮箱数量
+//
+// 返回:
+//   bool: 是否可以开始
+//   Reason: 不能开始的原因（可以开始时为 ReasonOK）
+//   error: 获取配额失败时返回错误
+//
+// 示例:
+//   ok, reason, err := client.CanCreate(ctx, 500)
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+//   if !ok {
+//       log.Printf("暂不启动批量任务: %s", reason)
+//       return
+//   }
+func (c *Client) CanCreate(ctx context.Context, n int) (bool, Reason, error) {
+	need := n - c.poolInventory()
+	if need <= 0 {
+		return true, ReasonOK, nil
+	}
+
+	remaining := -1
+	quota, err := c.Quota(ctx)
+	switch {
+	case err == nil:
+		remaining = quota.Remaining
+	case errors.Is(err, ErrNotSupportedByServer):
+		remaining = c.lastQuota()
+	default:
+		return false, ReasonOK, err
+	}
+	if remaining >= 0 && remaining < need {
+		return false, ReasonQuotaExhausted, nil
+	}
+
+	limit := c.RateLimitState()
+	if limit.Remaining >= 0 && limit.Remaining < need &&
+		(limit.Reset.IsZero() || time.Now().Before(limit.Reset)) {
+		return false, ReasonRateLimited, nil
+	}
+
+	return true, ReasonOK, nil
+}
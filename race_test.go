@@ -0,0 +1,6 @@
+//go:build race
+
+package mail2sdk_test
+
+// raceEnabled 竞态检测会让 sync.Pool 随机丢弃对象，分配次数不再稳定
+const raceEnabled = true
@@ -0,0 +1,267 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrLowConfidence 表示最佳候选验证码的得分低于要求的最低置信度
+var ErrLowConfidence = errors.New("code confidence below threshold")
+
+// CodeCandidate 表示一个候选验证码及其置信度
+type CodeCandidate struct {
+	Code     string  `json:"code"`     // 候选验证码
+	Score    float64 `json:"score"`    // 综合得分（0-1，越高越可信）
+	Position int     `json:"position"` // 在邮件正文中首次独立出现的位置（-1 表示未在正文中找到）
+	Snippet  string  `json:"snippet"`  // 验证码前后约 80 个字符的上下文（用于日志排查误提取）
+}
+
+// 上下文片段在验证码前后各保留的字符数
+const snippetRadius = 80
+
+// 验证码附近常见的关键词（小写）
+var codeKeywords = []string{
+	"验证码", "校验码", "动态码", "确认码", "安全码", "激活码",
+	"verification", "verify", "code", "otp", "passcode", "pin", "one-time",
+}
+
+// codeKeywordPattern 在原文中匹配 codeKeywords（不区分大小写，英文关键词要求单词边界，
+// 避免 "barcode"、"spinning" 之类的误匹配）
+var codeKeywordPattern = keywordPattern(codeKeywords)
+
+// keywordPattern 将关键词列表编译为不区分大小写的正则
+func keywordPattern(keywords []string) *regexp.Regexp {
+	parts := make([]string, len(keywords))
+	for i, kw := range keywords {
+		parts[i] = regexp.QuoteMeta(kw)
+		if isASCIIAlnum(kw[0]) && isASCIIAlnum(kw[len(kw)-1]) {
+			parts[i] = `\b` + parts[i] + `\b`
+		}
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(parts, "|"))
+}
+
+// 关键词距离超过该值（字节）时不再加分
+const keywordWindow = 120
+
+// 各项评分权重
+const (
+	weightKeyword  = 0.4
+	weightLength   = 0.2
+	weightPosition = 0.2
+	weightRecency  = 0.2
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlIgnorePattern = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	spacePattern      = regexp.MustCompile(`[ \t\r\f\v]+`)
+)
+
+// htmlToText 将 HTML 粗略转换为纯文本（去除标签、脚本和样式）
+func htmlToText(s string) string {
+	s = htmlIgnorePattern.ReplaceAllString(s, " ")
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return spacePattern.ReplaceAllString(s, " ")
+}
+
+// mailText 返回用于分析的邮件文本（主题 + 正文，正文为空时使用 HTML 转换结果）
+func mailText(detail *MailDetail) string {
+	body := detail.TextBody
+	if strings.TrimSpace(body) == "" {
+		body = htmlToText(detail.HTMLBody)
+	}
+	return detail.Subject + "\n" + body
+}
+
+// RankCodes 对候选验证码进行评分并按得分从高到低排序
+//
+// 评分综合以下因素：
+//   - 关键词距离：离"验证码"、"code"、"OTP"等关键词越近得分越高（英文关键词按整词匹配）
+//   - 长度：6 位最常见，4/8 位次之
+//   - 位置：在正文中出现得越早得分越高（只认前后不是字母或数字的独立出现，
+//     订单号等更长数字中的片段不算）
+//   - 新旧：codes 中越靠前（越新的邮件）得分越高
+//
+// 参数:
+//   codes: 候选验证码（通常为 CodeResult.AllCodes，新邮件在前）
+//   text: 邮件文本（主题 + 正文）
+//
+// 返回:
+//   []CodeCandidate: 按得分降序排列的候选列表
+func RankCodes(codes []string, text string) []CodeCandidate {
+	seen := make(map[string]bool, len(codes))
+	candidates := make([]CodeCandidate, 0, len(codes))
+
+	for i, code := range codes {
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		pos := indexCode(text, code)
+		score := weightLength*lengthScore(code) + weightRecency*(1-float64(i)/float64(len(codes)))
+		if pos >= 0 {
+			score += weightKeyword * keywordScore(text, pos, len(code))
+			score += weightPosition * (1 - float64(pos)/float64(len(text)))
+		}
+
+		// 形如年份的 4 位数字（19xx/20xx）很可能不是验证码
+		if len(code) == 4 && (strings.HasPrefix(code, "19") || strings.HasPrefix(code, "20")) {
+			score *= 0.6
+		}
+
+		candidate := CodeCandidate{Code: code, Score: score, Position: pos}
+		if pos >= 0 {
+			candidate.Snippet = snippetAround(text, pos, len(code), snippetRadius)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// snippetAround 截取 text[pos:pos+length] 前后各 radius 个字符（按 rune 计算）
+//
+// 换行会被替换为空格，便于在单行日志中输出。
+func snippetAround(text string, pos, length, radius int) string {
+	before := []rune(text[:pos])
+	if len(before) > radius {
+		before = before[len(before)-radius:]
+	}
+	after := []rune(text[pos+length:])
+	if len(after) > radius {
+		after = after[:radius]
+	}
+
+	snippet := string(before) + text[pos:pos+length] + string(after)
+	snippet = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(snippet)
+	return strings.TrimSpace(snippet)
+}
+
+// lengthScore 根据验证码长度打分
+func lengthScore(code string) float64 {
+	switch len(code) {
+	case 6:
+		return 1
+	case 5, 7:
+		return 0.85
+	case 4, 8:
+		return 0.7
+	default:
+		return 0.4
+	}
+}
+
+// indexCode 返回 code 在 text 中首次独立出现（前后不是 ASCII 字母或数字）的位置，找不到时返回 -1
+func indexCode(text, code string) int {
+	for start := 0; ; {
+		idx := strings.Index(text[start:], code)
+		if idx < 0 {
+			return -1
+		}
+		idx += start
+		end := idx + len(code)
+		if (idx == 0 || !isASCIIAlnum(text[idx-1])) && (end == len(text) || !isASCIIAlnum(text[end])) {
+			return idx
+		}
+		start = idx + 1
+	}
+}
+
+// isASCIIAlnum 判断字节是否为 ASCII 字母或数字
+func isASCIIAlnum(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// keywordScore 根据与最近关键词的距离打分
+//
+// 关键词直接在原文中匹配，pos 与关键词位置使用同一套字节偏移。
+func keywordScore(text string, pos, length int) float64 {
+	best := -1
+	for _, m := range codeKeywordPattern.FindAllStringIndex(text, -1) {
+		var dist int
+		switch {
+		case m[1] <= pos:
+			dist = pos - m[1]
+		case m[0] >= pos+length:
+			dist = m[0] - (pos + length)
+		default:
+			dist = 0
+		}
+		if best < 0 || dist < best {
+			best = dist
+		}
+	}
+
+	if best < 0 || best > keywordWindow {
+		return 0
+	}
+	return 1 - float64(best)/float64(keywordWindow)
+}
+
+// BestCode 返回得分最高且不低于 minScore 的验证码
+//
+// 需要先通过 ExtractCodeRanked 填充 Candidates。
+//
+// 返回:
+//   string: 验证码
+//   error: 没有候选时返回错误，得分不足时返回 ErrLowConfidence
+func (r *CodeResult) BestCode(minScore float64) (string, error) {
+	if len(r.Candidates) == 0 {
+		return "", errors.New("no code candidates")
+	}
+	if r.Candidates[0].Score < minScore {
+		return "", ErrLowConfidence
+	}
+	return r.Candidates[0].Code, nil
+}
+
+// ExtractCodeRanked 提取验证码并对所有候选进行评分排序
+//
+// 在 ExtractCode 的基础上读取最新邮件的正文，对 AllCodes 中的候选评分，
+// 结果写入 CodeResult.Candidates，并将 Code 设置为得分最高的候选。
+//
+// 示例:
+//   result, err := client.ExtractCodeRanked(ctx, address, 5)
+//   if err == nil {
+//       code, err := result.BestCode(0.6) // 要求最低置信度 0.6
+//   }
+func (c *Client) ExtractCodeRanked(ctx context.Context, address string, maxMails int) (*CodeResult, error) {
+	result, err := c.ExtractCode(ctx, address, maxMails)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Found {
+		return result, nil
+	}
+
+	codes := result.AllCodes
+	if len(codes) == 0 {
+		codes = []string{result.Code}
+	}
+
+	text := ""
+	if result.LatestMailID != "" {
+		detail, err := c.detailForExtraction(ctx, address, result.LatestMailID)
+		if err != nil {
+			return nil, err
+		}
+		text = mailText(detail)
+	}
+
+	result.Candidates = RankCodes(codes, text)
+	if len(result.Candidates) > 0 {
+		result.Code = result.Candidates[0].Code
+	}
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package mail2sdk
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit 响应头中的限流信息（X-RateLimit-Limit / Remaining / Reset）
+type RateLimit struct {
+	Limit     int       // 窗口内允许的请求数（未返回时为 -1）
+	Remaining int       // 窗口内剩余的请求数（未返回时为 -1）
+	Reset     time.Time // 窗口重置时间（未返回时为零值）
+	UpdatedAt time.Time // 收到该信息的时间
+}
+
+// Known 判断服务端是否返回了限流信息
+func (r RateLimit) Known() bool {
+	return r.Limit >= 0 || r.Remaining >= 0 || !r.Reset.IsZero()
+}
+
+// unknownRateLimit 尚未收到限流信息时的状态
+var unknownRateLimit = RateLimit{Limit: -1, Remaining: -1}
+
+// parseRateLimit 解析响应头中的限流信息
+//
+// X-RateLimit-Reset 兼容 Unix 时间戳和距离重置的秒数两种写法。
+func parseRateLimit(header http.Header, now time.Time) RateLimit {
+	limit := RateLimit{
+		Limit:     headerInt(header, "X-Ratelimit-Limit"),
+		Remaining: headerInt(header, "X-Ratelimit-Remaining"),
+		UpdatedAt: now,
+	}
+
+	if reset := headerInt(header, "X-Ratelimit-Reset"); reset >= 0 {
+		// 小于一年的秒数视为相对时间
+		if reset < 365*24*3600 {
+			limit.Reset = now.Add(time.Duration(reset) * time.Second)
+		} else {
+			limit.Reset = time.Unix(int64(reset), 0)
+		}
+	}
+	return limit
+}
+
+// headerInt 解析整数响应头（不存在或无法解析时返回 -1）
+//
+// key 使用规范化形式（如 "X-Ratelimit-Limit"），Get 不必再为规范化分配内存。
+func headerInt(header http.Header, key string) int {
+	value := header.Get(key)
+	if value == "" {
+		return -1
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// rateLimitTracker 每个客户端记录的最新限流状态
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	state *RateLimit // 最新状态（nil 表示尚未收到）
+
+	threshold int             // 剩余请求数低于等于该值时触发回调
+	onLow     func(RateLimit) // 限流余量不足时的回调
+}
+
+// OnRateLimitLow 设置限流余量不足时的回调
+//
+// 每次响应的 X-RateLimit-Remaining 小于等于 threshold 时都会调用 fn，
+// 编排程序可以据此在触发 429 之前主动降速。回调在发起请求的 goroutine 中
+// 同步执行，应尽快返回。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.OnRateLimitLow(10, func(r mail2sdk.RateLimit) {
+//       log.Printf("剩余 %d/%d 次请求，%s 后重置", r.Remaining, r.Limit, time.Until(r.Reset))
+//       limiter.SetLimit(limiter.Limit() / 2)
+//   }))
+func OnRateLimitLow(threshold int, fn func(RateLimit)) Option {
+	return func(c *Client) {
+		c.rateLimit.threshold = threshold
+		c.rateLimit.onLow = fn
+	}
+}
+
+// RateLimitState 返回最近一次响应中的限流信息
+//
+// 服务端从未返回限流响应头时，返回值的 Known() 为 false。
+func (c *Client) RateLimitState() RateLimit {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	if c.rateLimit.state == nil {
+		return unknownRateLimit
+	}
+	return *c.rateLimit.state
+}
+
+// observeRateLimit 记录响应中的限流信息，余量不足时触发回调
+func (c *Client) observeRateLimit(header http.Header) {
+	limit := parseRateLimit(header, time.Now())
+	if !limit.Known() {
+		return
+	}
+
+	state := limit
+	c.rateLimit.mu.Lock()
+	c.rateLimit.state = &state
+	onLow, threshold := c.rateLimit.onLow, c.rateLimit.threshold
+	c.rateLimit.mu.Unlock()
+
+	if onLow != nil && limit.Remaining >= 0 && limit.Remaining <= threshold {
+		onLow(limit)
+	}
+}
@@ -0,0 +1,62 @@
+package mail2sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrReadOnly 表示只读客户端拒绝了会修改数据的调用
+var ErrReadOnly = errors.New("client is read-only")
+
+// WithReadOnly 启用只读模式
+//
+// 只读客户端会拒绝所有会修改服务端数据的调用（创建、删除邮箱，设置密码，
+// 移入回收站等），直接返回 ErrReadOnly 而不发送请求；读取邮件、查询域名等
+// 调用不受影响。适合基于 SDK 构建的看板和排查工具，确保它们不会干扰正在
+// 进行的活动。换发邮箱级令牌（RefreshMailboxToken、Session.EnableTokenRefresh）
+// 虽然使用 POST，但不修改邮箱和邮件，在只读模式下同样允许。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithReadOnly())
+//   mails, err := client.GetMails(ctx, address)     // 正常执行
+//   err = client.DeleteMailbox(ctx, address)         // errors.Is(err, mail2sdk.ErrReadOnly)
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// ReadOnly 判断客户端是否为只读模式
+func (c *Client) ReadOnly() bool {
+	return c.settings().readOnly
+}
+
+// checkReadOnly 只读模式下拒绝 GET、HEAD、OPTIONS 以外的请求（换发邮箱令牌除外）
+func (c *Client) checkReadOnly(method, path string) error {
+	if !c.settings().readOnly {
+		return nil
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+	if isTokenRefresh(method, path) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", ErrReadOnly, method, path)
+}
+
+// isTokenRefresh 判断请求是否为换发邮箱令牌（POST /api/mailbox/{address}/token）
+func isTokenRefresh(method, path string) bool {
+	if method != http.MethodPost {
+		return false
+	}
+	rest, ok := strings.CutPrefix(path, "/api/mailbox/")
+	if !ok {
+		return false
+	}
+	address, ok := strings.CutSuffix(rest, "/token")
+	return ok && address != "" && !strings.Contains(address, "/")
+}
@@ -0,0 +1,42 @@
+package mail2sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+func TestReadOnlyAllowsTokenRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/version":
+			w.Write([]byte(`{"code":0,"data":{"version":"1.4.0"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/mailbox/user@example.com/token":
+			w.Write([]byte(`{"code":0,"data":{"access_token":"fresh-token"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	client := mail2sdk.NewClient(srv.URL, "key", mail2sdk.WithReadOnly())
+	ctx := context.Background()
+
+	token, err := client.RefreshMailboxToken(ctx, "user@example.com")
+	if err != nil {
+		t.Fatalf("RefreshMailboxToken: %v", err)
+	}
+	if token.Token != "fresh-token" {
+		t.Errorf("token = %q, want %q", token.Token, "fresh-token")
+	}
+
+	if err := client.DeleteMailbox(ctx, "user@example.com"); !errors.Is(err, mail2sdk.ErrReadOnly) {
+		t.Errorf("DeleteMailbox error = %v, want ErrReadOnly", err)
+	}
+}
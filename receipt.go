@@ -0,0 +1,77 @@
+package mail2sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// CreateReceipt 创建邮箱时服务端返回的完整信息
+//
+// Mailbox 只保留常用字段，批量创建时调优所需的配额、存储节点、限流等信息
+// 可以通过 WithCreateReceipt 获取。
+type CreateReceipt struct {
+	StatusCode int             // HTTP 状态码
+	Header     http.Header     // 响应头
+	Raw        json.RawMessage // 响应中数据字段（默认为 data，见 WithEnvelope）的原始内容
+
+	QuotaRemaining int       // 剩余可创建邮箱数（服务端未返回时为 -1）
+	StorageNode    string    // 分配的存储节点（服务端未返回时为空）
+	RateLimit      RateLimit // 响应头中的限流信息
+}
+
+// responseCapture 记录最后一次成功请求的原始响应
+type responseCapture struct {
+	dataKey string        // 响应中的数据字段名（见 Envelope.DataKey）
+	receipt CreateReceipt // 解析后的响应
+}
+
+// capture 解析响应
+//
+// body 来自缓冲池，只复制数据字段；响应只属于本次请求，响应头无需复制。
+func (r *responseCapture) capture(resp *http.Response, body []byte) {
+	r.receipt = CreateReceipt{
+		StatusCode:     resp.StatusCode,
+		Header:         resp.Header,
+		QuotaRemaining: -1,
+		RateLimit:      parseRateLimit(resp.Header, time.Now()),
+	}
+
+	data := envelopeData(body, r.dataKey)
+	if len(data) == 0 {
+		return
+	}
+	r.receipt.Raw = data
+
+	var meta struct {
+		QuotaRemaining *int   `json:"quota_remaining"`
+		StorageNode    string `json:"storage_node"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return
+	}
+	if meta.QuotaRemaining != nil {
+		r.receipt.QuotaRemaining = *meta.QuotaRemaining
+	}
+	r.receipt.StorageNode = meta.StorageNode
+}
+
+// envelopeData 返回响应中数据字段的副本（无法解析时返回 nil）
+func envelopeData(body []byte, dataKey string) json.RawMessage {
+	// 默认的数据字段直接解码，不必构建整个响应的 map
+	if dataKey == defaultEnvelope.DataKey {
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return nil
+		}
+		return envelope.Data
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+	return fields[dataKey]
+}
@@ -0,0 +1,160 @@
+package mail2sdk
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 脱敏后的占位文本
+const redactedText = "[REDACTED]"
+
+// 比该长度更短的密钥不做替换，避免误伤正常文本
+const minSecretLen = 4
+
+// Redactor 对错误信息和日志文本脱敏
+type Redactor interface {
+	Redact(s string) string
+}
+
+// RedactorFunc 将普通函数适配为 Redactor
+type RedactorFunc func(s string) string
+
+// Redact 实现 Redactor 接口
+func (f RedactorFunc) Redact(s string) string {
+	return f(s)
+}
+
+// SecretRedactor 将文本中出现的指定密钥替换为 [REDACTED]
+//
+// 示例:
+//   mail2sdk.WithRedactor(mail2sdk.SecretRedactor(os.Getenv("PROXY_PASSWORD")))
+func SecretRedactor(secrets ...string) Redactor {
+	var pairs []string
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLen {
+			pairs = append(pairs, secret, redactedText)
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+	return RedactorFunc(func(s string) string {
+		if len(pairs) == 0 {
+			return s
+		}
+		return replacer.Replace(s)
+	})
+}
+
+// WithRedactor 添加自定义脱敏规则
+//
+// 客户端默认会脱敏 API 密钥、邮箱令牌（包括创建邮箱时返回和换发得到的令牌），以及 URL 中
+// token/key/password 等查询参数。
+// 可以多次调用以追加规则，规则作用于所有返回的错误、审计记录和日志回调。
+func WithRedactor(r Redactor) Option {
+	return func(c *Client) {
+		c.redactors = append(c.redactors, r)
+	}
+}
+
+// URL 查询参数和请求头形式的敏感字段
+//
+// 参数名前要求单词边界，避免误伤 monkey=、hotkey= 之类以敏感词结尾的参数。
+var sensitiveParamPattern = regexp.MustCompile(`(?i)(\b(?:access_token|token|api_key|apikey|key|password|secret|signature)=)[^&\s"']+`)
+
+// 需要脱敏的请求头
+var sensitiveHeaders = []string{"X-Api-Key", "X-Mailbox-Token", "Authorization", "Cookie"}
+
+// redact 对文本应用内置和自定义的脱敏规则
+func (c *Client) redact(s string) string {
+	if s == "" {
+		return s
+	}
+	live := c.settings()
+	for _, secret := range []string{live.apiKey, live.mailboxToken} {
+		if len(secret) >= minSecretLen {
+			s = strings.ReplaceAll(s, secret, redactedText)
+		}
+	}
+	s = c.issued.redact(s)
+	s = sensitiveParamPattern.ReplaceAllString(s, "${1}"+redactedText)
+	for _, r := range c.redactors {
+		s = r.Redact(s)
+	}
+	return s
+}
+
+// 记住的服务端签发的令牌数量上限，超出后丢弃最早的
+const maxIssuedSecrets = 256
+
+// issuedSecrets 服务端签发给该客户端的邮箱令牌（创建邮箱返回的访问令牌和换发的令牌）
+type issuedSecrets struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// add 记录新签发的令牌
+func (t *issuedSecrets) add(secret string) {
+	if len(secret) < minSecretLen {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.secrets {
+		if s == secret {
+			return
+		}
+	}
+	if len(t.secrets) >= maxIssuedSecrets {
+		t.secrets = append(t.secrets[:0], t.secrets[1:]...)
+	}
+	t.secrets = append(t.secrets, secret)
+}
+
+// redact 将文本中出现的已签发令牌替换为 [REDACTED]
+func (t *issuedSecrets) redact(s string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, secret := range t.secrets {
+		s = strings.ReplaceAll(s, secret, redactedText)
+	}
+	return s
+}
+
+// redactedError 脱敏后的错误（保留原始错误链，便于 errors.Is/As 判断）
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError 对错误信息脱敏（内容没有变化时原样返回）
+func (c *Client) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if redacted := c.redact(msg); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+// redactHeader 返回脱敏后的请求头副本
+func (c *Client) redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[k] = make([]string, len(v))
+		for i := range v {
+			out[k][i] = c.redact(v[i])
+		}
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := out[k]; ok {
+			out[k] = []string{redactedText}
+		}
+	}
+	return out
+}
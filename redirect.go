@@ -0,0 +1,117 @@
+package mail2sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrRedirectedToLogin 表示 API 请求被重定向到了登录页面
+//
+// 常见于部署在 SSO 网关或反向代理之后的服务端：认证失效时网关把请求重定向到
+// 登录页，跟随重定向后得到的是 HTML 页面，看起来像是响应解析失败。检测到这种
+// 重定向时 SDK 不再跟随，直接返回该错误，且不会重试。
+var ErrRedirectedToLogin = errors.New("redirected to login page")
+
+// RedirectPolicy API 请求的重定向策略
+type RedirectPolicy int
+
+// 重定向策略常量
+const (
+	RedirectFollow   RedirectPolicy = iota // 跟随重定向，最多 10 次（默认）
+	RedirectSameHost                       // 只跟随同一主机内的重定向，跳转到其他主机时返回 3xx 的 APIError
+	RedirectNone                           // 不跟随重定向，3xx 响应作为 APIError 返回
+)
+
+// maxAPIRedirects 跟随重定向的最大次数（与 net/http 的默认值相同）
+const maxAPIRedirects = 10
+
+// WithRedirectPolicy 设置 API 请求的重定向策略
+//
+// 无论使用哪种策略，重定向到登录页面（路径或主机名包含 login、signin、sso、oauth
+// 等）时都返回 ErrRedirectedToLogin。使用 WithHTTPClient 时，调用方设置的
+// CheckRedirect 在 SDK 的检查通过后调用。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithRedirectPolicy(mail2sdk.RedirectNone))
+//   _, err := client.GetDomains(ctx)
+//   if errors.Is(err, mail2sdk.ErrRedirectedToLogin) {
+//       log.Fatal("访问令牌已失效，请重新登录网关")
+//   }
+func WithRedirectPolicy(policy RedirectPolicy) Option {
+	return func(c *Client) {
+		c.redirectPolicy = policy
+	}
+}
+
+// checkRedirect 返回 API 请求使用的重定向检查函数，next 为调用方原有的检查函数
+func (c *Client) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if isLoginURL(req.URL) {
+			return fmt.Errorf("%w: %s", ErrRedirectedToLogin, c.redact(req.URL.String()))
+		}
+
+		switch c.redirectPolicy {
+		case RedirectNone:
+			return http.ErrUseLastResponse
+		case RedirectSameHost:
+			if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+				return http.ErrUseLastResponse
+			}
+		}
+
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= maxAPIRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxAPIRedirects)
+		}
+		return nil
+	}
+}
+
+// redirectError 将未跟随的 3xx 响应转换为错误（不是 3xx 时返回 nil）
+func (c *Client) redirectError(resp *http.Response, body []byte) error {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return nil
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return &APIError{StatusCode: resp.StatusCode, Message: c.redact(string(body))}
+	}
+	if isLoginURL(location) {
+		return fmt.Errorf("%w: %s", ErrRedirectedToLogin, c.redact(location.String()))
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: c.redact("redirect to " + location.String())}
+}
+
+// loginPathSegments 登录页面路径中常见的片段
+var loginPathSegments = []string{
+	"login", "logon", "signin", "sign-in", "sign_in", "sso", "oauth", "oauth2",
+	"saml", "cas", "adfs", "openid-connect", "authorize",
+}
+
+// loginHostPrefixes 登录服务主机名常见的前缀
+var loginHostPrefixes = []string{"login.", "sso.", "auth.", "accounts.", "signin.", "idp."}
+
+// isLoginURL 判断地址是否像登录页面
+func isLoginURL(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range loginHostPrefixes {
+		if strings.HasPrefix(host, prefix) {
+			return true
+		}
+	}
+
+	for _, segment := range strings.Split(strings.ToLower(u.Path), "/") {
+		for _, login := range loginPathSegments {
+			if segment == login || strings.HasPrefix(segment, login+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package mail2sdk
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// reloadable 可以通过 Reload 在运行中替换的配置
+//
+// 快照创建后不再修改，Reload 整体替换为新的快照。运行中的请求使用读取到的快照，
+// 替换不影响它们；之后发出的请求（包括监听中的下一次轮询）使用新的快照。
+type reloadable struct {
+	apiKey          string        // API 密钥
+	apiKeyHeader    []string      // 预先构建的 X-API-Key 请求头值
+	mailboxToken    string        // 邮箱级访问令牌
+	httpClient      *http.Client  // 发送请求的 HTTP 客户端（Timeout 为默认请求超时）
+	sendClient      *http.Client  // httpClient 去掉 Timeout 的副本，超时由 attemptContext 通过上下文控制
+	blacklist       []string      // 客户端级别的域名黑名单
+	defaultMode     int           // ModeDefault 对应的生成模式
+	retry           RetryPolicy   // 重试策略
+	headers         http.Header   // 每个请求附加的自定义请求头
+	userAgentHeader []string      // 预先构建的 User-Agent 请求头值
+	locale          Locale        // ErrorMessage 使用的语言
+	readOnly        bool          // 只读模式
+	watchers        *watcherLimit // 同时进行的监听、等待操作上限（nil 表示不限制）
+}
+
+// settings 返回当前的可替换配置
+func (c *Client) settings() *reloadable {
+	return c.live.Load()
+}
+
+// snapshot 由配置项设置的字段构建可替换配置，httpClient 为已应用超时的 HTTP 客户端
+func (c *Client) snapshot(httpClient *http.Client) *reloadable {
+	s := &reloadable{
+		apiKey:          c.apiKey,
+		apiKeyHeader:    []string{c.apiKey},
+		mailboxToken:    c.mailboxToken,
+		httpClient:      httpClient,
+		sendClient:      httpClient,
+		blacklist:       c.blacklist,
+		defaultMode:     c.defaultMode,
+		retry:           c.retry,
+		headers:         c.headers,
+		userAgentHeader: userAgentHeader,
+		locale:          c.locale,
+		readOnly:        c.readOnly,
+		watchers:        c.watchers,
+	}
+	if c.userAgentSuffix != "" {
+		s.userAgentHeader = []string{userAgentHeader[0] + " " + c.userAgentSuffix}
+	}
+	if httpClient != nil && httpClient.Timeout > 0 {
+		hc := *httpClient
+		hc.Timeout = 0
+		s.sendClient = &hc
+	}
+	return s
+}
+
+// Reload 按新的配置替换客户端的运行参数，不中断正在进行的请求和监听
+//
+// 可以替换的配置：api_key、mailbox_token、timeout、mode、blacklist、retry、read_only、
+// headers、user_agent_suffix、locale、max_watchers 和 watcher_queue。替换是原子的，
+// 不会读到只替换了一半的配置；已经发出的请求按旧配置完成，正在重试的调用沿用旧的
+// 重试策略，Watch、WaitForCode 等监听不会重启，下一次轮询起使用新配置。
+//
+// 以 cfg 为准整体替换上述配置，cfg 中没有设置的项恢复为默认值（如 blacklist 为空时
+// 清空黑名单，timeout 为 0 时恢复创建客户端时的超时）；opts 在 cfg 之后应用，可以
+// 补充 Config 中没有的设置，但只有上述配置会生效。base_url、endpoints 和 dry_run
+// 不能替换，与当前客户端不一致时返回错误，需要重新创建客户端。同时进行的操作上限
+// 改变时，正在进行的操作仍占用旧上限的名额。
+//
+// 参数:
+//   cfg: 新的配置（先经过 Validate 校验）
+//   opts: 可选配置项（在 cfg 之后应用）
+//
+// 返回:
+//   error: 配置不合法或包含不能替换的改动时返回 ErrInvalidConfig，此时客户端配置不变
+//
+// 示例:
+//   // 控制面推送新配置时
+//   cfg, err := mail2sdk.ParseConfig(data)
+//   if err == nil {
+//       err = client.Reload(cfg)
+//   }
+//   if err != nil {
+//       log.Printf("配置未生效: %v", err)
+//   }
+func (c *Client) Reload(cfg *Config, opts ...Option) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: config is required", ErrInvalidConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := c.checkFixed(cfg); err != nil {
+		return err
+	}
+
+	// 在临时客户端上应用配置项，只取其中可以替换的字段
+	next := &Client{apiKey: cfg.APIKey}
+	for _, opt := range append(cfg.Options(), opts...) {
+		opt(next)
+	}
+
+	httpClient := c.httpClient
+	if next.timeout > 0 {
+		hc := *c.httpClient
+		hc.Timeout = next.timeout
+		httpClient = &hc
+	}
+	s := next.snapshot(httpClient)
+
+	// 上限没有变化时沿用原来的计数，避免正在进行的操作不再被计入
+	cur := c.settings()
+	if s.watchers != nil && cur.watchers != nil && cap(s.watchers.slots) == cap(cur.watchers.slots) && s.watchers.queue == cur.watchers.queue {
+		s.watchers = cur.watchers
+	}
+
+	c.live.Store(s)
+	return nil
+}
+
+// checkFixed 检查配置中不能替换的部分是否与当前客户端一致
+func (c *Client) checkFixed(cfg *Config) error {
+	if normalizeBaseURL(cfg.BaseURL)+normalizeBasePath(c.basePath) != c.baseURL {
+		return fmt.Errorf("%w: base_url cannot be reloaded, create a new client", ErrInvalidConfig)
+	}
+
+	current := make(map[string]bool)
+	if c.endpoints != nil {
+		for _, e := range c.endpoints.list[1:] {
+			current[e.base] = true
+		}
+	}
+	var wanted []*endpoint
+	if set := newEndpointSet(c.baseURL, cfg.Endpoints, c.basePath); set != nil {
+		wanted = set.list[1:]
+	}
+	changed := len(wanted) != len(current)
+	for _, e := range wanted {
+		changed = changed || !current[e.base]
+	}
+	if changed {
+		return fmt.Errorf("%w: endpoints cannot be reloaded, create a new client", ErrInvalidConfig)
+	}
+
+	if _, dryRun := c.transport.(dryRunTransport); cfg.DryRun != dryRun {
+		return fmt.Errorf("%w: dry_run cannot be reloaded, create a new client", ErrInvalidConfig)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mail2sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// RetryPolicy 请求重试策略
+//
+// 重试只针对可安全重放的失败：幂等请求（GET/HEAD/PUT/DELETE/OPTIONS）的网络错误、
+// 5xx 和 429 响应；非幂等请求（POST）只在服务端明确拒绝（429/503）时重试，
+// 避免重复创建邮箱。
+type RetryPolicy struct {
+	MaxRetries int           // 最大重试次数（0 表示不重试）
+	BaseDelay  time.Duration // 首次重试前的等待时间（之后指数增长，<= 0 表示 200ms）
+	MaxDelay   time.Duration // 单次等待时间上限（<= 0 表示 10s）
+}
+
+// delay 计算第 attempt 次失败后的等待时间（指数退避 + 随机抖动）
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 200 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 10 * time.Second
+	}
+
+	d := base
+	for i := 0; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+
+	// 在 [d/2, d] 范围内抖动，避免大量客户端同时重试
+	half := int64(d / 2)
+	if half > 0 {
+		d = time.Duration(half + randInt63n(half+1))
+	}
+	return d
+}
+
+// isIdempotent 判断 HTTP 方法是否幂等
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableStatus 判断 HTTP 状态码是否值得重试
+func isRetryableStatus(method string, status int) bool {
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		return true
+	}
+	return status >= 500 && isIdempotent(method)
+}
@@ -0,0 +1,162 @@
+package mail2sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ExtractionRule 声明式提取规则
+//
+// 规则可以从 JSON 配置文件中加载，无需重新编译即可调整提取逻辑。
+// Regex 与 Expr 二选一：
+//   - Regex: 正则表达式，命名分组作为字段，否则第一个分组（或整个匹配）作为 Name 字段
+//   - Expr: 返回字符串的规则表达式（语法见 README「声明式提取规则」），结果作为 Name 字段
+//
+// When 为可选的布尔表达式，只有满足条件的邮件才会执行该规则。
+type ExtractionRule struct {
+	Name   string `json:"name"`             // 字段名
+	Source string `json:"source,omitempty"` // Regex 的匹配范围：subject/from/to/text/html/body（默认 body）
+	Regex  string `json:"regex,omitempty"`  // 正则表达式
+	Expr   string `json:"expr,omitempty"`   // 规则表达式
+	When   string `json:"when,omitempty"`   // 执行条件
+}
+
+// compiledRule 编译后的规则
+type compiledRule struct {
+	ExtractionRule
+	re   *regexp.Regexp
+	expr exprNode
+	when exprNode
+}
+
+// RuleExtractor 由声明式规则组成的提取器
+type RuleExtractor struct {
+	rules []compiledRule
+}
+
+// NewRuleExtractor 编译一组提取规则
+//
+// 所有正则和表达式在创建时编译并检查类型（Expr 必须返回字符串，When 必须返回布尔值），
+// 规则有误时返回带规则名的错误。
+func NewRuleExtractor(rules []ExtractionRule) (*RuleExtractor, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i)
+		}
+		if (rule.Regex == "") == (rule.Expr == "") {
+			return nil, fmt.Errorf("rule %q: exactly one of regex and expr is required", rule.Name)
+		}
+		if rule.Source == "" {
+			rule.Source = "body"
+		}
+		if !exprVars[rule.Source] {
+			return nil, fmt.Errorf("rule %q: unknown source %q", rule.Name, rule.Source)
+		}
+
+		cr := compiledRule{ExtractionRule: rule}
+		var err error
+		if rule.Regex != "" {
+			if cr.re, err = regexp.Compile(rule.Regex); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+		} else if cr.expr, err = parseExpr(rule.Expr); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		} else if err = expectExprType(cr.expr, exprTypeString, "expr"); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		if rule.When != "" {
+			if cr.when, err = parseExpr(rule.When); err != nil {
+				return nil, fmt.Errorf("rule %q: when: %w", rule.Name, err)
+			}
+			if err = expectExprType(cr.when, exprTypeBool, "when"); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+		}
+		compiled = append(compiled, cr)
+	}
+	return &RuleExtractor{rules: compiled}, nil
+}
+
+// LoadExtractionRules 从 JSON 加载提取规则
+//
+// 示例配置:
+//   [
+//     {"name": "code", "regex": "验证码[:：]\\s*(\\d{6})"},
+//     {"name": "order", "regex": "(?P<order>ORD-\\d+)", "source": "subject"},
+//     {"name": "link", "expr": "body.find('https://\\S+/verify\\S*')",
+//      "when": "from.endsWith('@github.com') && subject.lower().contains('verify')"}
+//   ]
+//
+// 示例:
+//   f, _ := os.Open("rules.json")
+//   extractor, err := mail2sdk.LoadExtractionRules(f)
+//   session.Use(extractor)
+func LoadExtractionRules(r io.Reader) (*RuleExtractor, error) {
+	var rules []ExtractionRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("parse rules failed: %w", err)
+	}
+	return NewRuleExtractor(rules)
+}
+
+// Extract 实现 Extractor 接口
+//
+// 规则按顺序执行，同名字段先匹配的规则优先；某条规则求值失败不会影响其他规则。
+func (e *RuleExtractor) Extract(detail *MailDetail) (map[string]string, error) {
+	env := exprEnv(detail)
+	result := make(map[string]string)
+	set := func(k, v string) {
+		if _, ok := result[k]; !ok && v != "" {
+			result[k] = v
+		}
+	}
+
+	var errs []error
+	for _, rule := range e.rules {
+		if rule.when != nil {
+			ok, err := evalBool(rule.when, env)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: when: %w", rule.Name, err))
+				continue
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if rule.expr != nil {
+			v, err := evalString(rule.expr, env)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+				continue
+			}
+			set(rule.Name, v)
+			continue
+		}
+
+		m := rule.re.FindStringSubmatch(env[rule.Source])
+		if m == nil {
+			continue
+		}
+		named := false
+		for i, group := range rule.re.SubexpNames() {
+			if i > 0 && group != "" {
+				named = true
+				set(group, m[i])
+			}
+		}
+		switch {
+		case named:
+		case len(m) > 1:
+			set(rule.Name, m[1])
+		default:
+			set(rule.Name, m[0])
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
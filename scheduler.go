@@ -0,0 +1,509 @@
+package mail2sdk
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultSchedulerWorkers 调度器默认的工作协程数量
+	defaultSchedulerWorkers = 16
+	// defaultSchedulerBatch 一次批量请求默认包含的邮箱数
+	defaultSchedulerBatch = 50
+	// schedulerLagSmoothing 延迟滑动平均中新样本的权重
+	schedulerLagSmoothing = 0.1
+)
+
+// SchedulerOptions 批量监听调度器配置
+type SchedulerOptions struct {
+	Workers         int           // 工作协程数量，邮箱按地址固定分配到各个协程（<= 0 表示 16）
+	Interval        time.Duration // 每个邮箱的轮询间隔（<= 0 表示 Client.PollInterval，不会短于服务端建议的间隔）
+	BatchSize       int           // 一次批量请求最多包含的邮箱数（<= 0 表示 50，服务端不支持批量接口时逐个请求）
+	Buffer          int           // 事件通道缓冲大小（<= 0 表示 256）
+	IncludeExisting bool          // 为 true 时开始监听前已有的邮件也会作为事件发出
+}
+
+// SchedulerStats 调度器的运行统计
+type SchedulerStats struct {
+	Mailboxes int           // 正在监听的邮箱数
+	Polls     int64         // 已完成的邮箱轮询次数（批量请求中的每个邮箱各计一次）
+	Requests  int64         // 发出的邮件列表请求数（批量请求计一次）
+	Errors    int64         // 失败的邮箱轮询次数
+	Lag       time.Duration // 轮询实际开始时间比计划时间晚的滑动平均（各工作协程的最大值）
+	MaxLag    time.Duration // 已到期但尚未轮询的邮箱中等待最久的时长
+	Overdue   int           // 已到期但尚未轮询的邮箱数
+	Shards    []ShardStats  // 各工作协程的统计
+}
+
+// ShardStats 一个工作协程的统计
+type ShardStats struct {
+	Mailboxes int           // 分配到该协程的邮箱数
+	Lag       time.Duration // 轮询延迟的滑动平均
+	MaxLag    time.Duration // 已到期但尚未轮询的邮箱中等待最久的时长
+	Overdue   int           // 已到期但尚未轮询的邮箱数
+}
+
+// WatchScheduler 同时监听大量邮箱的调度器（见 Client.NewWatchScheduler）
+type WatchScheduler struct {
+	client *Client
+	opts   SchedulerOptions
+	shards []*schedShard
+	events chan MailEvent
+	ctx    context.Context
+
+	polls    atomic.Int64
+	requests atomic.Int64
+	failures atomic.Int64
+}
+
+// schedShard 一个工作协程负责的邮箱
+type schedShard struct {
+	mu    sync.Mutex
+	boxes map[string]*schedBox
+	queue schedQueue    // 按下次轮询时间排序
+	wake  chan struct{} // 新增邮箱时唤醒工作协程
+	lag   float64       // 轮询延迟的滑动平均（纳秒）
+}
+
+// schedBox 一个被监听的邮箱
+type schedBox struct {
+	address string
+	due     time.Time   // 下次轮询的计划时间
+	index   int         // 在 queue 中的位置（-1 表示正在轮询）
+	removed atomic.Bool // 已被 Remove（工作协程轮询期间也可能被移除）
+
+	seen  map[string]bool
+	first bool
+	tally *senderTally
+}
+
+// schedQueue 按计划时间排序的最小堆
+type schedQueue []*schedBox
+
+func (q schedQueue) Len() int           { return len(q) }
+func (q schedQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+func (q schedQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+func (q *schedQueue) Push(x interface{}) {
+	box := x.(*schedBox)
+	box.index = len(*q)
+	*q = append(*q, box)
+}
+func (q *schedQueue) Pop() interface{} {
+	old := *q
+	box := old[len(old)-1]
+	old[len(old)-1] = nil
+	box.index = -1
+	*q = old[:len(old)-1]
+	return box
+}
+
+// NewWatchScheduler 创建并启动批量监听调度器
+//
+// 为每个邮箱单独调用 Watch 时每个邮箱占用一个 goroutine 和一个监听名额，监听数千个
+// 邮箱时 goroutine 数量、同一时刻集中发出的请求和难以观察的轮询延迟都会成为问题。
+// 调度器使用固定数量的工作协程，邮箱按地址分配到各个协程；各邮箱的首次轮询分散在
+// 一个轮询间隔内，避免同时到期；服务端支持批量接口（FeatureBatchMails）时同一协程
+// 中已到期和即将到期（十分之一个间隔内）的邮箱合并为一次请求，否则逐个请求。
+//
+// 所有邮箱的事件从同一个通道发出，事件语义与 Watch 相同（每封邮件只发出一次，轮询
+// 失败发出 Err 不为 nil 的事件，WithNoiseFilter 同样生效）。事件没有被及时读取时
+// 工作协程会阻塞，轮询随之放慢。轮询跟不上计划时，Stats 中的 Lag 和 Overdue 会上升，
+// 此时应增加 Workers 或放宽 Interval。调度器整体占用一个监听名额（见 WithMaxWatchers）；
+// ctx 取消或客户端关闭后停止，事件通道随之关闭。
+//
+// 参数:
+//   ctx: 上下文（取消后停止调度器）
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   *WatchScheduler: 调度器，通过 Add / Remove 增减监听的邮箱
+//   error: 监听名额已满时返回 ErrTooManyWatchers
+//
+// 示例:
+//   sched, err := client.NewWatchScheduler(ctx, &mail2sdk.SchedulerOptions{Workers: 32})
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+//   for _, address := range addresses {
+//       sched.Add(address)
+//   }
+//   go func() {
+//       for range time.Tick(time.Minute) {
+//           st := sched.Stats()
+//           log.Printf("%d 个邮箱，平均延迟 %s，%d 个已到期未轮询", st.Mailboxes, st.Lag, st.Overdue)
+//       }
+//   }()
+//   for ev := range sched.Events() {
+//       if ev.Err == nil {
+//           fmt.Println(ev.Address, "新邮件:", ev.Mail.Subject)
+//       }
+//   }
+func (c *Client) NewWatchScheduler(ctx context.Context, opts *SchedulerOptions) (*WatchScheduler, error) {
+	var o SchedulerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultSchedulerWorkers
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultSchedulerBatch
+	}
+	if o.Buffer <= 0 {
+		o.Buffer = 256
+	}
+
+	release, err := c.acquireWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 客户端关闭时停止调度器并关闭事件通道
+	ctx, stop := c.bindLifetime(ctx)
+	s := &WatchScheduler{
+		client: c,
+		opts:   o,
+		shards: make([]*schedShard, o.Workers),
+		events: make(chan MailEvent, o.Buffer),
+		ctx:    ctx,
+	}
+
+	var wg sync.WaitGroup
+	for i := range s.shards {
+		shard := &schedShard{boxes: make(map[string]*schedBox), wake: make(chan struct{}, 1)}
+		s.shards[i] = shard
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.run(ctx, stop, shard)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		release()
+		stop()
+		close(s.events)
+	}()
+	return s, nil
+}
+
+// Events 返回事件通道（调度器停止后关闭）
+func (s *WatchScheduler) Events() <-chan MailEvent {
+	return s.events
+}
+
+// Add 开始监听邮箱（已在监听的邮箱忽略），首次轮询安排在一个轮询间隔内由地址决定的时刻
+func (s *WatchScheduler) Add(address string) error {
+	if address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	key := strings.ToLower(address)
+	hash := addressHash(key)
+	shard := s.shards[hash%uint64(len(s.shards))]
+
+	// 用哈希的高位决定首次轮询的相位，使邮箱均匀分布在轮询间隔内
+	phase := time.Duration(float64(s.interval()) * float64(hash>>32) / (1 << 32))
+	box := &schedBox{
+		address: address,
+		due:     time.Now().Add(phase),
+		seen:    make(map[string]bool),
+		first:   true,
+	}
+
+	shard.mu.Lock()
+	if _, ok := shard.boxes[key]; ok {
+		shard.mu.Unlock()
+		return nil
+	}
+	box.tally = s.client.senders.watch(address)
+	shard.boxes[key] = box
+	heap.Push(&shard.queue, box)
+	shard.mu.Unlock()
+
+	select {
+	case shard.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Remove 停止监听邮箱（正在进行的轮询完成后不再发出该邮箱的事件）
+func (s *WatchScheduler) Remove(address string) {
+	key := strings.ToLower(address)
+	shard := s.shards[addressHash(key)%uint64(len(s.shards))]
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	box, ok := shard.boxes[key]
+	if !ok {
+		return
+	}
+	delete(shard.boxes, key)
+	box.removed.Store(true)
+	if box.index >= 0 {
+		heap.Remove(&shard.queue, box.index)
+	}
+}
+
+// Len 返回正在监听的邮箱数
+func (s *WatchScheduler) Len() int {
+	n := 0
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		n += len(shard.boxes)
+		shard.mu.Unlock()
+	}
+	return n
+}
+
+// Stats 返回调度器的运行统计
+func (s *WatchScheduler) Stats() SchedulerStats {
+	now := time.Now()
+	stats := SchedulerStats{
+		Polls:    s.polls.Load(),
+		Requests: s.requests.Load(),
+		Errors:   s.failures.Load(),
+		Shards:   make([]ShardStats, len(s.shards)),
+	}
+	for i, shard := range s.shards {
+		shard.mu.Lock()
+		st := ShardStats{Mailboxes: len(shard.boxes), Lag: time.Duration(shard.lag)}
+		for _, box := range shard.queue {
+			if lag := now.Sub(box.due); lag > 0 {
+				st.Overdue++
+				st.MaxLag = max(st.MaxLag, lag)
+			}
+		}
+		shard.mu.Unlock()
+
+		stats.Shards[i] = st
+		stats.Mailboxes += st.Mailboxes
+		stats.Overdue += st.Overdue
+		stats.Lag = max(stats.Lag, st.Lag)
+		stats.MaxLag = max(stats.MaxLag, st.MaxLag)
+	}
+	return stats
+}
+
+// interval 每个邮箱的轮询间隔（每次重新计算，服务端调整建议间隔后立即生效）
+func (s *WatchScheduler) interval() time.Duration {
+	return s.client.pollInterval(s.opts.Interval)
+}
+
+// run 工作协程：轮询到期的邮箱并安排下次轮询
+func (s *WatchScheduler) run(ctx context.Context, stop context.CancelFunc, shard *schedShard) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		now := time.Now()
+		var batch []*schedBox
+		wait := time.Duration(-1)
+
+		// 可以批量请求时，稍后才到期的邮箱也提前合并到本次请求中，减少请求数
+		horizon := now
+		if !s.client.knownUnsupported(FeatureBatchMails) {
+			horizon = now.Add(s.interval() / 10)
+		}
+
+		shard.mu.Lock()
+		for len(shard.queue) > 0 && len(batch) < s.opts.BatchSize {
+			// 至少有一个邮箱已到期时才发出请求
+			due := shard.queue[0].due
+			if due.After(horizon) || len(batch) == 0 && due.After(now) {
+				break
+			}
+			box := heap.Pop(&shard.queue).(*schedBox)
+			shard.lag += schedulerLagSmoothing * (float64(max(now.Sub(box.due), 0)) - shard.lag)
+			batch = append(batch, box)
+		}
+		if len(batch) == 0 && len(shard.queue) > 0 {
+			wait = shard.queue[0].due.Sub(now)
+		}
+		shard.mu.Unlock()
+
+		if len(batch) == 0 {
+			var tick <-chan time.Time
+			if wait >= 0 {
+				timer.Reset(wait)
+				tick = timer.C
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-shard.wake:
+			case <-tick:
+			}
+			if wait >= 0 && !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			continue
+		}
+
+		if !s.poll(ctx, batch) {
+			// 预算用完或客户端关闭后继续轮询也只会立即失败，停止整个调度器
+			stop()
+			return
+		}
+
+		next := time.Now()
+		interval := s.interval()
+		shard.mu.Lock()
+		for _, box := range batch {
+			if box.removed.Load() {
+				continue
+			}
+			// 保持各邮箱的相位；落后超过一个间隔时不补轮询，从现在起重新计时
+			box.due = box.due.Add(interval)
+			if box.due.Before(next) {
+				box.due = next
+			}
+			heap.Push(&shard.queue, box)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// poll 轮询一批邮箱并发出事件，调度器应停止时返回 false
+func (s *WatchScheduler) poll(ctx context.Context, batch []*schedBox) bool {
+	results, errs := s.fetch(ctx, batch)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	for i, box := range batch {
+		s.polls.Add(1)
+		if err := errs[i]; err != nil {
+			s.failures.Add(1)
+			if box.removed.Load() {
+				continue
+			}
+			if !sendEvent(ctx, s.events, MailEvent{Address: box.address, Err: err}) || stopPolling(err) {
+				return false
+			}
+			continue
+		}
+
+		for _, mail := range results[i] {
+			if box.seen[mail.ID] {
+				continue
+			}
+			box.seen[mail.ID] = true
+			box.tally.observe(mail)
+
+			// 首次轮询看到的邮件视为已有邮件
+			if box.first && !s.opts.IncludeExisting {
+				continue
+			}
+			if box.removed.Load() || s.client.noiseFilter.isNoise(mail.From) {
+				continue
+			}
+			if !sendEvent(ctx, s.events, MailEvent{Address: box.address, Mail: mail}) {
+				return false
+			}
+		}
+		box.first = false
+	}
+	return true
+}
+
+// fetch 获取一批邮箱的邮件列表（服务端支持时合并为一次请求）
+func (s *WatchScheduler) fetch(ctx context.Context, batch []*schedBox) ([][]Mail, []error) {
+	results := make([][]Mail, len(batch))
+	errs := make([]error, len(batch))
+
+	c := s.client
+	if len(batch) > 1 && !c.knownUnsupported(FeatureBatchMails) {
+		addresses := make([]string, len(batch))
+		for i, box := range batch {
+			addresses[i] = box.address
+		}
+		s.requests.Add(1)
+		byAddress, err := c.batchMails(ctx, addresses)
+		if err == nil {
+			for i, box := range batch {
+				if r, ok := byAddress[strings.ToLower(box.address)]; ok {
+					results[i], errs[i] = r.mails, r.err
+				} else {
+					errs[i] = fmt.Errorf("mailbox %s missing from batch response", box.address)
+				}
+			}
+			return results, errs
+		}
+		if !errors.Is(err, ErrNotSupportedByServer) {
+			for i := range batch {
+				errs[i] = err
+			}
+			return results, errs
+		}
+	}
+
+	for i, box := range batch {
+		if ctx.Err() != nil {
+			break
+		}
+		s.requests.Add(1)
+		results[i], errs[i] = c.GetMails(ctx, box.address)
+	}
+	return results, errs
+}
+
+// batchResult 批量接口中一个邮箱的结果
+type batchResult struct {
+	mails []Mail
+	err   error
+}
+
+// batchMails 通过批量接口获取多个邮箱的收件箱邮件（键为小写地址）
+//
+// 服务端不支持批量接口时返回 ErrNotSupportedByServer，并记录下来，之后不再尝试。
+func (c *Client) batchMails(ctx context.Context, addresses []string) (map[string]batchResult, error) {
+	var result struct {
+		Mailboxes []struct {
+			Address string `json:"address"`
+			Mails   []Mail `json:"mails"`
+			Error   string `json:"error,omitempty"`
+		} `json:"mailboxes"`
+	}
+
+	query := url.Values{"addresses": {strings.Join(addresses, ",")}}
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "GET", "/api/mails/batch?"+query.Encode(), nil, &result); err != nil {
+		return nil, c.markUnsupportedOn404(FeatureBatchMails, err)
+	}
+
+	byAddress := make(map[string]batchResult, len(result.Mailboxes))
+	for _, mb := range result.Mailboxes {
+		r := batchResult{mails: mb.Mails}
+		if mb.Error != "" {
+			r.err = fmt.Errorf("get mails of %s failed: %s", mb.Address, mb.Error)
+		}
+		byAddress[strings.ToLower(mb.Address)] = r
+	}
+	return byAddress, nil
+}
+
+// addressHash 邮箱地址的哈希值（决定所属的工作协程和首次轮询的相位）
+func addressHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
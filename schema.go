@@ -0,0 +1,304 @@
+package mail2sdk
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 各接口响应 data 字段的 JSON Schema（仅使用 type、properties、required、items、
+// additionalProperties 和 format: date-time 这一子集）
+//
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// jsonSchema JSON Schema 子集
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Format               string                 `json:"format"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+}
+
+// schemaTypes 兼容 "type": "string" 和 "type": ["string", "null"] 两种写法
+type schemaTypes []string
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*t = multi
+	return nil
+}
+
+// schemaRoute 接口与 Schema 的对应关系（路径中的 * 匹配任意一段）
+type schemaRoute struct {
+	method  string
+	pattern []string
+	file    string
+}
+
+var schemaRoutes = []schemaRoute{
+	{"GET", []string{"api", "domains"}, "domains.json"},
+	{"GET", []string{"api", "domains", "stats"}, "domain_stats.json"},
+	{"POST", []string{"api", "mailbox"}, "mailbox.json"},
+	{"GET", []string{"api", "mailboxes"}, "mailboxes.json"},
+	{"GET", []string{"api", "mailbox", "*", "mails"}, "mails.json"},
+	{"GET", []string{"api", "mailbox", "*", "folders"}, "folders.json"},
+	{"GET", []string{"api", "mailbox", "*", "folders", "*", "mails"}, "mails.json"},
+	{"GET", []string{"api", "mailbox", "*", "mails", "*"}, "mail_detail.json"},
+	{"GET", []string{"api", "mailbox", "*", "code"}, "code.json"},
+}
+
+var (
+	schemasOnce sync.Once
+	schemas     map[string]*jsonSchema // 文件名 -> Schema
+	schemasErr  error
+)
+
+// loadSchemas 解析内嵌的全部 Schema
+func loadSchemas() (map[string]*jsonSchema, error) {
+	schemasOnce.Do(func() {
+		schemas = make(map[string]*jsonSchema)
+		for _, route := range schemaRoutes {
+			data, err := schemaFS.ReadFile("schemas/" + route.file)
+			if err != nil {
+				schemasErr = err
+				return
+			}
+			var s jsonSchema
+			if err := json.Unmarshal(data, &s); err != nil {
+				schemasErr = fmt.Errorf("parse schema %s failed: %w", route.file, err)
+				return
+			}
+			schemas[route.file] = &s
+		}
+	})
+	return schemas, schemasErr
+}
+
+// schemaFor 查找接口对应的 Schema（没有对应 Schema 时返回 nil）
+func schemaFor(method, path string) *jsonSchema {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, route := range schemaRoutes {
+		if route.method != method || len(route.pattern) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, p := range route.pattern {
+			if p != "*" && p != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			all, err := loadSchemas()
+			if err != nil {
+				return nil
+			}
+			return all[route.file]
+		}
+	}
+	return nil
+}
+
+// SchemaDrift 响应与 Schema 不一致的一处差异
+type SchemaDrift struct {
+	Path    string // JSON 路径（如 "data.mails[].received_at"）
+	Kind    string // unknown_field / type_mismatch / missing_field / invalid_format
+	Details string // 说明
+}
+
+// String 返回差异描述
+func (d SchemaDrift) String() string {
+	return d.Kind + " " + d.Path + ": " + d.Details
+}
+
+// validate 校验 value 是否符合 Schema，差异追加到 drifts
+func (s *jsonSchema) validate(path string, value interface{}, drifts *[]SchemaDrift) {
+	actual := jsonTypeOf(value)
+	if len(s.Type) > 0 && !s.allows(actual, value) {
+		*drifts = append(*drifts, SchemaDrift{
+			Path:    path,
+			Kind:    "type_mismatch",
+			Details: fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, "|"), actual),
+		})
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.Format == "date-time" && v != "" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				*drifts = append(*drifts, SchemaDrift{Path: path, Kind: "invalid_format", Details: "expected RFC 3339 date-time"})
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*drifts = append(*drifts, SchemaDrift{Path: path + "." + name, Kind: "missing_field", Details: "required field is missing"})
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			switch {
+			case ok:
+				prop.validate(path+"."+name, v[name], drifts)
+			case s.Properties != nil && (s.AdditionalProperties == nil || !*s.AdditionalProperties):
+				*drifts = append(*drifts, SchemaDrift{
+					Path:    path + "." + name,
+					Kind:    "unknown_field",
+					Details: "field not in schema (" + jsonTypeOf(v[name]) + ")",
+				})
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return
+		}
+		// 只报告每个位置的第一处差异，避免长列表刷屏
+		seen := make(map[string]bool)
+		for _, item := range v {
+			var itemDrifts []SchemaDrift
+			s.Items.validate(path+"[]", item, &itemDrifts)
+			for _, d := range itemDrifts {
+				if key := d.Kind + d.Path; !seen[key] {
+					seen[key] = true
+					*drifts = append(*drifts, d)
+				}
+			}
+		}
+	}
+}
+
+// allows 判断 Schema 是否允许该类型（integer 视为 number 的子集）
+func (s *jsonSchema) allows(actual string, value interface{}) bool {
+	for _, t := range s.Type {
+		if t == actual {
+			return true
+		}
+		if t == "integer" && actual == "number" {
+			if f := value.(float64); f == float64(int64(f)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeOf 返回 JSON 值的类型名称
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// ValidateResponse 按内嵌 Schema 校验接口响应
+//
+// 参数:
+//   method: HTTP 方法
+//   path: 接口路径（如 "/api/mailbox/x@example.com/mails"）
+//   respBody: 完整的响应体（包含 code/msg/data）
+//
+// 返回:
+//   []SchemaDrift: 差异列表（没有对应 Schema 或完全一致时为空）
+//   error: 响应体不是合法 JSON
+func ValidateResponse(method, path string, respBody []byte) ([]SchemaDrift, error) {
+	return validateResponse(method, path, respBody, defaultEnvelope.DataKey)
+}
+
+// validateResponse 按内嵌 Schema 校验响应中 dataKey 字段的内容
+func validateResponse(method, path string, respBody []byte, dataKey string) ([]SchemaDrift, error) {
+	schema := schemaFor(method, path)
+	if schema == nil {
+		return nil, nil
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+
+	var drifts []SchemaDrift
+	schema.validate(dataKey, envelope[dataKey], &drifts)
+	return drifts, nil
+}
+
+// WithSchemaValidation 启用响应 Schema 校验
+//
+// 启用后每个已知接口的响应都会按内嵌的 JSON Schema 校验，发现未知字段、类型变化、
+// 缺失字段时通过日志回调（WithLogHook）以 LogWarn 级别报告。同一处差异每个客户端
+// 只报告一次。校验不影响响应解析，适合在升级服务端前后开启，及早发现不兼容的变化。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey,
+//       mail2sdk.WithSchemaValidation(),
+//       mail2sdk.WithLogHook(func(e mail2sdk.LogEntry) {
+//           if e.Level >= mail2sdk.LogWarn {
+//               log.Println(e.Message)
+//           }
+//       }),
+//   )
+func WithSchemaValidation() Option {
+	return func(c *Client) {
+		c.schemaDrift = &sync.Map{}
+	}
+}
+
+// checkSchema 校验响应并报告新的差异
+func (c *Client) checkSchema(ctx context.Context, method, path string, respBody []byte) {
+	drifts, err := validateResponse(method, path, respBody, c.envelopeOrDefault().DataKey)
+	if err != nil {
+		return
+	}
+	for _, d := range drifts {
+		key := method + " " + d.String()
+		if _, reported := c.schemaDrift.LoadOrStore(key, true); reported {
+			continue
+		}
+		c.log(ctx, LogEntry{
+			Level:   LogWarn,
+			Message: "response schema drift: " + d.String(),
+			Method:  method,
+			URL:     c.url(path),
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package mail2sdk
+
+import "time"
+
+// NewDomainSelector 创建域名选择器
+//
+// 每个客户端默认拥有独立的选择器，各自记录轮询计数、禁用和冷却状态；指向同一服务端
+// 的多个客户端需要共享这些状态时，可以创建一个选择器并通过 WithDomainSelector 传入。
+func NewDomainSelector() *DomainSelector {
+	return &DomainSelector{
+		counters: make(map[string]int),
+		disabled: make(map[string]bool),
+		cooling:  make(map[string]domainCooldown),
+	}
+}
+
+// WithDomainSelector 使用指定的域名选择器
+//
+// 包级函数（CreateMailbox 等）使用的是全局默认选择器，GetDomainStats、
+// DisabledDomains 等包级函数查看的也是它。
+//
+// 示例:
+//   selector := mail2sdk.NewDomainSelector()
+//   a := mail2sdk.NewClient(baseURL, keyA, mail2sdk.WithDomainSelector(selector))
+//   b := mail2sdk.NewClient(baseURL, keyB, mail2sdk.WithDomainSelector(selector))
+func WithDomainSelector(selector *DomainSelector) Option {
+	return func(c *Client) {
+		c.selector = selector
+	}
+}
+
+// DomainSelector 返回客户端使用的域名选择器
+//
+// 示例:
+//   stats := client.DomainSelector().Stats()
+//   client.DomainSelector().Enable("domain1.com")
+func (c *Client) DomainSelector() *DomainSelector {
+	return c.selector
+}
+
+// Stats 返回每个域名的使用次数
+func (ds *DomainSelector) Stats() map[string]int {
+	return ds.getStats()
+}
+
+// Reset 清空所有域名的使用计数
+func (ds *DomainSelector) Reset() {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.counters = make(map[string]int)
+}
+
+// Disabled 返回被自动判定为已禁用的域名
+func (ds *DomainSelector) Disabled() []string {
+	return ds.disabledDomains()
+}
+
+// Enable 将域名移出禁用列表，重新参与自动选择
+func (ds *DomainSelector) Enable(domain string) {
+	ds.enable(domain)
+}
+
+// Cool 让域名进入冷却期（window 内完全避开，之后的 ramp 时间内逐步恢复）
+func (ds *DomainSelector) Cool(domain string, window, ramp time.Duration) {
+	ds.cool(domain, window, ramp)
+}
+
+// Cooling 返回处于冷却期（包括恢复阶段）的域名及其冷却结束时间
+func (ds *DomainSelector) Cooling() map[string]time.Time {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	domains := make(map[string]time.Time, len(ds.cooling))
+	for domain, cd := range ds.cooling {
+		domains[domain] = cd.until
+	}
+	return domains
+}
@@ -0,0 +1,138 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 自检步骤名称
+const (
+	SelfTestPing    = "ping"    // 检查连通性和 API 密钥（Ping）
+	SelfTestDomains = "domains" // 获取可用域名（GetDomains）
+	SelfTestCreate  = "create"  // 创建临时邮箱（CreateMailbox）
+	SelfTestList    = "list"    // 读取临时邮箱的邮件列表（GetMails）
+	SelfTestDelete  = "delete"  // 删除临时邮箱（DeleteMailbox）
+)
+
+// SelfTestStep 自检中一个步骤的结果
+type SelfTestStep struct {
+	Name     string        // 步骤名称（SelfTestPing 等）
+	Duration time.Duration // 耗时
+	Err      error         // 失败原因（nil 表示通过或跳过）
+	Skipped  bool          // 是否跳过（前置步骤失败或只读客户端）
+}
+
+// SelfTestReport 自检报告
+type SelfTestReport struct {
+	Steps    []SelfTestStep // 按执行顺序排列的步骤结果
+	Version  string         // 服务端版本（旧版本服务端为空）
+	Latency  time.Duration  // Ping 的往返耗时
+	Domains  []string       // 可用域名
+	Mailbox  string         // 自检创建的临时邮箱地址
+	Duration time.Duration  // 总耗时
+}
+
+// OK 判断所有执行的步骤是否都通过
+func (r *SelfTestReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err 返回第一个失败步骤的错误（全部通过时为 nil）
+func (r *SelfTestReport) Err() error {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return fmt.Errorf("self-test step %s failed: %w", step.Name, step.Err)
+		}
+	}
+	return nil
+}
+
+// String 返回便于输出到部署日志的多行摘要
+func (r *SelfTestReport) String() string {
+	var b strings.Builder
+	for _, step := range r.Steps {
+		switch {
+		case step.Skipped:
+			fmt.Fprintf(&b, "SKIP %s\n", step.Name)
+		case step.Err != nil:
+			fmt.Fprintf(&b, "FAIL %-8s %s: %v\n", step.Name, step.Duration.Round(time.Millisecond), step.Err)
+		default:
+			fmt.Fprintf(&b, "OK   %-8s %s\n", step.Name, step.Duration.Round(time.Millisecond))
+		}
+	}
+	return b.String()
+}
+
+// SelfTest 执行一次端到端自检
+//
+// 依次检查连通性和 API 密钥、获取域名、创建临时邮箱、读取其（空的）邮件列表并删除它，
+// 适合在部署流水线中作为一次调用的冒烟测试。某一步失败后，依赖它的步骤会被跳过；
+// 临时邮箱创建成功后总会尝试删除（即使 ctx 已被取消）。只读客户端跳过创建、读取和
+// 删除步骤。
+//
+// 返回:
+//   *SelfTestReport: 自检报告（总是非 nil）
+//   error: 第一个失败步骤的错误（与 report.Err() 相同）
+//
+// 示例:
+//   report, err := client.SelfTest(ctx)
+//   fmt.Print(report)
+//   if err != nil {
+//       os.Exit(1)
+//   }
+func (c *Client) SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	report := &SelfTestReport{}
+	start := time.Now()
+	failed := false
+
+	run := func(name string, skip bool, fn func() error) {
+		if skip {
+			report.Steps = append(report.Steps, SelfTestStep{Name: name, Skipped: true})
+			return
+		}
+		stepStart := time.Now()
+		err := fn()
+		report.Steps = append(report.Steps, SelfTestStep{Name: name, Duration: time.Since(stepStart), Err: err})
+		if err != nil {
+			failed = true
+		}
+	}
+
+	run(SelfTestPing, false, func() error {
+		result, err := c.Ping(ctx)
+		if err == nil {
+			report.Version, report.Latency = result.Version, result.Latency
+		}
+		return err
+	})
+	run(SelfTestDomains, failed, func() error {
+		domains, err := c.GetDomains(ctx)
+		if err == nil && len(domains) == 0 {
+			err = fmt.Errorf("no domains available")
+		}
+		report.Domains = domains
+		return err
+	})
+
+	var mailbox *Mailbox
+	run(SelfTestCreate, failed || c.ReadOnly(), func() error {
+		var err error
+		mailbox, err = c.CreateMailbox(ctx, ModeDefault, "", nil)
+		if err == nil {
+			report.Mailbox = mailbox.Address
+		}
+		return err
+	})
+	run(SelfTestList, mailbox == nil, func() error {
+		_, err := c.GetMails(ctx, mailbox.Address)
+		return err
+	})
+	run(SelfTestDelete, mailbox == nil, func() error {
+		return c.DeleteMailbox(context.WithoutCancel(ctx), mailbox.Address)
+	})
+
+	report.Duration = time.Since(start)
+	return report, report.Err()
+}
@@ -0,0 +1,213 @@
+package mail2sdk
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SenderStat 某个发件人域名发往某个收件邮箱域名的邮件统计
+type SenderStat struct {
+	Sender    string    // 发件人域名（Top 的结果中为汇总前的发件人域名）
+	Domain    string    // 收件邮箱域名（Top 的结果中为空，表示所有域名之和）
+	Mails     int       // 收到的邮件数
+	Mailboxes int       // 收到该发件人邮件的监听次数（同一邮箱被多次监听时分别计数）
+	LastSeen  time.Time // 最近一封邮件的接收时间
+}
+
+// DomainDelivery 某个收件邮箱域名的投递情况
+type DomainDelivery struct {
+	Domain    string // 收件邮箱域名
+	Watches   int    // 监听和等待的次数
+	Delivered int    // 至少收到一封邮件的次数
+	Mails     int    // 收到的邮件总数
+}
+
+// Silent 返回一封邮件都没有收到的监听次数
+func (d DomainDelivery) Silent() int {
+	return d.Watches - d.Delivered
+}
+
+// SenderReport 按发件人统计的收信报告
+type SenderReport struct {
+	Senders []SenderStat     // 按邮件数从多到少排列
+	Domains []DomainDelivery // 按收件邮箱域名排列
+}
+
+// Top 返回跨收件域名汇总后邮件数最多的 n 个发件人域名（n <= 0 表示全部）
+func (r *SenderReport) Top(n int) []SenderStat {
+	totals := make(map[string]*SenderStat)
+	var order []string
+	for _, s := range r.Senders {
+		total, ok := totals[s.Sender]
+		if !ok {
+			total = &SenderStat{Sender: s.Sender}
+			totals[s.Sender] = total
+			order = append(order, s.Sender)
+		}
+		total.Mails += s.Mails
+		total.Mailboxes += s.Mailboxes
+		if s.LastSeen.After(total.LastSeen) {
+			total.LastSeen = s.LastSeen
+		}
+	}
+
+	top := make([]SenderStat, 0, len(order))
+	for _, sender := range order {
+		top = append(top, *totals[sender])
+	}
+	sortSenderStats(top)
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// SenderStats 返回该客户端监听过的邮箱的收信统计
+//
+// Watch、WaitForMailMatching、WaitForCode 等轮询操作看到的每封邮件（包括开始监听前
+// 已有的邮件和被噪音过滤忽略的邮件）都会按发件人域名和收件邮箱域名计数；每次监听结束
+// 前是否收到过邮件按收件邮箱域名计数。据此可以量化哪些服务会投递到哪些临时域名，
+// 以及哪些域名的邮件从未到达（DomainDelivery.Silent）。统计只保存在内存中。
+//
+// 返回:
+//   *SenderReport: 统计快照
+//
+// 示例:
+//   report := client.SenderStats()
+//   for _, s := range report.Top(10) {
+//       fmt.Printf("%s: %d 封，%d 个邮箱\n", s.Sender, s.Mails, s.Mailboxes)
+//   }
+//   for _, d := range report.Domains {
+//       fmt.Printf("%s: %d/%d 次监听没有收到邮件\n", d.Domain, d.Silent(), d.Watches)
+//   }
+func (c *Client) SenderStats() *SenderReport {
+	return c.senders.report()
+}
+
+// ResetSenderStats 清空收信统计
+func (c *Client) ResetSenderStats() {
+	c.senders.mu.Lock()
+	c.senders.senders = nil
+	c.senders.domains = nil
+	c.senders.mu.Unlock()
+}
+
+// senderTracker 记录监听到的邮件的发件人（零值可用）
+type senderTracker struct {
+	mu      sync.Mutex
+	senders map[latencyKey]*SenderStat
+	domains map[string]*DomainDelivery
+}
+
+// senderTally 一次监听中已经计数的邮件
+type senderTally struct {
+	tracker *senderTracker
+	domain  string
+	counted map[string]bool // 已计数的邮件 ID
+	senders map[string]bool // 已计入 Mailboxes 的发件人域名
+}
+
+// watch 开始记录一次监听，返回用于计数邮件的 tally
+func (t *senderTracker) watch(address string) *senderTally {
+	domain := addressDomain(strings.ToLower(address))
+
+	t.mu.Lock()
+	t.domain(domain).Watches++
+	t.mu.Unlock()
+
+	return &senderTally{tracker: t, domain: domain, counted: make(map[string]bool), senders: make(map[string]bool)}
+}
+
+// domain 返回收件域名的投递统计（调用方持有 mu）
+func (t *senderTracker) domain(domain string) *DomainDelivery {
+	if t.domains == nil {
+		t.domains = make(map[string]*DomainDelivery)
+	}
+	d, ok := t.domains[domain]
+	if !ok {
+		d = &DomainDelivery{Domain: domain}
+		t.domains[domain] = d
+	}
+	return d
+}
+
+// observe 计数一封邮件（同一次监听中重复出现的邮件只计一次）
+func (s *senderTally) observe(mail Mail) {
+	if s.counted[mail.ID] {
+		return
+	}
+	first := len(s.counted) == 0
+	s.counted[mail.ID] = true
+
+	sender := addressDomain(senderAddress(mail.From))
+	newSender := !s.senders[sender]
+	s.senders[sender] = true
+	seen := mail.ReceivedAt
+	if seen.IsZero() {
+		seen = time.Now()
+	}
+
+	t := s.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d := t.domain(s.domain)
+	d.Mails++
+	if first {
+		d.Delivered++
+	}
+
+	if t.senders == nil {
+		t.senders = make(map[latencyKey]*SenderStat)
+	}
+	key := latencyKey{sender: sender, domain: s.domain}
+	stat, ok := t.senders[key]
+	if !ok {
+		stat = &SenderStat{Sender: sender, Domain: s.domain}
+		t.senders[key] = stat
+	}
+	stat.Mails++
+	if newSender {
+		stat.Mailboxes++
+	}
+	if seen.After(stat.LastSeen) {
+		stat.LastSeen = seen
+	}
+}
+
+// report 返回统计快照
+func (t *senderTracker) report() *SenderReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &SenderReport{
+		Senders: make([]SenderStat, 0, len(t.senders)),
+		Domains: make([]DomainDelivery, 0, len(t.domains)),
+	}
+	for _, stat := range t.senders {
+		report.Senders = append(report.Senders, *stat)
+	}
+	sortSenderStats(report.Senders)
+
+	for _, d := range t.domains {
+		report.Domains = append(report.Domains, *d)
+	}
+	sort.Slice(report.Domains, func(i, j int) bool { return report.Domains[i].Domain < report.Domains[j].Domain })
+	return report
+}
+
+// sortSenderStats 按邮件数从多到少排序（相同时按发件人、收件域名排序）
+func sortSenderStats(stats []SenderStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Mails != b.Mails {
+			return a.Mails > b.Mails
+		}
+		if a.Sender != b.Sender {
+			return a.Sender < b.Sender
+		}
+		return a.Domain < b.Domain
+	})
+}
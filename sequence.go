@@ -0,0 +1,84 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// sequenceMatcher 按顺序匹配多封邮件的状态机
+type sequenceMatcher struct {
+	steps   []MailMatcher
+	matched []*MailDetail
+	filled  int
+}
+
+// Match 把邮件分配给第一个尚未匹配且满足条件的步骤，所有步骤都匹配后返回 true
+//
+// 同一次轮询中的邮件可能以任意顺序出现，后续步骤的邮件先被读到时也会保留下来，
+// 不会因为前面的步骤尚未完成而被丢弃。
+func (m *sequenceMatcher) Match(detail *MailDetail) bool {
+	for i, step := range m.steps {
+		if m.matched[i] == nil && step.Match(detail) {
+			m.matched[i] = detail
+			m.filled++
+			break
+		}
+	}
+	return m.filled == len(m.steps)
+}
+
+// pending 返回第一个尚未匹配的步骤序号
+func (m *sequenceMatcher) pending() int {
+	for i, detail := range m.matched {
+		if detail == nil {
+			return i
+		}
+	}
+	return len(m.matched)
+}
+
+// ExpectSequence 等待依次满足各个条件的多封邮件
+//
+// 适用于先后发送多封邮件的流程（如先发验证码、再发注册确认）。每封邮件最多满足一个
+// 步骤，优先分配给靠前的步骤；所有步骤都匹配后按步骤顺序返回邮件。与嵌套多次
+// WaitForMailMatching 相比，后一步的邮件先到或与前一步在同一次轮询中出现时都不会遗漏。
+//
+// 参数:
+//   ctx: 上下文（建议设置覆盖整个流程的超时）
+//   address: 邮箱地址
+//   matchers: 各步骤的匹配条件
+//
+// 返回:
+//   []*MailDetail: 与 matchers 一一对应的邮件（出错时未匹配的步骤为 nil）
+//   error: ctx 结束时返回错误，说明停在哪一步
+//
+// 示例:
+//   mails, err := client.ExpectSequence(ctx, address,
+//       mail2sdk.SubjectRegexp(regexp.MustCompile(`(?i)verification code`)),
+//       mail2sdk.SubjectRegexp(regexp.MustCompile(`(?i)welcome|confirm`)),
+//   )
+//   fields, _ := mail2sdk.CodeExtractor().Extract(mails[0])
+//   client.ConfirmLink(ctx, mails[1], nil)
+func (c *Client) ExpectSequence(ctx context.Context, address string, matchers ...MailMatcher) ([]*MailDetail, error) {
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("at least one matcher is required")
+	}
+	for i, m := range matchers {
+		if m == nil {
+			return nil, fmt.Errorf("matcher %d is nil", i)
+		}
+	}
+
+	seq := &sequenceMatcher{steps: matchers, matched: make([]*MailDetail, len(matchers))}
+	if _, err := c.WaitForMailMatching(ctx, address, seq); err != nil {
+		return seq.matched, fmt.Errorf("sequence stopped at step %d of %d: %w", seq.pending()+1, len(matchers), err)
+	}
+	return seq.matched, nil
+}
+
+// ExpectSequence 等待会话邮箱收到依次满足各个条件的多封邮件（见 Client.ExpectSequence）
+func (s *Session) ExpectSequence(ctx context.Context, matchers ...MailMatcher) ([]*MailDetail, error) {
+	mails, err := s.client.ExpectSequence(s.waitContext(ctx), s.mailbox.Address, matchers...)
+	s.waitDone(err)
+	return mails, err
+}
@@ -0,0 +1,233 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Session 绑定一个邮箱的操作会话
+//
+// Session 把客户端和邮箱组合在一起，省去每次传递邮箱地址，并可以挂载提取器管线，
+// 对收到的邮件统一提取验证码、链接等数据。Session 可以在多个 goroutine 中共享。
+//
+// 示例:
+//   session, err := client.OpenSession(ctx, mail2sdk.ModeRandom, "", nil)
+//   if err != nil {
+//       log.Fatal(err)
+//   }
+//   defer session.Close(context.Background())
+//
+//   session.Use(mail2sdk.CodeExtractor(), mail2sdk.LinkExtractor())
+//   fields, _, err := session.ExtractLatest(ctx)
+//   fmt.Println(fields["code"], fields["link"])
+type Session struct {
+	client  *Client
+	mailbox *Mailbox
+
+	mu          sync.RWMutex
+	pipeline    Pipeline
+	triggeredAt time.Time          // 最近一次 Trigger 的时间
+	token       *sessionToken      // 会话持有的邮箱级令牌（nil 表示使用客户端的认证方式）
+	stopRefresh context.CancelFunc // 停止后台刷新令牌
+}
+
+// NewSession 为已有邮箱创建会话
+//
+// 会话在 Close 或 Detach 之前会计入客户端的 Inventory，不再使用的会话应调用其中之一。
+func (c *Client) NewSession(mailbox *Mailbox) *Session {
+	s := &Session{client: c, mailbox: mailbox}
+
+	c.sessionsMu.Lock()
+	if c.sessions == nil {
+		c.sessions = make(map[*Session]struct{})
+	}
+	c.sessions[s] = struct{}{}
+	c.sessionsMu.Unlock()
+
+	return s
+}
+
+// OpenSession 创建新邮箱并返回其会话
+//
+// 参数含义与 CreateMailbox 相同。
+func (c *Client) OpenSession(ctx context.Context, mode int, domain string, blacklist []string) (*Session, error) {
+	mailbox, err := c.CreateMailbox(ctx, mode, domain, blacklist)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewSession(mailbox), nil
+}
+
+// Client 返回会话使用的客户端
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// Mailbox 返回会话绑定的邮箱
+func (s *Session) Mailbox() *Mailbox {
+	return s.mailbox
+}
+
+// Address 返回会话绑定的邮箱地址
+func (s *Session) Address() string {
+	return s.mailbox.Address
+}
+
+// Use 向提取器管线追加提取器
+func (s *Session) Use(extractors ...Extractor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pipeline = append(s.pipeline, extractors...)
+}
+
+// Extract 对邮件执行会话的提取器管线
+func (s *Session) Extract(detail *MailDetail) (map[string]string, error) {
+	s.mu.RLock()
+	pipeline := s.pipeline
+	s.mu.RUnlock()
+
+	if len(pipeline) == 0 {
+		return nil, fmt.Errorf("no extractors configured")
+	}
+	return pipeline.Run(detail)
+}
+
+// ExtractLatest 读取最新一封邮件并执行提取器管线
+//
+// 返回:
+//   map[string]string: 提取结果
+//   *MailDetail: 最新邮件（邮箱为空时为 nil）
+//   error: 邮箱中没有邮件或请求失败时返回错误
+func (s *Session) ExtractLatest(ctx context.Context) (map[string]string, *MailDetail, error) {
+	mails, err := s.GetMails(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(mails) == 0 {
+		return nil, nil, fmt.Errorf("mailbox is empty")
+	}
+
+	latest := mails[0]
+	for _, mail := range mails[1:] {
+		if mail.ReceivedAt.After(latest.ReceivedAt) {
+			latest = mail
+		}
+	}
+
+	detail, err := s.client.detailForExtraction(s.requestContext(ctx), s.mailbox.Address, latest.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields, err := s.Extract(detail)
+	return fields, detail, err
+}
+
+// GetMails 获取会话邮箱的邮件列表
+func (s *Session) GetMails(ctx context.Context) ([]Mail, error) {
+	return s.client.GetMails(s.requestContext(ctx), s.mailbox.Address)
+}
+
+// GetMailDetail 获取会话邮箱中邮件的完整详情
+func (s *Session) GetMailDetail(ctx context.Context, mailID string) (*MailDetail, error) {
+	return s.client.GetMailDetail(s.requestContext(ctx), s.mailbox.Address, mailID)
+}
+
+// Watch 持续监听会话邮箱（见 Client.Watch）
+func (s *Session) Watch(ctx context.Context, opts *WatchOptions) (<-chan MailEvent, error) {
+	return s.client.Watch(s.requestContext(ctx), s.mailbox.Address, opts)
+}
+
+// requestContext 为请求附加会话的邮箱级令牌（启用 EnableTokenRefresh 后）
+func (s *Session) requestContext(ctx context.Context) context.Context {
+	s.mu.RLock()
+	token := s.token
+	s.mu.RUnlock()
+	if token == nil {
+		return ctx
+	}
+	return withSessionToken(ctx, token)
+}
+
+// Trigger 记录触发发送邮件的时间（如点击"发送验证码"之前调用）
+//
+// 客户端设置了 WithLatencyTracker 时，下一次等待到的邮件会以该时间为起点记录到达延迟。
+func (s *Session) Trigger() {
+	s.mu.Lock()
+	s.triggeredAt = time.Now()
+	s.mu.Unlock()
+}
+
+// waitContext 为等待调用附加触发时间和会话令牌
+func (s *Session) waitContext(ctx context.Context) context.Context {
+	ctx = s.requestContext(ctx)
+
+	s.mu.RLock()
+	trigger := s.triggeredAt
+	s.mu.RUnlock()
+	if trigger.IsZero() {
+		return ctx
+	}
+	return MarkTrigger(ctx, trigger)
+}
+
+// waitDone 等待成功后清除触发时间，避免后续等待重复计算
+func (s *Session) waitDone(err error) {
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.triggeredAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// WaitForMail 等待第一封满足条件的邮件（见 Client.WaitForMailMatching）
+func (s *Session) WaitForMail(ctx context.Context, matcher MailMatcher) (*MailDetail, error) {
+	detail, err := s.client.WaitForMailMatching(s.waitContext(ctx), s.mailbox.Address, matcher)
+	s.waitDone(err)
+	return detail, err
+}
+
+// WaitForCode 等待会话邮箱收到验证码，参数含义与 Client.WaitForCode 相同
+func (s *Session) WaitForCode(ctx context.Context, opts *WaitCodeOptions) (*CodeResult, error) {
+	result, err := s.client.WaitForCode(s.waitContext(ctx), s.Address(), opts)
+	s.waitDone(err)
+	return result, err
+}
+
+// WaitForCodeOrLink 等待会话邮箱收到验证码或确认链接，参数含义与 Client.WaitForCodeOrLink 相同
+func (s *Session) WaitForCodeOrLink(ctx context.Context) (*CodeOrLink, error) {
+	result, err := s.client.WaitForCodeOrLink(s.waitContext(ctx), s.Address())
+	s.waitDone(err)
+	return result, err
+}
+
+// Close 删除会话邮箱并停止后台刷新令牌
+//
+// 删除失败时会话同样结束（不再计入 Inventory），返回删除的错误，邮箱可能仍留在
+// 服务端，需要时由调用方重试 DeleteMailbox。
+//
+// 注意: 此操作不可逆！
+func (s *Session) Close(ctx context.Context) error {
+	s.Detach()
+	return s.client.DeleteMailbox(ctx, s.mailbox.Address)
+}
+
+// Detach 结束会话但不删除邮箱
+//
+// 停止后台刷新令牌，会话不再计入客户端的 Inventory。邮箱交给其他地方继续使用
+// （如归还到邮箱池）时调用。
+func (s *Session) Detach() {
+	s.mu.Lock()
+	if s.stopRefresh != nil {
+		s.stopRefresh()
+		s.stopRefresh = nil
+	}
+	s.mu.Unlock()
+
+	s.client.sessionsMu.Lock()
+	delete(s.client.sessions, s)
+	s.client.sessionsMu.Unlock()
+}
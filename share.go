@@ -0,0 +1,58 @@
+package mail2sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ShareLink 表示邮箱的只读分享链接
+type ShareLink struct {
+	URL       string    `json:"url"`        // 只读网页链接（无需登录即可查看邮件）
+	ExpiresAt time.Time `json:"expires_at"` // 链接过期时间
+}
+
+// ShareMailbox 生成邮箱的只读分享链接
+//
+// 常用于 CI 中注册流程失败时，把链接打印到日志里，开发者点击即可查看该邮箱收到的邮件。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   ttl: 链接有效期（<= 0 表示使用服务端默认值）
+//
+// 返回:
+//   *ShareLink: 分享链接
+//   error: 错误信息
+//
+// 示例:
+//   link, err := client.ShareMailbox(ctx, mailbox.Address, time.Hour)
+//   if err == nil {
+//       t.Logf("查看邮箱: %s", link.URL)
+//   }
+func (c *Client) ShareMailbox(ctx context.Context, address string, ttl time.Duration) (*ShareLink, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.requireFeature(ctx, FeatureShareLink); err != nil {
+		return nil, err
+	}
+
+	path := "/api/mailbox/" + escaped + "/share"
+	reqBody := map[string]interface{}{}
+	if ttl > 0 {
+		reqBody["ttl_seconds"] = int64(ttl / time.Second)
+	}
+
+	var link ShareLink
+	if err := c.do(ctx, "POST", path, reqBody, &link); err != nil {
+		return nil, err
+	}
+
+	if link.URL == "" {
+		return nil, fmt.Errorf("server returned empty share URL")
+	}
+	return &link, nil
+}
@@ -0,0 +1,186 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound 表示存储中不存在指定的键
+var ErrNotFound = errors.New("mail2sdk: key not found")
+
+// Store 键值存储接口
+//
+// SDK 中需要持久化本地状态的组件（如 Webhook 消费者的重试队列）都通过 Store 读写，
+// 用户可以接入 Redis、数据库等自己的实现。实现必须是并发安全的。
+type Store interface {
+	// Get 读取键对应的值，不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put 写入键值（覆盖已有值）
+	Put(ctx context.Context, key string, value []byte) error
+	// Delete 删除键（键不存在时不返回错误）
+	Delete(ctx context.Context, key string) error
+	// List 按字典序返回所有以 prefix 开头的键
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// MemoryStore 基于内存的 Store 实现（进程退出后数据丢失，适用于测试）
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Get 实现 Store 接口
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put 实现 Store 接口
+func (s *MemoryStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete 实现 Store 接口
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// List 实现 Store 接口
+func (s *MemoryStore) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// FileStore 基于本地目录的 Store 实现
+//
+// 每个键保存为目录下的一个文件（文件名为转义后的键），写入时先写临时文件再重命名，
+// 保证进程崩溃时不会留下写了一半的数据。
+type FileStore struct {
+	dir string
+	mu  sync.Mutex // 串行化同一进程内的写操作
+}
+
+// NewFileStore 创建本地目录存储（目录不存在时自动创建）
+//
+// 示例:
+//   store, err := mail2sdk.NewFileStore("/var/lib/myapp/mail2")
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create store dir failed: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path 返回键对应的文件路径
+//
+// PathEscape 不会转义 "."，"." 和 ".." 需要单独处理，避免指向存储目录之外。
+func (s *FileStore) path(key string) string {
+	name := url.PathEscape(key)
+	if name == "." || name == ".." {
+		name = strings.Repeat("%2E", len(name))
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// Get 实现 Store 接口
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+// Put 实现 Store 接口
+func (s *FileStore) Put(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file failed: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("write temp file failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("close temp file failed: %w", err)
+	}
+	if err := os.Rename(tmpName, s.path(key)); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("rename temp file failed: %w", err)
+	}
+	return nil
+}
+
+// Delete 实现 Store 接口
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List 实现 Store 接口
+func (s *FileStore) List(ctx context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+		key, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
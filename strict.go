@@ -0,0 +1,149 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnexpectedResponse 表示严格解析模式下响应与 SDK 的结构不一致
+//
+// 严格解析失败时返回的 *DecodeError 包装了该错误，可以用 errors.Is 判断。
+var ErrUnexpectedResponse = errors.New("response does not match sdk types")
+
+// WithStrictDecoding 启用严格解析模式
+//
+// 默认情况下响应中 SDK 不认识的字段会被静默忽略。启用后 data 字段出现未知字段时
+// 调用直接失败，返回包装了 ErrUnexpectedResponse 的 *DecodeError（附带原始响应），
+// 服务端升级后能在测试环境中第一时间发现。与只报告不失败的 WithSchemaValidation
+// 相比更严格，缺失字段仍需依靠 WithSchemaValidation 发现。不建议在生产环境开启。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithStrictDecoding())
+//   _, err := client.GetMails(ctx, address)
+//   if errors.Is(err, mail2sdk.ErrUnexpectedResponse) {
+//       t.Fatalf("服务端响应结构发生变化: %v", err)
+//   }
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// decodeEnvelopeStrict 解析标准响应，data 字段中出现未知字段时返回错误
+func decodeEnvelopeStrict(respBody []byte, result interface{}) error {
+	var apiResp apiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("parse response failed: %w", err)
+	}
+
+	if apiResp.Code != 0 && apiResp.Code != 200 {
+		return &APIError{Code: apiResp.Code, Message: apiResp.Msg}
+	}
+
+	if len(apiResp.Data) == 0 {
+		return nil
+	}
+	return decodeStrict(apiResp.Data, result)
+}
+
+// decodeStrict 解析 data 字段，出现未知字段时返回错误
+func decodeStrict(data []byte, result interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(result); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	// 自定义 UnmarshalJSON 的类型（如 Mail）内部不受 DisallowUnknownFields 约束，需要单独检查
+	if err := checkUnknownFields(data, reflect.TypeOf(result)); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	return nil
+}
+
+// checkUnknownFields 检查 JSON 对象中是否有类型 t 中不存在的字段
+func checkUnknownFields(data []byte, t reflect.Type) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return nil // 不是对象（如 time.Time 的字符串）
+		}
+		known := jsonFields(t)
+		for name, raw := range fields {
+			field, ok := known[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("json: unknown field %q", name)
+			}
+			if err := checkUnknownFields(raw, field); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for _, item := range items {
+			if err := checkUnknownFields(item, t.Elem()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for _, item := range items {
+			if err := checkUnknownFields(item, t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFields 返回结构体的 JSON 字段名（小写，与 encoding/json 一样不区分大小写）及其类型
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, ft := range jsonFields(embedded) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = ft
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,96 @@
+package mail2sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// TemplateID 返回邮件模板指纹
+//
+// 指纹由规范化后的主题和正文结构计算：有 HTML 正文时使用 HTML 标签结构，
+// 否则使用纯文本正文的各行。链接、邮箱地址、长令牌和数字会先替换为占位符，
+// 因此同一模板发出的不同验证码邮件得到相同的指纹。
+//
+// 返回:
+//   string: 16 位十六进制字符串
+//
+// 示例:
+//   detail, _ := client.GetMailDetail(ctx, address, mailID)
+//   fmt.Println(detail.TemplateID()) // 如: 9f2c4e1a7b3d5f60
+func (d *MailDetail) TemplateID() string {
+	h := sha256.New()
+	h.Write([]byte(normalizeLine(d.Subject)))
+	h.Write([]byte{0})
+
+	lines := htmlStructure(d.HTMLBody)
+	if len(lines) == 0 {
+		lines = normalizedLines(d.TextBody)
+	}
+	for _, line := range lines {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// TemplateSet 已知邮件模板集合（可在多个 goroutine 中共享）
+//
+// 用于按模板归类收到的验证邮件，并在出现未知模板时告警。
+//
+// 示例:
+//   known := mail2sdk.NewTemplateSet(savedIDs...)
+//   if id, isNew := known.Observe(detail); isNew {
+//       log.Printf("发现未知邮件模板 %s: %s", id, detail.Subject)
+//   }
+type TemplateSet struct {
+	mu     sync.Mutex
+	counts map[string]int // 模板 ID -> 出现次数
+}
+
+// NewTemplateSet 创建模板集合
+//
+// 参数:
+//   ids: 预先已知的模板 ID（如从配置中加载）
+func NewTemplateSet(ids ...string) *TemplateSet {
+	s := &TemplateSet{counts: make(map[string]int, len(ids))}
+	for _, id := range ids {
+		s.counts[id] = 0
+	}
+	return s
+}
+
+// Observe 记录一封邮件的模板
+//
+// 返回:
+//   string: 模板 ID
+//   bool: 该模板此前是否未知
+func (s *TemplateSet) Observe(detail *MailDetail) (string, bool) {
+	id := detail.TemplateID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, known := s.counts[id]
+	s.counts[id]++
+	return id, !known
+}
+
+// Known 判断模板 ID 是否已知
+func (s *TemplateSet) Known(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.counts[id]
+	return ok
+}
+
+// Counts 返回各模板的出现次数
+func (s *TemplateSet) Counts() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]int, len(s.counts))
+	for id, n := range s.counts {
+		counts[id] = n
+	}
+	return counts
+}
@@ -0,0 +1,79 @@
+package mail2sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// 请求默认超时时间
+const defaultTimeout = 30 * time.Second
+
+// WithTimeout 设置客户端的默认请求超时时间
+//
+// 超时时间包括连接、发送请求和读取响应，每次重试单独计时。默认 30 秒；
+// 同时使用 WithHTTPClient 时会覆盖该 HTTP 客户端的 Timeout。单次调用需要
+// 不同的超时时间时使用 WithRequestTimeout。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithTimeout(10*time.Second))
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithRequestTimeout 返回一个上下文，使用该上下文的请求按 d 超时，代替客户端的默认超时
+//
+// 与 context.WithTimeout 不同，超时时间作用于每一次 HTTP 请求（每次重试单独计时），
+// 而不是整个调用，因此可以比客户端默认超时更长或更短：轮询时用较短的超时尽快放弃
+// 卡住的请求，批量操作中的慢接口则可以放宽。
+//
+// 示例:
+//   // 轮询邮件列表时单次请求最多等 5 秒
+//   mails, err := client.GetMails(mail2sdk.WithRequestTimeout(ctx, 5*time.Second), address)
+//
+//   // 导出大邮箱时放宽到 2 分钟
+//   result := exporter.ExportMailboxes(mail2sdk.WithRequestTimeout(ctx, 2*time.Minute), addresses)
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestTimeout, d)
+}
+
+// requestTimeoutFrom 返回上下文中的单次请求超时时间
+func requestTimeoutFrom(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(ctxKeyRequestTimeout).(time.Duration)
+	return d, ok && d > 0
+}
+
+// Timeout 返回客户端的默认请求超时时间（0 表示由 HTTP 客户端决定）
+func (c *Client) Timeout() time.Duration {
+	return c.settings().httpClient.Timeout
+}
+
+// attemptContext 为单次请求设置超时时间（WithRequestTimeout 指定的时间，否则为客户端的默认超时）
+//
+// 超时由上下文控制，请求通过 Timeout 为 0 的 HTTP 客户端发送，比 http.Client.Timeout
+// 少分配一组计时器和取消函数。调用方需要在读完响应体之后再调用返回的 cancel。
+func (c *Client) attemptContext(ctx context.Context, s *reloadable) (context.Context, context.CancelFunc) {
+	d, ok := requestTimeoutFrom(ctx)
+	if !ok {
+		d = s.httpClient.Timeout
+	}
+	if d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
+
+// httpClientFor 返回发送该请求使用的 HTTP 客户端
+//
+// 请求指定了单次超时时间时由上下文控制超时，不再受客户端默认超时限制。
+func (c *Client) httpClientFor(req *http.Request) *http.Client {
+	httpClient := c.settings().httpClient
+	if _, ok := requestTimeoutFrom(req.Context()); !ok || httpClient.Timeout == 0 {
+		return httpClient
+	}
+	hc := *httpClient
+	hc.Timeout = 0
+	return &hc
+}
@@ -0,0 +1,211 @@
+package mail2sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeLayouts 解析响应中的时间字符串时，在 RFC 3339 之后依次尝试的格式
+//
+// 不带时区的格式按 UTC 解析。对接使用其他格式的服务端时可以追加，应在发起任何请求之前设置。
+var TimeLayouts = []string{
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// 数值时间戳超过该值时视为毫秒（秒级时间戳要到 33658 年才会达到）
+const epochMillisThreshold = 1e12
+
+// Timestamp 兼容多种格式的时间
+//
+// 不同版本的服务端可能以 Unix 时间戳（秒或毫秒，数字或数字字符串）或 RFC 3339 字符串
+// 返回时间，标准的 time.Time 只接受后者。Timestamp 依次尝试 RFC 3339、TimeLayouts
+// 和时间戳，空字符串和 0 解析为零值。序列化时输出 RFC 3339。
+//
+// Mailbox、Mail、MailDetail 的时间字段在解析时已经使用 Timestamp，调用方自己解析
+// 响应（如 DoRaw 的结果）时也可以直接使用。
+//
+// 示例:
+//   var resp struct {
+//       UpdatedAt mail2sdk.Timestamp `json:"updated_at"`
+//   }
+//   json.Unmarshal([]byte(`{"updated_at": 1700000000}`), &resp)
+//   fmt.Println(resp.UpdatedAt.Time) // 2023-11-14 22:13:20 +0000 UTC
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON 解析数字、数字字符串或时间字符串
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	// 与 time.Time 一致，null 不修改原值
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	// 常见的 RFC 3339 字符串直接按字节解析，不分配内存
+	var t3339 time.Time
+	if len(data) > 0 && data[0] == '"' && t3339.UnmarshalJSON(data) == nil {
+		t.Time = t3339
+		return nil
+	}
+
+	var s string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(data)
+	}
+
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// ParseTimestamp 按 Timestamp 的规则解析时间（空字符串返回零值）
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return time.Time{}, fmt.Errorf("parse time %q failed: unsupported format", s)
+	}
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	if math.Abs(n) >= epochMillisThreshold {
+		return time.UnixMilli(int64(n)).UTC(), nil
+	}
+	sec, frac := math.Modf(n)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
+
+// UnmarshalJSON 解析邮箱信息（时间字段兼容多种格式，见 Timestamp）
+func (m *Mailbox) UnmarshalJSON(data []byte) error {
+	type plain Mailbox
+	aux := struct {
+		*plain
+		ExpiresAt            Timestamp `json:"expires_at"`
+		CreatedAt            Timestamp `json:"created_at"`
+		AccessTokenExpiresAt Timestamp `json:"access_token_expires_at"`
+	}{plain: (*plain)(m), ExpiresAt: Timestamp{m.ExpiresAt}, CreatedAt: Timestamp{m.CreatedAt}, AccessTokenExpiresAt: Timestamp{m.AccessTokenExpiresAt}}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.ExpiresAt, m.CreatedAt, m.AccessTokenExpiresAt = aux.ExpiresAt.Time, aux.CreatedAt.Time, aux.AccessTokenExpiresAt.Time
+	return nil
+}
+
+// UnmarshalJSON 解析邮件基本信息（时间字段兼容多种格式，见 Timestamp）
+func (m *Mail) UnmarshalJSON(data []byte) error {
+	type plain Mail
+	aux := struct {
+		*plain
+		ReceivedAt Timestamp `json:"received_at"`
+	}{plain: (*plain)(m), ReceivedAt: Timestamp{m.ReceivedAt}}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.ReceivedAt = aux.ReceivedAt.Time
+	return nil
+}
+
+// plainMail 没有 UnmarshalJSON 方法的 Mail
+type plainMail Mail
+
+// mailJSON 直接解码邮件基本信息的结构（外层的 ReceivedAt 覆盖 plainMail 中的同名字段）
+//
+// 解析邮件列表时直接解码为 []mailJSON，不必为每封邮件调用一次 Mail.UnmarshalJSON。
+type mailJSON struct {
+	plainMail
+	ReceivedAt Timestamp `json:"received_at"`
+}
+
+// mail 转换为 Mail
+func (w *mailJSON) mail() Mail {
+	m := Mail(w.plainMail)
+	m.ReceivedAt = w.ReceivedAt.Time
+	return m
+}
+
+// mailJSONPool 复用解析邮件列表时的临时切片
+var mailJSONPool = sync.Pool{
+	New: func() interface{} {
+		list := make([]mailJSON, 0, 32)
+		return &list
+	},
+}
+
+// 超过该长度的临时切片不放回池中
+const maxPooledMailList = 1024
+
+// decodeMailList 将 mailJSON 列表转换为 []Mail，并把临时切片放回池中
+func decodeMailList(list *[]mailJSON) []Mail {
+	mails := make([]Mail, len(*list))
+	for i := range *list {
+		mails[i] = (*list)[i].mail()
+	}
+	if cap(*list) <= maxPooledMailList {
+		clear((*list)[:cap(*list)])
+		*list = (*list)[:0]
+		mailJSONPool.Put(list)
+	}
+	return mails
+}
+
+// plainMailDetail 没有 UnmarshalJSON 方法的 MailDetail
+type plainMailDetail MailDetail
+
+// mailDetailJSON 直接解码邮件详情的结构（外层的 ReceivedAt 覆盖 plainMailDetail 中的同名字段）
+//
+// 获取邮件详情时解码到 mailDetailJSON，详情与时间字段共用一次分配。
+type mailDetailJSON struct {
+	plainMailDetail
+	ReceivedAt Timestamp `json:"received_at"`
+}
+
+// detail 返回解码后的邮件详情（与 w 共用内存）
+func (w *mailDetailJSON) detail() *MailDetail {
+	detail := (*MailDetail)(&w.plainMailDetail)
+	detail.ReceivedAt = w.ReceivedAt.Time
+	return detail
+}
+
+// UnmarshalJSON 解析邮件详情（时间字段兼容多种格式，见 Timestamp）
+func (m *MailDetail) UnmarshalJSON(data []byte) error {
+	type plain MailDetail
+	aux := struct {
+		*plain
+		ReceivedAt Timestamp `json:"received_at"`
+	}{plain: (*plain)(m), ReceivedAt: Timestamp{m.ReceivedAt}}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.ReceivedAt = aux.ReceivedAt.Time
+	return nil
+}
@@ -0,0 +1,168 @@
+package mail2sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 刷新令牌失败后的最长重试间隔
+const maxTokenRetryDelay = 30 * time.Second
+
+// 两次刷新之间的最短间隔（令牌有效期短于提前量或时钟偏差时避免连续刷新）
+const minTokenRefreshInterval = 5 * time.Second
+
+// MailboxToken 邮箱级访问令牌
+type MailboxToken struct {
+	Token     string    `json:"access_token"` // 令牌
+	ExpiresAt time.Time `json:"expires_at"`   // 过期时间（零值表示不过期）
+}
+
+// RefreshMailboxToken 为邮箱换发新的邮箱级访问令牌
+//
+// 请求使用客户端的 API 密钥或当前有效的邮箱级令牌认证。服务端不支持时返回
+// ErrNotSupportedByServer。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//
+// 返回:
+//   *MailboxToken: 新令牌
+//   error: 错误信息
+func (c *Client) RefreshMailboxToken(ctx context.Context, address string) (*MailboxToken, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.requireFeature(ctx, FeatureTokenRefresh); err != nil {
+		return nil, err
+	}
+
+	var token MailboxToken
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "POST", "/api/mailbox/"+escaped+"/token", nil, &token); err != nil {
+		return nil, err
+	}
+	c.issued.add(token.Token)
+	return &token, nil
+}
+
+// sessionToken 会话持有的邮箱级令牌（可在后台刷新）
+type sessionToken struct {
+	mu        sync.RWMutex
+	token     string
+	expiresAt time.Time
+}
+
+// get 返回当前令牌
+func (t *sessionToken) get() (string, time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.token, t.expiresAt
+}
+
+// set 更新令牌
+func (t *sessionToken) set(token *MailboxToken) {
+	t.mu.Lock()
+	t.token, t.expiresAt = token.Token, token.ExpiresAt
+	t.mu.Unlock()
+}
+
+// withSessionToken 让请求使用会话的邮箱级令牌
+func withSessionToken(ctx context.Context, token *sessionToken) context.Context {
+	return context.WithValue(ctx, ctxKeyMailboxToken, token)
+}
+
+// EnableTokenRefresh 使用邮箱的访问令牌读取邮件，并在过期前自动刷新
+//
+// 服务端签发短期邮箱令牌（Mailbox.AccessTokenExpiresAt 不为零）时，长时间运行的
+// Watch、WaitForCode 等调用可能在中途因令牌过期返回 401。启用后会话的读取请求
+// 都携带该令牌，后台在过期前 lead 时间换发新令牌；换发失败时会以递增间隔重试，
+// 直到令牌过期，失败信息通过日志回调（LogWarn）报告。令牌剩余有效期不足 lead
+// （服务端签发的有效期过短或本地时钟偏差）时，等待剩余有效期的一半（至少 5 秒）
+// 再刷新；换发的令牌没有延长有效期时同样以递增间隔放慢刷新。Close 会停止刷新。
+//
+// 参数:
+//   lead: 提前刷新的时间（<= 0 表示 1 分钟）
+//
+// 示例:
+//   session, _ := client.OpenSession(ctx, mail2sdk.ModeRandom, "", nil)
+//   session.EnableTokenRefresh(2 * time.Minute)
+//   events, _ := session.Watch(ctx, nil)
+func (s *Session) EnableTokenRefresh(lead time.Duration) {
+	if lead <= 0 {
+		lead = time.Minute
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil {
+		return
+	}
+	s.token = &sessionToken{token: s.mailbox.AccessToken, expiresAt: s.mailbox.AccessTokenExpiresAt}
+	s.client.issued.add(s.mailbox.AccessToken)
+
+	ctx, cancel := context.WithCancel(s.client.lifetime)
+	s.stopRefresh = cancel
+	go s.refreshLoop(ctx, s.token, lead)
+}
+
+// refreshLoop 在令牌过期前 lead 时间刷新令牌，直到 ctx 被取消
+func (s *Session) refreshLoop(ctx context.Context, token *sessionToken, lead time.Duration) {
+	retryDelay := time.Second
+	retrying := false
+	for {
+		_, expiresAt := token.get()
+		if expiresAt.IsZero() {
+			return // 令牌不过期
+		}
+
+		if !retrying {
+			wait := time.Until(expiresAt.Add(-lead))
+			if wait < minTokenRefreshInterval {
+				wait = max(time.Until(expiresAt)/2, minTokenRefreshInterval)
+			}
+			if sleepContext(ctx, wait) != nil {
+				return
+			}
+		}
+		retrying = false
+
+		fresh, err := s.client.RefreshMailboxToken(withSessionToken(ctx, token), s.Address())
+		if err == nil {
+			token.set(fresh)
+			if fresh.ExpiresAt.IsZero() || fresh.ExpiresAt.After(expiresAt) {
+				retryDelay = time.Second
+				continue
+			}
+			// 新令牌没有延长有效期，放慢刷新
+			s.client.log(ctx, LogEntry{Level: LogWarn, Message: "refreshed mailbox token does not extend expiry"})
+			if sleepContext(ctx, retryDelay) != nil {
+				return
+			}
+			if retryDelay *= 2; retryDelay > maxTokenRetryDelay {
+				retryDelay = maxTokenRetryDelay
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.client.log(ctx, LogEntry{Level: LogWarn, Message: "refresh mailbox token failed", Err: err})
+		if !time.Now().Before(expiresAt) {
+			// 令牌已过期，刷新请求也无法再认证
+			return
+		}
+		if sleepContext(ctx, retryDelay) != nil {
+			return
+		}
+		if retryDelay *= 2; retryDelay > maxTokenRetryDelay {
+			retryDelay = maxTokenRetryDelay
+		}
+		// 下一轮立即重试，不再等待刷新时间
+		retrying = true
+	}
+}
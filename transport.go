@@ -0,0 +1,46 @@
+package mail2sdk
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// sharedTransport 所有未自定义传输层的客户端共享的传输层
+//
+// 包级函数每次调用都会创建新的 Client，共享传输层让它们复用同一个连接池，
+// 连续请求不必每次重新建立 TCP 连接和 TLS 握手。
+var sharedTransport http.RoundTripper = newSharedTransport()
+
+// newSharedTransport 创建保持长连接的传输层
+//
+// 默认传输层每个主机只保留 2 个空闲连接，并发轮询时大部分请求仍要新建连接，
+// 这里调高到 32 个。
+func newSharedTransport() *http.Transport {
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		t = t.Clone()
+		t.MaxIdleConns = 128
+		t.MaxIdleConnsPerHost = 32
+		return t
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          128,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// CloseIdleConnections 关闭客户端传输层中的空闲连接
+//
+// 使用共享传输层时会影响所有未自定义传输层的客户端，通常只需在程序退出前调用。
+func (c *Client) CloseIdleConnections() {
+	c.httpClient.CloseIdleConnections()
+}
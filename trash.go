@@ -0,0 +1,126 @@
+package mail2sdk
+
+import (
+	"context"
+	"time"
+)
+
+// TrashedMail 回收站中的邮件
+type TrashedMail struct {
+	Mail
+	TrashedAt time.Time `json:"trashed_at"` // 移入回收站的时间
+}
+
+// TrashMail 将邮件移入回收站
+//
+// 移入回收站的邮件不再出现在 GetMails 中，可以通过 RestoreMail 恢复。
+// 服务端不支持回收站时返回 ErrNotSupportedByServer。
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   mailID: 邮件 ID
+//
+// 返回:
+//   error: 错误信息
+func (c *Client) TrashMail(ctx context.Context, address, mailID string) error {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return err
+	}
+	escapedID, err := escapePathSegment("mailID", mailID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.requireFeature(ctx, FeatureTrash); err != nil {
+		return err
+	}
+
+	path := "/api/mailbox/" + escaped + "/mails/" + escapedID + "/trash"
+	if err := c.do(ctx, "POST", path, nil, nil); err != nil {
+		return err
+	}
+
+	// 邮件已不在收件箱中，移除缓存的详情
+	if cache := getDetailCache(); cache != nil {
+		cache.removeMail(c.baseURL, address, mailID)
+	}
+
+	return nil
+}
+
+// RestoreMail 将回收站中的邮件恢复到收件箱
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   mailID: 邮件 ID
+//
+// 返回:
+//   error: 错误信息
+//
+// 示例:
+//   trashed, _ := client.ListTrash(ctx, address)
+//   for _, m := range trashed {
+//       client.RestoreMail(ctx, address, m.ID)
+//   }
+func (c *Client) RestoreMail(ctx context.Context, address, mailID string) error {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return err
+	}
+	escapedID, err := escapePathSegment("mailID", mailID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.requireFeature(ctx, FeatureTrash); err != nil {
+		return err
+	}
+
+	path := "/api/mailbox/" + escaped + "/trash/" + escapedID + "/restore"
+	return c.do(ctx, "POST", path, nil, nil)
+}
+
+// ListTrash 获取邮箱回收站中的邮件列表
+//
+// 参数:
+//   ctx: 上下文
+//   address: 邮箱地址
+//   opts: 可选的调用参数（如 WithSince、WithLimit）
+//
+// 返回:
+//   []TrashedMail: 回收站中的邮件
+//   error: 错误信息
+func (c *Client) ListTrash(ctx context.Context, address string, opts ...CallOption) ([]TrashedMail, error) {
+	escaped, err := escapePathSegment("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.requireFeature(ctx, FeatureTrash); err != nil {
+		return nil, err
+	}
+
+	o := newCallOptions(opts)
+	path := "/api/mailbox/" + escaped + "/trash" + o.query()
+
+	var result struct {
+		Count int           `json:"count"`
+		Mails []TrashedMail `json:"mails"`
+	}
+
+	ctx = withMailboxRead(ctx)
+	if err := c.do(ctx, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	trashed := result.Mails[:0]
+	for _, mail := range result.Mails {
+		if o.keep(mail.ReceivedAt) && (o.limit <= 0 || len(trashed) < o.limit) {
+			trashed = append(trashed, mail)
+		}
+	}
+	return trashed, nil
+}
@@ -0,0 +1,183 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WaitCodeOptions 等待验证码配置
+type WaitCodeOptions struct {
+	// Timeout 每一轮等待的时长，超时后调用 Resend 重新发送（<= 0 表示只等待一轮，直到 ctx 结束）
+	Timeout time.Duration
+
+	// Resend 重新发送验证码（如调用目标站点的"重新发送"接口），可选
+	Resend func() error
+
+	// MaxResends 最多重新发送的次数
+	MaxResends int
+
+	// Since 忽略该时间之前收到的邮件（零值表示不忽略），避免取到上一次流程的旧验证码
+	Since time.Time
+
+	// Matcher 只从满足条件的邮件中提取（可选）
+	Matcher MailMatcher
+
+	// IncludeSpam 同时检查垃圾邮件文件夹（服务端不支持文件夹时忽略）。在垃圾邮件中找到
+	// 验证码时触发 EventCodeInSpam 事件
+	IncludeSpam bool
+
+	// RescueSpam 在垃圾邮件中找到验证码后调用 RescueFromSpam 将邮件移回收件箱，
+	// 向服务端反馈误判（失败不影响返回结果）
+	RescueSpam bool
+
+	// CodePattern 使用以该名称注册的正则提取验证码（见 RegisterPattern），为空时使用内置规则。
+	// 正则包含名为 code 的分组时取该分组，否则取第一个分组或整个匹配
+	CodePattern string
+}
+
+// WaitForCode 等待包含验证码的邮件并提取验证码
+//
+// 每一轮最多等待 Timeout，没有收到验证码时调用 Resend 重新发送，最多重发
+// MaxResends 次后放弃，相当于把人工"没收到？点击重新发送"的处理写进流程。
+// 验证码在本地提取并评分（见 ExtractCodeLocal），设置了 OCRProvider 时同样会识别图片。
+//
+// 参数:
+//   ctx: 上下文（整体超时）
+//   address: 邮箱地址
+//   opts: 可选配置（传 nil 表示等待到 ctx 结束，不重发）
+//
+// 返回:
+//   *CodeResult: 提取结果（LatestMailID 为包含验证码的邮件）
+//   error: 错误信息（Resend 失败时包含其错误）
+//
+// 示例:
+//   result, err := client.WaitForCode(ctx, address, &mail2sdk.WaitCodeOptions{
+//       Timeout:    30 * time.Second,
+//       MaxResends: 2,
+//       Resend:     func() error { return site.ResendCode(address) },
+//       Since:      time.Now(),
+//   })
+func (c *Client) WaitForCode(ctx context.Context, address string, opts *WaitCodeOptions) (*CodeResult, error) {
+	var o WaitCodeOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	var pattern *regexp.Regexp
+	if o.CodePattern != "" {
+		var err error
+		if pattern, err = c.Pattern(o.CodePattern); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		codes []string
+		text  string
+	)
+	matcher := MatcherFunc(func(detail *MailDetail) bool {
+		if !o.Since.IsZero() && detail.ReceivedAt.Before(o.Since) {
+			return false
+		}
+		if o.Matcher != nil && !o.Matcher.Match(detail) {
+			return false
+		}
+		codes, text = c.mailCodes(ctx, address, detail, pattern)
+		return len(codes) > 0
+	})
+
+	for resends := 0; ; resends++ {
+		detail, err := c.waitRound(ctx, address, matcher, o.Timeout, o.IncludeSpam)
+
+		if err == nil {
+			if detail.Folder == FolderSpam {
+				c.codeInSpam(ctx, address, detail.ID, o.RescueSpam)
+			}
+			result := &CodeResult{
+				Found:        true,
+				AllCodes:     codes,
+				CheckedMails: 1,
+				LatestMailID: detail.ID,
+				Candidates:   RankCodes(codes, text),
+			}
+			result.Code = result.Candidates[0].Code
+			return result, nil
+		}
+
+		// 整体 ctx 结束、预算耗尽或不能再重发时放弃
+		if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) ||
+			o.Resend == nil || resends >= o.MaxResends {
+			if errors.Is(err, context.DeadlineExceeded) {
+				c.coolDomainOf(ctx, address, err)
+			}
+			if resends > 0 {
+				return nil, fmt.Errorf("no code received after %d resends: %w", resends, err)
+			}
+			return nil, err
+		}
+
+		if err := o.Resend(); err != nil {
+			return nil, fmt.Errorf("resend code failed: %w", err)
+		}
+	}
+}
+
+// waitRound 等待一轮（timeout <= 0 表示等待到 ctx 结束）
+func (c *Client) waitRound(ctx context.Context, address string, matcher MailMatcher, timeout time.Duration, includeSpam bool) (*MailDetail, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return c.waitForMail(ctx, address, matcher, includeSpam)
+}
+
+// codeInSpam 报告在垃圾邮件中找到的验证码，rescue 为 true 时将邮件移回收件箱
+func (c *Client) codeInSpam(ctx context.Context, address, mailID string, rescue bool) {
+	var err error
+	if rescue {
+		if err = c.RescueFromSpam(ctx, address, mailID); err != nil {
+			err = fmt.Errorf("rescue from spam failed: %w", err)
+		}
+	}
+	c.emit(ctx, ClientEvent{
+		Type:    EventCodeInSpam,
+		Domain:  addressDomain(strings.ToLower(address)),
+		Address: address,
+		MailID:  mailID,
+		Err:     err,
+	})
+}
+
+// WithDomainCooldown 等待验证码超时后让该邮箱的域名进入冷却期
+//
+// WaitForCode 最终超时（包括重发之后）时，邮箱所在域名在 window 内不再被自动选择，
+// 之后的 ramp 时间内逐步恢复，用于自动缓解目标服务对某些域名的临时屏蔽。
+// 域名进入冷却期时触发 EventDomainCooling 事件。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithDomainCooldown(30*time.Minute, 30*time.Minute))
+func WithDomainCooldown(window, ramp time.Duration) Option {
+	return func(c *Client) {
+		c.cooldownWindow = window
+		c.cooldownRamp = ramp
+	}
+}
+
+// coolDomainOf 让邮箱所在域名进入冷却期（未配置 WithDomainCooldown 时忽略）
+func (c *Client) coolDomainOf(ctx context.Context, address string, err error) {
+	if c.cooldownWindow <= 0 {
+		return
+	}
+	domain := addressDomain(strings.ToLower(address))
+	if domain == "" {
+		return
+	}
+	if c.selector.cool(domain, c.cooldownWindow, c.cooldownRamp) {
+		c.emit(ctx, ClientEvent{Type: EventDomainCooling, Domain: domain, Err: err})
+	}
+}
@@ -0,0 +1,164 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 默认轮询间隔
+const defaultPollInterval = 3 * time.Second
+
+// WatchOptions 邮箱监听配置
+type WatchOptions struct {
+	Interval        time.Duration // 轮询间隔（<= 0 表示 Client.PollInterval，不会短于服务端建议的间隔）
+	IncludeExisting bool          // 为 true 时开始监听前已有的邮件也会作为事件发出
+	Buffer          int           // 事件通道缓冲大小（<= 0 表示 16）
+	IncludeSpam     bool          // 为 true 时同时监听垃圾邮件文件夹（服务端不支持文件夹时忽略，见 Mail.Folder）
+}
+
+// MailEvent 邮箱监听事件
+type MailEvent struct {
+	Address string // 邮箱地址
+	Mail    Mail   // 新邮件（Err 不为 nil 时为空）
+	Err     error  // 某次轮询失败的错误（监听会继续进行）
+}
+
+// Watch 持续监听邮箱，收到新邮件时通过通道发出事件
+//
+// 内部按固定间隔轮询 GetMails（见 Client.PollInterval），每封邮件只会发出一次。轮询失败不会终止监听，
+// 而是发出一个 Err 不为 nil 的事件。ctx 被取消后通道会被关闭。ctx 携带 Budget 时监听期间
+// 的时间都计入预算，预算用完时发出一个 ErrBudgetExceeded 事件后关闭通道。
+// 配置了 WithNoiseFilter 时，噪音发件人的邮件不会发出事件。
+//
+// 参数:
+//   ctx: 上下文（取消后停止监听）
+//   address: 邮箱地址
+//   opts: 可选配置（传 nil 使用默认值）
+//
+// 返回:
+//   <-chan MailEvent: 事件通道
+//   error: 参数错误或耗时预算已用完
+//
+// 示例:
+//   events, _ := client.Watch(ctx, mailbox.Address, nil)
+//   for ev := range events {
+//       if ev.Err != nil {
+//           log.Printf("轮询失败: %v", ev.Err)
+//           continue
+//       }
+//       fmt.Println("新邮件:", ev.Mail.Subject)
+//   }
+func (c *Client) Watch(ctx context.Context, address string, opts *WatchOptions) (<-chan MailEvent, error) {
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+
+	var o WatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Buffer <= 0 {
+		o.Buffer = 16
+	}
+
+	// 监听期间（包括轮询间隔）计入耗时预算
+	ctx, finish, err := beginBudget(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := c.acquireWatcher(ctx)
+	if err != nil {
+		finish(err)
+		return nil, err
+	}
+
+	// 客户端关闭时停止监听并关闭事件通道
+	ctx, stop := c.bindLifetime(ctx)
+	events := make(chan MailEvent, o.Buffer)
+	go func() {
+		defer finish(nil)
+		defer release()
+		defer stop()
+		c.watchLoop(ctx, address, o, events)
+	}()
+	return events, nil
+}
+
+// watchLoop 轮询邮件列表并发出新邮件事件
+func (c *Client) watchLoop(ctx context.Context, address string, opts WatchOptions, events chan<- MailEvent) {
+	defer close(events)
+
+	seen := make(map[string]bool)
+	tally := c.senders.watch(address)
+	first := true
+	spamOff := false
+
+	for {
+		// 垃圾邮件获取失败时仍会返回收件箱的邮件
+		mails, err := c.watchMails(ctx, address, opts, &spamOff)
+		if err != nil {
+			if ctx.Err() != nil {
+				sendBudgetExceeded(ctx, events, address)
+				return
+			}
+			if !sendEvent(ctx, events, MailEvent{Address: address, Err: err}) {
+				return
+			}
+			// 预算用完或客户端关闭后继续轮询也只会立即失败
+			if stopPolling(err) {
+				return
+			}
+		}
+		if err == nil || mails != nil {
+			for _, mail := range mails {
+				if seen[mail.ID] {
+					continue
+				}
+				seen[mail.ID] = true
+				tally.observe(mail)
+
+				// 首次轮询看到的邮件视为已有邮件
+				if first && !opts.IncludeExisting {
+					continue
+				}
+				if c.noiseFilter.isNoise(mail.From) {
+					continue
+				}
+				if !sendEvent(ctx, events, MailEvent{Address: address, Mail: mail}) {
+					return
+				}
+			}
+			first = false
+		}
+
+		// 每轮重新计算间隔，服务端调整建议间隔后立即生效
+		if sleepContext(ctx, c.pollInterval(opts.Interval)) != nil {
+			sendBudgetExceeded(ctx, events, address)
+			return
+		}
+	}
+}
+
+// sendBudgetExceeded ctx 因耗时预算用完而结束时发出最后一个错误事件（通道已满时放弃）
+func sendBudgetExceeded(ctx context.Context, events chan<- MailEvent, address string) {
+	if !errors.Is(context.Cause(ctx), ErrBudgetExceeded) {
+		return
+	}
+	select {
+	case events <- MailEvent{Address: address, Err: budgetFrom(ctx).exceeded()}:
+	default:
+	}
+}
+
+// sendEvent 发送事件，ctx 被取消时返回 false
+func sendEvent(ctx context.Context, events chan<- MailEvent, ev MailEvent) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
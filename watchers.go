@@ -0,0 +1,81 @@
+package mail2sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrTooManyWatchers 表示同时进行的监听和等待操作超过了上限且排队已满
+var ErrTooManyWatchers = errors.New("too many active watchers")
+
+// watcherLimit 限制同时进行的 Watch / WaitFor 操作
+type watcherLimit struct {
+	slots   chan struct{} // 容量为同时进行的上限
+	queue   int64         // 允许排队等待的操作数
+	waiting int64         // 正在排队的操作数
+}
+
+// WithMaxWatchers 限制同时进行的监听和等待操作数量
+//
+// Watch、WaitForMailMatching、WaitForCode、WaitForCodeOrLink 等操作都会持续轮询
+// 服务端，数量失控时会同时拖垮客户端进程和服务端。设置后同时进行的操作最多为 max 个，
+// 超出的操作排队等待空位（最多 queue 个，等待期间 ctx 结束则返回 ctx.Err()），
+// 排队也已满时立即返回 ErrTooManyWatchers。max <= 0 表示不限制。
+//
+// 示例:
+//   client := mail2sdk.NewClient(baseURL, apiKey, mail2sdk.WithMaxWatchers(50, 200))
+//   _, err := client.WaitForCode(ctx, address, nil)
+//   if errors.Is(err, mail2sdk.ErrTooManyWatchers) {
+//       // 稍后重试或降级处理
+//   }
+func WithMaxWatchers(max, queue int) Option {
+	return func(c *Client) {
+		if max <= 0 {
+			c.watchers = nil
+			return
+		}
+		if queue < 0 {
+			queue = 0
+		}
+		c.watchers = &watcherLimit{slots: make(chan struct{}, max), queue: int64(queue)}
+	}
+}
+
+// ActiveWatchers 返回正在进行和排队中的监听、等待操作数量（未设置上限时均为 0）
+func (c *Client) ActiveWatchers() (active, queued int) {
+	w := c.settings().watchers
+	if w == nil {
+		return 0, 0
+	}
+	return len(w.slots), int(atomic.LoadInt64(&w.waiting))
+}
+
+// acquireWatcher 占用一个监听名额，返回释放名额的函数
+func (c *Client) acquireWatcher(ctx context.Context) (release func(), err error) {
+	w := c.settings().watchers
+	if w == nil {
+		return func() {}, nil
+	}
+
+	release = func() { <-w.slots }
+	select {
+	case w.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if atomic.AddInt64(&w.waiting, 1) > w.queue {
+		atomic.AddInt64(&w.waiting, -1)
+		return nil, fmt.Errorf("%w: %d active, queue of %d is full", ErrTooManyWatchers, cap(w.slots), w.queue)
+	}
+	defer atomic.AddInt64(&w.waiting, -1)
+
+	select {
+	case w.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,406 @@
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chuyu5762/mail2sdk"
+)
+
+// Store 中使用的键前缀
+const (
+	keyPending = "webhook/pending/" // 等待处理（或等待重试）的事件
+	keyDone    = "webhook/done/"    // 已处理的事件（用于去重）
+	keyDead    = "webhook/dead/"    // 超过最大重试次数的事件
+)
+
+// 请求体大小上限
+const maxBodySize = 1 << 20
+
+// HandlerFunc 用户的事件处理函数
+//
+// 返回 nil 表示处理成功；返回错误时事件会保留在 Store 中并按退避策略重试。
+// 同一事件可能被处理多次（至少一次语义），处理函数应当是幂等的。
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// ConsumerOptions 消费者配置
+type ConsumerOptions struct {
+	Secret        string        // Webhook 密钥（为空表示不校验签名，不推荐）
+	Tolerance     time.Duration // 签名时间戳允许的偏差（<= 0 表示 DefaultTolerance）
+	MaxAttempts   int           // 最大处理次数（<= 0 表示 10），超过后转入死信
+	BaseDelay     time.Duration // 首次重试等待时间（<= 0 表示 1s，之后指数增长）
+	MaxDelay      time.Duration // 单次重试等待上限（<= 0 表示 5m）
+	PollInterval  time.Duration // Run 检查待重试事件的间隔（<= 0 表示 1s）
+	DoneRetention time.Duration // 已处理事件去重记录的保留时间（<= 0 表示 24h）
+
+	// Codec 事件记录写入 Store 时使用的编码（nil 表示 JSON）。大量事件积压时可以用
+	// mail2sdk.GzipCodec(mail2sdk.GobCodec) 减少占用，切换编码前应先处理完积压的事件。
+	Codec mail2sdk.Codec
+
+	// OnError 处理失败时的回调（可选），dead 为 true 表示事件已转入死信。
+	//
+	// 无法解析的事件以 *UnknownEvent 传入（Data 为完整的原始请求体），并直接转入死信；
+	// Store 中无法解码的记录同样以 *UnknownEvent 传入（Data 为原始记录）并原样转入死信；
+	// 与具体事件无关的错误（如 Run 读取 Store 失败）event 为 nil。
+	OnError func(event Event, err error, dead bool)
+}
+
+// record 持久化在 Store 中的事件记录
+type record struct {
+	ID          string          `json:"id"`
+	Body        json.RawMessage `json:"body"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// Consumer 持久化的 Webhook 消费者
+//
+// 收到的事件先写入 Store，只有在用户处理函数成功后才向服务端返回 200；
+// 处理失败的事件会留在 Store 中，由 Run 按指数退避重试，避免处理代码的
+// 短暂故障导致验证通知丢失。
+//
+// 示例:
+//   store, _ := mail2sdk.NewFileStore("/var/lib/myapp/webhooks")
+//   consumer := webhook.NewConsumer(store, func(ctx context.Context, e webhook.Event) error {
+//       if m, ok := e.(*webhook.MailReceived); ok {
+//           return handleMail(ctx, m)
+//       }
+//       return nil
+//   }, webhook.ConsumerOptions{Secret: secret})
+//
+//   go consumer.Run(ctx)
+//   http.Handle("/webhooks/mail2", consumer)
+type Consumer struct {
+	store   mail2sdk.Store
+	handler HandlerFunc
+	opts    ConsumerOptions
+
+	mu       sync.Mutex
+	inflight map[string]bool // 正在处理中的事件 ID
+}
+
+// NewConsumer 创建 Webhook 消费者
+func NewConsumer(store mail2sdk.Store, handler HandlerFunc, opts ConsumerOptions) *Consumer {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = time.Second
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 5 * time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.DoneRetention <= 0 {
+		opts.DoneRetention = 24 * time.Hour
+	}
+	if opts.Codec == nil {
+		opts.Codec = mail2sdk.JSONCodec
+	}
+	return &Consumer{
+		store:    store,
+		handler:  handler,
+		opts:     opts,
+		inflight: make(map[string]bool),
+	}
+}
+
+// ServeHTTP 接收 Webhook 推送（实现 http.Handler 接口）
+//
+// 响应码含义：
+//   200: 事件已处理（或之前已处理过）
+//   401: 签名校验失败
+//   400: 请求体格式错误
+//   500: 事件已持久化但处理失败，将在本地重试（服务端也可以重新推送）
+func (c *Consumer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+
+	if c.opts.Secret != "" {
+		if err := VerifySignatureWithTolerance(r.Header.Get(SignatureHeader), body, c.opts.Secret, c.opts.Tolerance); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	id := eventKey(event, body)
+
+	// 已处理过的重复推送直接确认
+	if _, err := c.store.Get(ctx, keyDone+id); err == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	rec, err := c.load(ctx, id)
+	if errors.Is(err, mail2sdk.ErrNotFound) {
+		rec = &record{ID: id, Body: body, NextAttempt: time.Now()}
+		err = c.save(ctx, rec)
+	}
+	if err != nil {
+		http.Error(w, "persist event failed", http.StatusInternalServerError)
+		return
+	}
+
+	if err := c.process(ctx, rec); err != nil {
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Run 持续重试 Store 中处理失败的事件，直到 ctx 被取消
+//
+// 进程重启后调用 Run 会继续处理上次遗留的事件。
+func (c *Consumer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.PollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for {
+		if err := c.retryDue(ctx); err != nil && ctx.Err() == nil && c.opts.OnError != nil {
+			c.opts.OnError(nil, err, false)
+		}
+
+		if time.Since(lastPrune) > time.Hour {
+			c.pruneDone(ctx)
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Pending 返回当前等待重试的事件数量
+func (c *Consumer) Pending(ctx context.Context) (int, error) {
+	keys, err := c.store.List(ctx, keyPending)
+	return len(keys), err
+}
+
+// retryDue 处理所有到达重试时间的事件
+func (c *Consumer) retryDue(ctx context.Context) error {
+	keys, err := c.store.List(ctx, keyPending)
+	if err != nil {
+		return fmt.Errorf("list pending events failed: %w", err)
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return nil
+		}
+		id := strings.TrimPrefix(key, keyPending)
+		data, err := c.store.Get(ctx, key)
+		if err != nil {
+			// 已被并发的推送处理完成时直接跳过，其他读取错误留待下一轮重试
+			if !errors.Is(err, mail2sdk.ErrNotFound) && c.opts.OnError != nil {
+				c.opts.OnError(nil, fmt.Errorf("read event %s failed: %w", id, err), false)
+			}
+			continue
+		}
+		rec, err := c.decode(data)
+		if err != nil {
+			// 损坏的记录不会因为重试而变好，原样转入死信，避免永远留在待处理列表中
+			c.moveRawToDead(ctx, id, data)
+			if c.opts.OnError != nil {
+				c.opts.OnError(&UnknownEvent{Meta: Meta{ID: id}, Data: data}, err, true)
+			}
+			continue
+		}
+		if rec.NextAttempt.After(now) {
+			continue
+		}
+		// 错误已通过 OnError 上报并记录在 Store 中
+		c.process(ctx, rec)
+	}
+	return nil
+}
+
+// process 调用用户处理函数并根据结果更新 Store
+func (c *Consumer) process(ctx context.Context, rec *record) error {
+	if !c.acquire(rec.ID) {
+		return fmt.Errorf("event %s is being processed", rec.ID)
+	}
+	defer c.release(rec.ID)
+
+	event, err := ParseEvent(rec.Body)
+	if err != nil {
+		// 无法解析的事件不会因为重试而变好，直接转入死信
+		c.moveToDead(ctx, rec, err)
+		if c.opts.OnError != nil {
+			c.opts.OnError(&UnknownEvent{Meta: Meta{ID: rec.ID}, Data: rec.Body}, err, true)
+		}
+		return err
+	}
+
+	rec.Attempts++
+	handlerErr := c.safeHandle(ctx, event)
+	if handlerErr == nil {
+		if err := c.store.Put(ctx, keyDone+rec.ID, []byte(time.Now().UTC().Format(time.RFC3339))); err != nil {
+			return fmt.Errorf("mark event done failed: %w", err)
+		}
+		return c.store.Delete(ctx, keyPending+rec.ID)
+	}
+
+	rec.LastError = handlerErr.Error()
+	if rec.Attempts >= c.opts.MaxAttempts {
+		c.moveToDead(ctx, rec, handlerErr)
+		if c.opts.OnError != nil {
+			c.opts.OnError(event, handlerErr, true)
+		}
+		return handlerErr
+	}
+
+	rec.NextAttempt = time.Now().Add(c.backoff(rec.Attempts))
+	if err := c.save(ctx, rec); err != nil {
+		return fmt.Errorf("save event failed: %w", err)
+	}
+	if c.opts.OnError != nil {
+		c.opts.OnError(event, handlerErr, false)
+	}
+	return handlerErr
+}
+
+// safeHandle 调用处理函数并把 panic 转换为错误
+func (c *Consumer) safeHandle(ctx context.Context, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return c.handler(ctx, event)
+}
+
+// backoff 计算第 attempts 次失败后的等待时间
+func (c *Consumer) backoff(attempts int) time.Duration {
+	d := c.opts.BaseDelay
+	for i := 1; i < attempts && d < c.opts.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > c.opts.MaxDelay {
+		d = c.opts.MaxDelay
+	}
+	return d
+}
+
+// moveToDead 将事件转入死信
+func (c *Consumer) moveToDead(ctx context.Context, rec *record, cause error) {
+	rec.LastError = cause.Error()
+	if data, err := c.opts.Codec.Marshal(rec); err == nil {
+		c.store.Put(ctx, keyDead+rec.ID, data)
+	}
+	c.store.Delete(ctx, keyPending+rec.ID)
+}
+
+// moveRawToDead 将无法解码的事件记录原样转入死信
+func (c *Consumer) moveRawToDead(ctx context.Context, id string, data []byte) {
+	if err := c.store.Put(ctx, keyDead+id, data); err == nil {
+		c.store.Delete(ctx, keyPending+id)
+	}
+}
+
+// pruneDone 清理过期的去重记录
+func (c *Consumer) pruneDone(ctx context.Context) {
+	keys, err := c.store.List(ctx, keyDone)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-c.opts.DoneRetention)
+	for _, key := range keys {
+		value, err := c.store.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+		doneAt, err := time.Parse(time.RFC3339, string(value))
+		if err != nil || doneAt.Before(cutoff) {
+			c.store.Delete(ctx, key)
+		}
+	}
+}
+
+// load 读取事件记录
+func (c *Consumer) load(ctx context.Context, id string) (*record, error) {
+	data, err := c.store.Get(ctx, keyPending+id)
+	if err != nil {
+		return nil, err
+	}
+	return c.decode(data)
+}
+
+// decode 解码事件记录
+func (c *Consumer) decode(data []byte) (*record, error) {
+	var rec record
+	if err := c.opts.Codec.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("decode event record failed: %w", err)
+	}
+	return &rec, nil
+}
+
+// save 写入事件记录
+func (c *Consumer) save(ctx context.Context, rec *record) error {
+	data, err := c.opts.Codec.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return c.store.Put(ctx, keyPending+rec.ID, data)
+}
+
+// acquire 标记事件为处理中，已在处理中时返回 false
+func (c *Consumer) acquire(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inflight[id] {
+		return false
+	}
+	c.inflight[id] = true
+	return true
+}
+
+// release 取消事件的处理中标记
+func (c *Consumer) release(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inflight, id)
+}
+
+// eventKey 返回事件的去重键（事件没有 ID 时使用请求体的哈希）
+func eventKey(event Event, body []byte) string {
+	if id := event.EventID(); id != "" {
+		return id
+	}
+	sum := sha256.Sum256(body)
+	return "sha256-" + hex.EncodeToString(sum[:16])
+}
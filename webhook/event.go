@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 事件类型
+const (
+	TypeMailReceived   = "mail.received"
+	TypeMailboxCreated = "mailbox.created"
+	TypeMailboxExpired = "mailbox.expired"
+	TypeQuotaWarning   = "quota.warning"
+)
+
+// ErrInvalidEvent 事件格式错误
+var ErrInvalidEvent = errors.New("webhook: invalid event payload")
+
+// Event 所有 Webhook 事件的公共接口
+//
+// 使用类型断言或 type switch 获取具体事件：
+//
+//   switch e := event.(type) {
+//   case *webhook.MailReceived:
+//       fmt.Println("新邮件:", e.Address, e.Subject)
+//   case *webhook.QuotaWarning:
+//       fmt.Printf("配额已用 %d/%d\n", e.Used, e.Limit)
+//   }
+type Event interface {
+	EventID() string       // 事件 ID（用于去重）
+	EventType() string     // 事件类型
+	OccurredAt() time.Time // 事件发生时间
+}
+
+// Meta 事件公共字段
+type Meta struct {
+	ID        string    // 事件 ID
+	Type      string    // 事件类型
+	CreatedAt time.Time // 事件发生时间
+}
+
+// EventID 实现 Event 接口
+func (m Meta) EventID() string { return m.ID }
+
+// EventType 实现 Event 接口
+func (m Meta) EventType() string { return m.Type }
+
+// OccurredAt 实现 Event 接口
+func (m Meta) OccurredAt() time.Time { return m.CreatedAt }
+
+// MailReceived 邮箱收到新邮件
+type MailReceived struct {
+	Meta       `json:"-"`
+	Address    string    `json:"email"`       // 收件邮箱
+	MailID     string    `json:"mail_id"`     // 邮件 ID（可用于 GetMailDetail）
+	From       string    `json:"from"`        // 发件人
+	Subject    string    `json:"subject"`     // 主题
+	ReceivedAt time.Time `json:"received_at"` // 接收时间
+}
+
+// MailboxCreated 邮箱已创建
+type MailboxCreated struct {
+	Meta      `json:"-"`
+	Address   string    `json:"email"`      // 邮箱地址
+	Domain    string    `json:"domain"`     // 域名
+	ExpiresAt time.Time `json:"expires_at"` // 过期时间
+}
+
+// MailboxExpired 邮箱已过期
+type MailboxExpired struct {
+	Meta      `json:"-"`
+	Address   string    `json:"email"`      // 邮箱地址
+	ExpiredAt time.Time `json:"expired_at"` // 过期时间
+}
+
+// QuotaWarning 配额即将用尽
+type QuotaWarning struct {
+	Meta   `json:"-"`
+	Used   int    `json:"used"`   // 已用量
+	Limit  int    `json:"limit"`  // 配额上限
+	Period string `json:"period"` // 配额周期（如 "daily"）
+}
+
+// UnknownEvent 未识别的事件类型（新版本服务端新增的事件）
+type UnknownEvent struct {
+	Meta
+	Data json.RawMessage // 原始 data 字段
+}
+
+// envelope Webhook 请求体的外层结构
+type envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ParseEvent 将 Webhook 请求体解析为具体的事件类型
+//
+// 未识别的事件类型返回 *UnknownEvent 而不是错误，便于服务端新增事件时向前兼容。
+//
+// 参数:
+//   body: 原始请求体（建议先通过 VerifySignature 校验）
+//
+// 返回:
+//   Event: *MailReceived / *MailboxCreated / *MailboxExpired / *QuotaWarning / *UnknownEvent
+//   error: 错误信息
+func ParseEvent(body []byte) (Event, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if env.Type == "" {
+		return nil, fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	}
+
+	meta := Meta{ID: env.ID, Type: env.Type, CreatedAt: env.CreatedAt}
+
+	var event Event
+	var target interface{}
+	switch env.Type {
+	case TypeMailReceived:
+		e := &MailReceived{Meta: meta}
+		event, target = e, e
+	case TypeMailboxCreated:
+		e := &MailboxCreated{Meta: meta}
+		event, target = e, e
+	case TypeMailboxExpired:
+		e := &MailboxExpired{Meta: meta}
+		event, target = e, e
+	case TypeQuotaWarning:
+		e := &QuotaWarning{Meta: meta}
+		event, target = e, e
+	default:
+		return &UnknownEvent{Meta: meta, Data: env.Data}, nil
+	}
+
+	if len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, target); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidEvent, env.Type, err)
+		}
+	}
+	return event, nil
+}
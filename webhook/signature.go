@@ -0,0 +1,154 @@
+// Package webhook 提供 Mail2 Webhook 的签名校验与事件解析
+//
+// Mail2 服务端推送 Webhook 时会在 X-Mail2-Signature 请求头中携带签名：
+//
+//   X-Mail2-Signature: t=1700000000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+//
+// 其中 t 为签名时间戳（Unix 秒），v1 为 HMAC-SHA256(secret, t + "." + body) 的十六进制编码。
+// 轮换密钥期间服务端可能同时携带多个 v1，任意一个匹配即视为有效。
+//
+// 使用示例:
+//   body, _ := io.ReadAll(r.Body)
+//   if err := webhook.VerifySignature(r.Header.Get(webhook.SignatureHeader), body, secret); err != nil {
+//       http.Error(w, "invalid signature", http.StatusUnauthorized)
+//       return
+//   }
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader 携带签名的请求头名称
+const SignatureHeader = "X-Mail2-Signature"
+
+// DefaultTolerance 默认允许的时间戳偏差（防止重放攻击）
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidHeader 签名请求头缺失或格式错误
+	ErrInvalidHeader = errors.New("webhook: invalid signature header")
+	// ErrNoValidSignature 没有任何签名与请求体匹配
+	ErrNoValidSignature = errors.New("webhook: no valid signature found")
+	// ErrTimestampOutOfTolerance 签名时间戳超出允许的偏差范围
+	ErrTimestampOutOfTolerance = errors.New("webhook: timestamp outside tolerance")
+)
+
+// now 当前时间（便于替换）
+var now = time.Now
+
+// VerifySignature 使用默认时间偏差（5 分钟）校验 Webhook 签名
+//
+// 参数:
+//   header: X-Mail2-Signature 请求头的值
+//   body: 原始请求体（必须是未经修改的原始字节）
+//   secret: Webhook 密钥
+//
+// 返回:
+//   error: 校验失败的原因（nil 表示校验通过）
+func VerifySignature(header string, body []byte, secret string) error {
+	return VerifySignatureWithTolerance(header, body, secret, DefaultTolerance)
+}
+
+// VerifySignatureWithTolerance 校验 Webhook 签名并指定允许的时间偏差
+//
+// tolerance <= 0 表示不检查时间戳（不推荐，会失去重放保护）。
+// 签名比较使用常量时间算法，避免时序攻击。
+func VerifySignatureWithTolerance(header string, body []byte, secret string, tolerance time.Duration) error {
+	timestamp, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	expected := computeSignature(timestamp, body, secret)
+	valid := false
+	for _, sig := range signatures {
+		// 不提前退出，保证耗时与匹配位置无关
+		if hmac.Equal(expected, sig) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrNoValidSignature
+	}
+
+	if tolerance > 0 {
+		diff := now().Sub(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrTimestampOutOfTolerance
+		}
+	}
+
+	return nil
+}
+
+// Sign 生成签名请求头的值
+//
+// 用于在测试中模拟服务端推送，或自建转发服务时重新签名。
+//
+// 示例:
+//   header := webhook.Sign(body, secret, time.Now())
+//   req.Header.Set(webhook.SignatureHeader, header)
+func Sign(body []byte, secret string, t time.Time) string {
+	timestamp := t.Unix()
+	sig := computeSignature(timestamp, body, secret)
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + hex.EncodeToString(sig)
+}
+
+// computeSignature 计算 HMAC-SHA256(secret, t + "." + body)
+func computeSignature(timestamp int64, body []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// parseHeader 解析签名请求头
+func parseHeader(header string) (int64, [][]byte, error) {
+	if header == "" {
+		return 0, nil, ErrInvalidHeader
+	}
+
+	var (
+		timestamp    int64
+		hasTimestamp bool
+		signatures   [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return 0, nil, ErrInvalidHeader
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			timestamp = ts
+			hasTimestamp = true
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				// 忽略无法解码的签名，其余签名仍可能有效
+				continue
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+
+	if !hasTimestamp || len(signatures) == 0 {
+		return 0, nil, ErrInvalidHeader
+	}
+	return timestamp, signatures, nil
+}